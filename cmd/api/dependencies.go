@@ -1,24 +1,47 @@
 package main
 
 import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"os"
+
+	"github.com/mrthoabby/portfolio-api/internal/access"
 	"github.com/mrthoabby/portfolio-api/internal/application/certificates"
 	"github.com/mrthoabby/portfolio-api/internal/application/contacts"
+	"github.com/mrthoabby/portfolio-api/internal/application/portfolio"
 	"github.com/mrthoabby/portfolio-api/internal/application/profile"
 	"github.com/mrthoabby/portfolio-api/internal/application/projects"
 	"github.com/mrthoabby/portfolio-api/internal/application/questions"
+	"github.com/mrthoabby/portfolio-api/internal/application/settings"
 	"github.com/mrthoabby/portfolio-api/internal/application/skills"
+	"github.com/mrthoabby/portfolio-api/internal/application/webhooks"
+	"github.com/mrthoabby/portfolio-api/internal/auth"
+	"github.com/mrthoabby/portfolio-api/internal/captcha"
 	"github.com/mrthoabby/portfolio-api/internal/common/contracts"
 	"github.com/mrthoabby/portfolio-api/internal/common/logger"
+	"github.com/mrthoabby/portfolio-api/internal/config"
 	"github.com/mrthoabby/portfolio-api/internal/health"
 	"github.com/mrthoabby/portfolio-api/internal/middleware"
+	"github.com/mrthoabby/portfolio-api/internal/notify"
+	"github.com/mrthoabby/portfolio-api/internal/outbox"
+	"github.com/mrthoabby/portfolio-api/internal/spam"
 )
 
 // Dependencies holds all application dependencies
 type Dependencies struct {
-	// Rate limiters
-	GlobalRateLimiter   *middleware.RateLimiter
-	ContactRateLimiter  *middleware.RateLimiter
-	QuestionRateLimiter *middleware.RateLimiter
+	// Authentication
+	Authenticator auth.Authenticator
+
+	// Rate limiting
+	RateLimiter *middleware.RateLimiter
+
+	// CAPTCHA challenges on public forms, disabled when CaptchaVerifier is nil
+	CaptchaVerifier captcha.Verifier
+	CaptchaProfiles captcha.ProfileSet
+
+	// Events published by the domains and delivered to their subscribers in the background
+	Outbox *outbox.Outbox
 
 	// Handlers
 	ProfileHandler      *profile.Handler
@@ -27,59 +50,355 @@ type Dependencies struct {
 	CertificatesHandler *certificates.Handler
 	ContactsHandler     *contacts.Handler
 	QuestionsHandler    *questions.Handler
+	MembersHandler      *access.Handler
+	SettingsHandler     *settings.Handler
+	WebhooksHandler     *webhooks.Handler
+	PortfolioHandler    *portfolio.Handler
 	HealthHandler       *health.Handler
 }
 
 // InitializeDependencies initializes all application dependencies
-func InitializeDependencies(dataSource contracts.DataSource, appLogger logger.Logger) *Dependencies {
-	// Initialize rate limiters
-	globalRateLimiter := middleware.NewRateLimiter(rateLimitRequests, rateLimitWindow)
-	contactRateLimiter := middleware.NewRateLimiter(contactRateLimit, contactRateWindow)
-	questionRateLimiter := middleware.NewRateLimiter(questionRateLimit, questionRateWindow)
+func InitializeDependencies(dataSource contracts.DataSource, appLogger logger.Logger, cfg *config.Config) (*Dependencies, error) {
+	// Initialize authentication
+	authenticator, err := newAuthenticator(dataSource, cfg.Auth)
+	if err != nil {
+		return nil, err
+	}
+
+	// Initialize rate limiting
+	rateLimiter, err := newRateLimiter(dataSource, cfg.RateLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	// Initialize access control
+	membersRepo := access.NewRepository(dataSource)
+	authorizer := access.NewAuthorizer(membersRepo, cfg.Auth.SuperadminSubjects)
 
 	// Initialize profile domain
 	profileRepo := profile.NewRepository(dataSource)
-	profileService := profile.NewService(profileRepo)
+	profileService := profile.NewService(profileRepo, authorizer, dataSource)
 	profileHandler := profile.NewHandler(profileService)
 
+	// Initialize profile settings
+	settingsRepo := settings.NewRepository(dataSource)
+	settingsService := settings.NewService(settingsRepo, profileService, authorizer)
+	settingsHandler := settings.NewHandler(settingsService)
+
+	// Initialize webhooks
+	webhooksRepo, err := newWebhooksRepository(dataSource)
+	if err != nil {
+		return nil, err
+	}
+	webhookDispatcher := webhooks.NewDispatcher(webhooksRepo, webhooks.NewSender(cfg.Webhook.Timeout, cfg.Webhook.AllowInsecure), settingsService, cfg.Webhook.DisableAfter, appLogger)
+	webhooksService := webhooks.NewService(webhooksRepo, profileService, authorizer, webhookDispatcher, cfg.Webhook.AllowInsecure)
+	webhooksHandler := webhooks.NewHandler(webhooksService)
+
+	// Initialize event delivery
+	eventOutbox, err := newOutbox(dataSource, cfg, profileService, settingsService, webhookDispatcher, appLogger)
+	if err != nil {
+		return nil, err
+	}
+
+	// Initialize profile memberships
+	membersService := access.NewService(membersRepo, authorizer, profileService)
+	membersHandler := access.NewHandler(membersService)
+
 	// Initialize skills domain
 	skillsRepo := skills.NewRepository(dataSource)
-	skillsService := skills.NewService(skillsRepo, profileService)
+	skillsService := skills.NewService(skillsRepo, profileService, authorizer, dataSource, cfg.Skills.Categories)
 	skillsHandler := skills.NewHandler(skillsService)
 
 	// Initialize projects domain
-	projectsRepo := projects.NewRepository(dataSource)
-	projectsService := projects.NewService(projectsRepo, profileService)
+	projectsRepo, err := newProjectsRepository(dataSource)
+	if err != nil {
+		return nil, err
+	}
+	projectsService := projects.NewService(projectsRepo, profileService, authorizer, dataSource, eventOutbox)
 	projectsHandler := projects.NewHandler(projectsService)
 
 	// Initialize certificates domain
 	certificatesRepo := certificates.NewRepository(dataSource)
-	certificatesService := certificates.NewService(certificatesRepo, profileService)
+	certificatesService := certificates.NewService(certificatesRepo, profileService, authorizer)
 	certificatesHandler := certificates.NewHandler(certificatesService)
 
 	// Initialize contacts domain
-	contactsRepo := contacts.NewRepository(dataSource)
-	contactsService := contacts.NewService(contactsRepo, profileService)
+	contactsRepo, err := newContactsRepository(dataSource)
+	if err != nil {
+		return nil, err
+	}
+	spamFilter, err := newSpamFilter(dataSource, cfg.Spam, appLogger)
+	if err != nil {
+		return nil, err
+	}
+	contactVerifier, err := newContactVerifier(cfg.Contact, appLogger)
+	if err != nil {
+		return nil, err
+	}
+	contactsService := contacts.NewService(contactsRepo, profileService, settingsService, authorizer, spamFilter, dataSource, eventOutbox, contactVerifier, newContactMailLimiter(rateLimiter, cfg.Contact))
 	contactsHandler := contacts.NewHandler(contactsService)
 
 	// Initialize questions domain
 	questionsRepo := questions.NewRepository(dataSource)
-	questionsService := questions.NewService(questionsRepo, profileService)
+	questionsService := questions.NewService(questionsRepo, profileService, settingsService, authorizer, dataSource, eventOutbox)
 	questionsHandler := questions.NewHandler(questionsService)
 
+	// Deleting a profile deletes everything that belongs to it
+	profileService.Cascade(membersRepo, settingsRepo, webhooksRepo, skillsRepo, projectsRepo, certificatesRepo, contactsRepo, questionsRepo)
+
+	// Initialize aggregated portfolio
+	portfolioService := portfolio.NewService(profileService, skillsService, projectsService, certificatesService, questionsService)
+	portfolioHandler := portfolio.NewHandler(portfolioService)
+
 	// Initialize health handler
 	healthHandler := health.NewHandler(dataSource)
 
+	eventOutbox.Start()
+
 	return &Dependencies{
-		GlobalRateLimiter:   globalRateLimiter,
-		ContactRateLimiter:  contactRateLimiter,
-		QuestionRateLimiter: questionRateLimiter,
+		Authenticator:       authenticator,
+		RateLimiter:         rateLimiter,
+		CaptchaVerifier:     newCaptchaVerifier(cfg.Captcha),
+		CaptchaProfiles:     captcha.NewProfileSet(cfg.Captcha.Profiles),
+		Outbox:              eventOutbox,
 		ProfileHandler:      profileHandler,
 		SkillsHandler:       skillsHandler,
 		ProjectsHandler:     projectsHandler,
 		CertificatesHandler: certificatesHandler,
 		ContactsHandler:     contactsHandler,
 		QuestionsHandler:    questionsHandler,
+		MembersHandler:      membersHandler,
+		SettingsHandler:     settingsHandler,
+		WebhooksHandler:     webhooksHandler,
+		PortfolioHandler:    portfolioHandler,
 		HealthHandler:       healthHandler,
+	}, nil
+}
+
+// Close releases the background resources held by the dependencies
+func (deps *Dependencies) Close() {
+	deps.RateLimiter.Stop()
+	deps.Outbox.Stop()
+}
+
+// newRateLimiter builds the rate limiter applying the configured policies, keeping its state in memory
+// or, when configured, in the datastore so that every instance shares the same limits
+func newRateLimiter(dataSource contracts.DataSource, cfg config.RateLimitConfig) (*middleware.RateLimiter, error) {
+	opts := []middleware.RateLimiterOption{middleware.WithIPv6Prefix(cfg.IPv6Prefix)}
+
+	if cfg.Backend == config.RateLimitBackendDatastore {
+		backend := middleware.NewStoreRateLimitBackend(dataSource)
+
+		ctx, cancel := context.WithTimeout(context.Background(), indexTimeout)
+		defer cancel()
+		if err := backend.EnsureIndexes(ctx); err != nil {
+			return nil, fmt.Errorf("failed to create rate limit indexes: %w", err)
+		}
+		opts = append(opts, middleware.WithBackend(backend))
+	}
+
+	policies := make([]middleware.RateLimitPolicy, 0, len(cfg.Policies))
+	for _, policy := range cfg.Policies {
+		converted := middleware.RateLimitPolicy{
+			Name:  policy.Name,
+			Route: policy.Route,
+			Key:   middleware.RateLimitKey(policy.Key),
+			Quota: middleware.Quota(policy.RateLimitQuota),
+		}
+		if policy.Authenticated != nil {
+			authenticated := middleware.Quota(*policy.Authenticated)
+			converted.Authenticated = &authenticated
+		}
+		policies = append(policies, converted)
+	}
+
+	return middleware.NewPolicyRateLimiter(policies, opts...), nil
+}
+
+// newSpamFilter builds the contact form spam filter, creating the index expiring spent form tokens.
+// Without a configured secret, form tokens are signed with a random one and only accepted by the
+// instance that issued them.
+func newSpamFilter(dataSource contracts.DataSource, cfg config.SpamConfig, appLogger logger.Logger) (*spam.Filter, error) {
+	nonces := spam.NewStoreNonces(dataSource)
+	ctx, cancel := context.WithTimeout(context.Background(), indexTimeout)
+	defer cancel()
+	if err := nonces.EnsureIndexes(ctx); err != nil {
+		return nil, fmt.Errorf("failed to create form token indexes: %w", err)
+	}
+
+	secret := []byte(cfg.FormSecret)
+	if len(secret) == 0 {
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, fmt.Errorf("failed to generate spam form secret: %w", err)
+		}
+		appLogger.Warn("SPAM_FORM_SECRET is not set, contact form tokens are only valid on this instance")
+	}
+
+	return spam.NewFilter(spam.Config{
+		Secret:         secret,
+		MinFillTime:    cfg.MinFillTime,
+		FormTokenTTL:   cfg.FormTokenTTL,
+		Threshold:      cfg.Threshold,
+		FormTokenScore: cfg.FormTokenScore,
+		Phrases:        cfg.Phrases,
+		Nonces:         nonces,
+	}), nil
+}
+
+// newContactMailLimiter caps the emails sent to contact senders, or returns nil when none are sent
+func newContactMailLimiter(rateLimiter *middleware.RateLimiter, cfg config.ContactConfig) contacts.MailLimiter {
+	if !cfg.AutoReply && !cfg.Verification {
+		return nil
 	}
+	return middleware.NewMailLimiter(rateLimiter, "contact-mail",
+		middleware.Quota{Limit: cfg.MailsPerAddress, Window: cfg.MailLimitWindow},
+		middleware.Quota{Limit: cfg.MailsPerIP, Window: cfg.MailLimitWindow},
+	)
+}
+
+// newProjectsRepository builds the projects repository, ordering the projects stored before they had an order
+func newProjectsRepository(dataSource contracts.DataSource) (*projects.Repository, error) {
+	repo := projects.NewRepository(dataSource)
+
+	ctx, cancel := context.WithTimeout(context.Background(), indexTimeout)
+	defer cancel()
+	if err := repo.BackfillOrder(ctx); err != nil {
+		return nil, fmt.Errorf("failed to order existing projects: %w", err)
+	}
+	return repo, nil
+}
+
+// newContactsRepository builds the contacts repository, creating the index expiring unverified contacts
+func newContactsRepository(dataSource contracts.DataSource) (*contacts.Repository, error) {
+	repo := contacts.NewRepository(dataSource)
+
+	ctx, cancel := context.WithTimeout(context.Background(), indexTimeout)
+	defer cancel()
+	if err := repo.EnsureIndexes(ctx); err != nil {
+		return nil, fmt.Errorf("failed to create contact indexes: %w", err)
+	}
+	return repo, nil
+}
+
+// newContactVerifier builds the verifier of contact sender addresses, or nil when contacts are not
+// verified. Without a configured secret, links are signed with a random one and only accepted
+// by the instance that issued them.
+func newContactVerifier(cfg config.ContactConfig, appLogger logger.Logger) (*contacts.Verifier, error) {
+	if !cfg.Verification {
+		return nil, nil
+	}
+
+	secret := []byte(cfg.VerificationSecret)
+	if len(secret) == 0 {
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, fmt.Errorf("failed to generate contact verification secret: %w", err)
+		}
+		appLogger.Warn("CONTACT_VERIFICATION_SECRET is not set, contact verification links are only valid on this instance")
+	}
+
+	return contacts.NewVerifier(secret, cfg.VerificationTTL), nil
+}
+
+// newWebhooksRepository builds the webhooks repository, creating the delivery log indexes
+func newWebhooksRepository(dataSource contracts.DataSource) (*webhooks.Repository, error) {
+	repo := webhooks.NewRepository(dataSource)
+
+	ctx, cancel := context.WithTimeout(context.Background(), indexTimeout)
+	defer cancel()
+	if err := repo.EnsureIndexes(ctx); err != nil {
+		return nil, fmt.Errorf("failed to create webhook indexes: %w", err)
+	}
+	return repo, nil
+}
+
+// newOutbox builds the outbox delivering domain events to the profile webhooks. When a mail server
+// is configured, the recipients chosen in the profile settings, or else the configured ones, are also
+// emailed about new contacts and questions, and contact senders get their confirmation or verification email.
+func newOutbox(dataSource contracts.DataSource, cfg *config.Config, profileService *profile.Service, settingsService *settings.Service, webhookDispatcher *webhooks.Dispatcher, appLogger logger.Logger) (*outbox.Outbox, error) {
+	subscribers := []outbox.Subscriber{{Name: "webhooks", Handler: webhookDispatcher}}
+
+	if cfg.SMTP.Enabled() {
+		mailer := notify.NewSMTPMailer(notify.SMTPConfig{
+			Host:     cfg.SMTP.Host,
+			Port:     cfg.SMTP.Port,
+			Username: cfg.SMTP.Username,
+			Password: cfg.SMTP.Password,
+			From:     cfg.SMTP.From,
+			StartTLS: cfg.SMTP.StartTLS,
+		})
+		subscribers = append(subscribers, outbox.Subscriber{
+			Name:    "email",
+			Handler: notify.NewNotifier(mailer, notify.ProfileRecipients{Settings: settingsService, Default: cfg.Notify.EmailTo}, profileService),
+		})
+		if cfg.Contact.AutoReply || cfg.Contact.Verification {
+			subscribers = append(subscribers, outbox.Subscriber{
+				Name:    "auto-reply",
+				Handler: notify.NewAutoReplier(mailer, profileService, settingsService, cfg.Contact.VerifyURL),
+			})
+		}
+	} else {
+		appLogger.Info("SMTP_HOST is not set, email notifications are disabled")
+	}
+
+	eventOutbox := outbox.New(dataSource, appLogger, outbox.Config{
+		PollInterval: cfg.Outbox.PollInterval,
+		MaxAttempts:  cfg.Outbox.MaxAttempts,
+	}, subscribers...)
+
+	ctx, cancel := context.WithTimeout(context.Background(), indexTimeout)
+	defer cancel()
+	if err := eventOutbox.EnsureIndexes(ctx); err != nil {
+		return nil, fmt.Errorf("failed to create outbox indexes: %w", err)
+	}
+	return eventOutbox, nil
+}
+
+// newCaptchaVerifier builds the verifier of the configured CAPTCHA provider, or nil when disabled
+func newCaptchaVerifier(cfg config.CaptchaConfig) captcha.Verifier {
+	opts := []captcha.Option{captcha.WithMinScore(cfg.MinScore)}
+
+	switch cfg.Provider {
+	case config.CaptchaProviderTurnstile:
+		return captcha.NewTurnstileVerifier(cfg.Secret, opts...)
+	case config.CaptchaProviderHCaptcha:
+		return captcha.NewHCaptchaVerifier(cfg.Secret, opts...)
+	case config.CaptchaProviderRecaptcha:
+		return captcha.NewRecaptchaVerifier(cfg.Secret, opts...)
+	case config.CaptchaProviderFake:
+		return captcha.FakeVerifier{Token: cfg.Secret}
+	default:
+		return nil
+	}
+}
+
+// newAuthenticator builds the authenticator chain: API keys are always accepted,
+// JWT bearer tokens only when a secret or public key is configured
+func newAuthenticator(dataSource contracts.DataSource, cfg config.AuthConfig) (auth.Authenticator, error) {
+	chain := auth.Chain{auth.NewAPIKeyAuthenticator(dataSource)}
+
+	if !cfg.JWTEnabled() {
+		return chain, nil
+	}
+
+	jwtConfig := auth.JWTConfig{
+		HMACSecret: cfg.JWTSecret,
+		Issuer:     cfg.JWTIssuer,
+		Audience:   cfg.JWTAudience,
+	}
+	if cfg.JWTPublicKeyFile != "" {
+		publicKey, err := os.ReadFile(cfg.JWTPublicKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read JWT public key: %w", err)
+		}
+		jwtConfig.RSAPublicKeyPEM = publicKey
+	}
+
+	jwtAuthenticator, err := auth.NewJWTAuthenticator(jwtConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(chain, jwtAuthenticator), nil
 }
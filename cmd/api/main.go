@@ -62,10 +62,15 @@ func main() {
 	appLogger.Info("Data source connection established successfully")
 
 	// Initialize dependencies
-	deps := InitializeDependencies(dataSource, appLogger)
+	deps, err := InitializeDependencies(dataSource, appLogger, cfg)
+	if err != nil {
+		appLogger.Error("Failed to initialize dependencies", logger.Error(err))
+		os.Exit(1)
+	}
+	defer deps.Close()
 
 	// Setup routes
-	router := SetupRoutes(deps, appLogger, cfg.CORS.AllowedOrigins)
+	router := SetupRoutes(deps, appLogger, cfg)
 
 	// Create and start server
 	server := NewServer(cfg, router, appLogger)
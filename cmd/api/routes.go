@@ -4,39 +4,112 @@ import (
 	"github.com/go-chi/chi/v5"
 
 	"github.com/mrthoabby/portfolio-api/internal/common/logger"
+	"github.com/mrthoabby/portfolio-api/internal/config"
 	"github.com/mrthoabby/portfolio-api/internal/middleware"
 )
 
 // SetupRoutes configures all routes and middleware
-func SetupRoutes(deps *Dependencies, appLogger logger.Logger, allowedOrigins []string) *chi.Mux {
+func SetupRoutes(deps *Dependencies, appLogger logger.Logger, cfg *config.Config) *chi.Mux {
 	r := chi.NewRouter()
 
+	// Requests carrying credentials are throttled per IP before any of them is looked up
+	limitAuthentication := middleware.LimitAuthentication(deps.RateLimiter, middleware.Quota(cfg.RateLimit.Auth))
+
 	// Global middleware (order matters!)
-	r.Use(middleware.RecoverPanic)             // Recover from panics first
-	r.Use(middleware.RequestID)                // Add request ID for tracing
-	r.Use(middleware.ClientIP)                 // Extract client IP to context
-	r.Use(middleware.WithLogger(appLogger))    // Log requests with structured logger
-	r.Use(middleware.SecurityHeaders)          // Add security headers
-	r.Use(middleware.MaxBodySize(maxBodySize)) // Limit request body size
-	r.Use(deps.GlobalRateLimiter.Limit)        // Global rate limiting
-	r.Use(middleware.NewCORS(allowedOrigins))  // CORS
+	r.Use(middleware.RecoverPanic)                       // Recover from panics first
+	r.Use(middleware.RequestID)                          // Add request ID for tracing
+	r.Use(middleware.ClientIP(cfg.Proxy.TrustedProxies)) // Resolve the client IP behind trusted proxies
+	r.Use(middleware.NewCORS(cfg.CORS.AllowedOrigins))   // CORS, before anything that may reject the request
+	r.Use(limitAuthentication)                           // Throttle credentials per IP before they are checked
+	r.Use(middleware.Authenticate(deps.Authenticator))   // Resolve the authenticated principal, if any
+	r.Use(middleware.WithLogger(appLogger))              // Log requests with structured logger
+	r.Use(middleware.SecurityHeaders)                    // Add security headers
+	r.Use(middleware.MaxBodySize(maxBodySize))           // Limit request body size
+	r.Use(deps.RateLimiter.Limit)                        // Rate limiting per configured policy
+
+	// Challenge verification for the anonymous forms of the enabled profiles
+	requireCaptcha := middleware.RequireCaptcha(deps.CaptchaVerifier, deps.CaptchaProfiles)
 
 	// Health check (no rate limiting needed)
 	r.Get("/health", deps.HealthHandler.Check)
 
 	// API routes
 	r.Route("/api/v1", func(r chi.Router) {
+		r.Get("/skills/categories", deps.SkillsHandler.GetCategories)
+
+		// Link emailed to contact senders when their address must be confirmed
+		r.Get("/contacts/verify", deps.ContactsHandler.Verify)
+
 		r.Route("/profiles/{id}", func(r chi.Router) {
 			r.Get("/", deps.ProfileHandler.GetByID)
 			r.Get("/skills", deps.SkillsHandler.GetByProfileID)
 			r.Get("/projects", deps.ProjectsHandler.GetByProfileID)
 			r.Get("/certificates", deps.CertificatesHandler.GetByProfileID)
+			r.Get("/faq", deps.QuestionsHandler.GetFAQ)
+			r.Get("/portfolio", deps.PortfolioHandler.Get)
 
-			// Contact endpoint with stricter rate limiting
-			r.With(deps.ContactRateLimiter.Limit).Post("/contacts", deps.ContactsHandler.Create)
+			// Contact endpoint with stricter rate limiting and spam screening
+			r.Get("/contacts/form-token", deps.ContactsHandler.FormToken)
+			r.With(requireCaptcha).Post("/contacts", deps.ContactsHandler.Create)
 
 			// Questions endpoint with rate limiting
-			r.With(deps.QuestionRateLimiter.Limit).Post("/questions", deps.QuestionsHandler.Create)
+			r.With(requireCaptcha).Post("/questions", deps.QuestionsHandler.Create)
+		})
+
+		// Admin routes require an authenticated principal
+		r.Route("/admin", func(r chi.Router) {
+			r.Use(middleware.RequireAuth)
+
+			r.Post("/profiles", deps.ProfileHandler.Create)
+			r.Route("/profiles/{id}", func(r chi.Router) {
+				r.Put("/", deps.ProfileHandler.Update)
+				r.Patch("/", deps.ProfileHandler.Patch)
+				r.Delete("/", deps.ProfileHandler.Delete)
+
+				r.Get("/members", deps.MembersHandler.GetByProfileID)
+				r.Put("/members/{subject}", deps.MembersHandler.Save)
+				r.Delete("/members/{subject}", deps.MembersHandler.Delete)
+
+				r.Post("/skills", deps.SkillsHandler.Create)
+				r.Put("/skills/order", deps.SkillsHandler.Reorder)
+				r.Put("/skills/{skillId}", deps.SkillsHandler.Update)
+				r.Delete("/skills/{skillId}", deps.SkillsHandler.Delete)
+
+				r.Get("/projects", deps.ProjectsHandler.GetAllByProfileID)
+				r.Post("/projects", deps.ProjectsHandler.Create)
+				r.Put("/projects/order", deps.ProjectsHandler.Reorder)
+				r.Put("/projects/{projectId}", deps.ProjectsHandler.Update)
+				r.Patch("/projects/{projectId}", deps.ProjectsHandler.Patch)
+				r.Delete("/projects/{projectId}", deps.ProjectsHandler.Delete)
+
+				r.Post("/certificates", deps.CertificatesHandler.Create)
+				r.Put("/certificates/{certificateId}", deps.CertificatesHandler.Update)
+				r.Delete("/certificates/{certificateId}", deps.CertificatesHandler.Delete)
+
+				r.Get("/contacts", deps.ContactsHandler.GetByProfileID)
+				r.Get("/contacts/{contactId}", deps.ContactsHandler.GetByID)
+				r.Post("/contacts/{contactId}/contacted", deps.ContactsHandler.MarkContacted)
+				r.Put("/contacts/{contactId}/status", deps.ContactsHandler.UpdateStatus)
+				r.Post("/contacts/{contactId}/notes", deps.ContactsHandler.AddNote)
+				r.Put("/contacts/{contactId}/assignee", deps.ContactsHandler.Assign)
+
+				r.Get("/questions", deps.QuestionsHandler.GetByProfileID)
+				r.Post("/questions/{questionId}/answer", deps.QuestionsHandler.Answer)
+				r.Post("/questions/{questionId}/reject", deps.QuestionsHandler.Reject)
+				r.Post("/questions/{questionId}/publish", deps.QuestionsHandler.Publish)
+				r.Post("/questions/{questionId}/unpublish", deps.QuestionsHandler.Unpublish)
+
+				r.Get("/settings", deps.SettingsHandler.GetByProfileID)
+				r.Put("/settings", deps.SettingsHandler.Update)
+
+				r.Get("/webhooks", deps.WebhooksHandler.GetByProfileID)
+				r.Post("/webhooks", deps.WebhooksHandler.Create)
+				r.Get("/webhooks/{webhookId}", deps.WebhooksHandler.GetByID)
+				r.Put("/webhooks/{webhookId}", deps.WebhooksHandler.Update)
+				r.Delete("/webhooks/{webhookId}", deps.WebhooksHandler.Delete)
+				r.Get("/webhooks/{webhookId}/deliveries", deps.WebhooksHandler.GetDeliveries)
+				r.Post("/webhooks/{webhookId}/deliveries/{deliveryId}/redeliver", deps.WebhooksHandler.Redeliver)
+			})
 		})
 	})
 
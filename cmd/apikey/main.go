@@ -0,0 +1,50 @@
+// Command apikey generates a new API key and prints the document to insert
+// into the api_keys collection. Only the hash is stored; the key itself is
+// shown once and must be handed to the client.
+//
+// Usage: go run ./cmd/apikey -name "portfolio-ui admin" -subject "user-123"
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/mrthoabby/portfolio-api/internal/auth"
+)
+
+func main() {
+	name := flag.String("name", "", "human readable name of the key")
+	subject := flag.String("subject", "", "subject the key authenticates as")
+	flag.Parse()
+
+	if *name == "" || *subject == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	key, hash, err := auth.GenerateAPIKey()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to generate API key: %v\n", err)
+		os.Exit(1)
+	}
+
+	document, err := json.MarshalIndent(map[string]interface{}{
+		"_id":       uuid.New().String(),
+		"name":      *name,
+		"subject":   *subject,
+		"keyHash":   hash,
+		"createdAt": map[string]string{"$date": time.Now().UTC().Format(time.RFC3339)},
+	}, "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to encode document: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("API key (store it now, it cannot be recovered):\n\n  %s\n\n", key)
+	fmt.Printf("Insert into the api_keys collection:\n\n%s\n", document)
+}
@@ -0,0 +1,90 @@
+package access
+
+import (
+	"context"
+
+	"github.com/mrthoabby/portfolio-api/internal/common"
+	"github.com/mrthoabby/portfolio-api/internal/common/types"
+)
+
+var (
+	// ErrUnauthenticated is returned when an operation requires a principal and the request has none
+	ErrUnauthenticated = types.ErrUnauthenticated{Message: "authentication required"}
+
+	// ErrForbidden is returned when the principal's role does not allow the operation
+	ErrForbidden = types.ErrForbidden{Message: "not allowed to perform this operation"}
+)
+
+// Authorizer decides whether the principal in a context may act on a profile
+type Authorizer struct {
+	repo        *Repository
+	superadmins map[string]struct{}
+}
+
+// NewAuthorizer creates an authorizer. Subjects listed in superadmins may act on every profile.
+func NewAuthorizer(repo *Repository, superadmins []string) *Authorizer {
+	set := make(map[string]struct{}, len(superadmins))
+	for _, subject := range superadmins {
+		if subject != "" {
+			set[subject] = struct{}{}
+		}
+	}
+	return &Authorizer{
+		repo:        repo,
+		superadmins: set,
+	}
+}
+
+// IsSuperadmin reports whether the principal in ctx is a superadmin
+func (a *Authorizer) IsSuperadmin(ctx context.Context) bool {
+	principal := common.PrincipalFromContext(ctx)
+	if principal == nil {
+		return false
+	}
+	_, ok := a.superadmins[principal.Subject]
+	return ok
+}
+
+// RequireSuperadmin returns an error unless the principal in ctx is a superadmin
+func (a *Authorizer) RequireSuperadmin(ctx context.Context) error {
+	if common.PrincipalFromContext(ctx) == nil {
+		return ErrUnauthenticated
+	}
+	if !a.IsSuperadmin(ctx) {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// Authorize returns an error unless the principal in ctx may perform action on the profile
+func (a *Authorizer) Authorize(ctx context.Context, profileID string, action Action) error {
+	principal := common.PrincipalFromContext(ctx)
+	if principal == nil {
+		return ErrUnauthenticated
+	}
+	if a.IsSuperadmin(ctx) {
+		return nil
+	}
+
+	role, err := a.repo.GetRole(ctx, profileID, principal.Subject)
+	if err != nil {
+		return err
+	}
+	if !Allows(role, action) {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// IsMember reports whether subject holds any role on the profile, or is a superadmin
+func (a *Authorizer) IsMember(ctx context.Context, profileID, subject string) (bool, error) {
+	if _, ok := a.superadmins[subject]; ok {
+		return true, nil
+	}
+
+	role, err := a.repo.GetRole(ctx, profileID, subject)
+	if err != nil {
+		return false, err
+	}
+	return role != "", nil
+}
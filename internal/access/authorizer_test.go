@@ -0,0 +1,65 @@
+package access
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mrthoabby/portfolio-api/internal/common"
+)
+
+func withSubject(subject string) context.Context {
+	return common.WithPrincipal(context.Background(), &common.Principal{Subject: subject})
+}
+
+func TestAllows(t *testing.T) {
+	tests := []struct {
+		role     string
+		action   Action
+		expected bool
+	}{
+		{RoleOwner, ActionRead, true},
+		{RoleOwner, ActionEdit, true},
+		{RoleOwner, ActionManage, true},
+		{RoleEditor, ActionRead, true},
+		{RoleEditor, ActionEdit, true},
+		{RoleEditor, ActionManage, false},
+		{RoleViewer, ActionRead, true},
+		{RoleViewer, ActionEdit, false},
+		{RoleViewer, ActionManage, false},
+		{"", ActionRead, false},
+		{RoleSuperadmin, ActionRead, false}, // superadmin is never granted through a membership
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.role+"/"+string(tt.action), func(t *testing.T) {
+			assert.Equal(t, tt.expected, Allows(tt.role, tt.action))
+		})
+	}
+}
+
+func TestIsValidRole(t *testing.T) {
+	assert.True(t, IsValidRole(RoleOwner))
+	assert.True(t, IsValidRole(RoleEditor))
+	assert.True(t, IsValidRole(RoleViewer))
+	assert.False(t, IsValidRole(RoleSuperadmin))
+	assert.False(t, IsValidRole("admin"))
+}
+
+func TestAuthorizer_RequireSuperadmin(t *testing.T) {
+	authorizer := NewAuthorizer(nil, []string{"root", ""})
+
+	assert.NoError(t, authorizer.RequireSuperadmin(withSubject("root")))
+	assert.ErrorIs(t, authorizer.RequireSuperadmin(withSubject("user-123")), ErrForbidden)
+	assert.ErrorIs(t, authorizer.RequireSuperadmin(withSubject("")), ErrForbidden)
+	assert.ErrorIs(t, authorizer.RequireSuperadmin(context.Background()), ErrUnauthenticated)
+}
+
+func TestAuthorizer_SuperadminBypassesMemberships(t *testing.T) {
+	// A nil repository proves memberships are not consulted for superadmins
+	authorizer := NewAuthorizer(nil, []string{"root"})
+
+	assert.NoError(t, authorizer.Authorize(withSubject("root"), "profile-1", ActionManage))
+	assert.ErrorIs(t, authorizer.Authorize(context.Background(), "profile-1", ActionRead), ErrUnauthenticated)
+}
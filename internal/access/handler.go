@@ -0,0 +1,105 @@
+package access
+
+import (
+	"net/http"
+
+	"github.com/mrthoabby/portfolio-api/internal/common"
+)
+
+type Handler struct {
+	service   *Service
+	validator *common.Validator
+}
+
+func NewHandler(service *Service) *Handler {
+	return &Handler{
+		service:   service,
+		validator: common.NewValidator(),
+	}
+}
+
+// GetByProfileID handles GET /admin/profiles/{id}/members
+func (h *Handler) GetByProfileID(w http.ResponseWriter, r *http.Request) {
+	profileID, ok := profileIDFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	members, err := h.service.GetByProfileID(r.Context(), profileID)
+	if err != nil {
+		common.RespondServiceError(w, r, err)
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, Response{Members: members})
+}
+
+// Save handles PUT /admin/profiles/{id}/members/{subject}
+func (h *Handler) Save(w http.ResponseWriter, r *http.Request) {
+	profileID, ok := profileIDFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	subject := r.PathValue("subject")
+	if subject == "" {
+		common.RespondError(w, r, http.StatusBadRequest, "BAD_REQUEST", "Subject is required", nil)
+		return
+	}
+
+	var memberReq Request
+	if err := common.DecodeJSON(r, &memberReq); err != nil {
+		common.RespondServiceError(w, r, err)
+		return
+	}
+
+	if err := h.validator.Validate(r, &memberReq); err != nil {
+		common.RespondServiceError(w, r, err)
+		return
+	}
+
+	membership, err := h.service.Save(r.Context(), profileID, subject, memberReq.Role)
+	if err != nil {
+		common.RespondServiceError(w, r, err)
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, membership)
+}
+
+// Delete handles DELETE /admin/profiles/{id}/members/{subject}
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
+	profileID, ok := profileIDFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	subject := r.PathValue("subject")
+	if subject == "" {
+		common.RespondError(w, r, http.StatusBadRequest, "BAD_REQUEST", "Subject is required", nil)
+		return
+	}
+
+	if err := h.service.Delete(r.Context(), profileID, subject); err != nil {
+		common.RespondServiceError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func profileIDFromPath(w http.ResponseWriter, r *http.Request) (string, bool) {
+	profileID := r.PathValue("id")
+	if profileID == "" {
+		common.RespondError(w, r, http.StatusBadRequest, "BAD_REQUEST", "Profile ID is required", nil)
+		return "", false
+	}
+
+	// Validate UUID format
+	if !common.IsValidUUID(profileID) {
+		common.RespondError(w, r, http.StatusBadRequest, "BAD_REQUEST", "Invalid profile ID format", nil)
+		return "", false
+	}
+
+	return profileID, true
+}
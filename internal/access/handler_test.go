@@ -0,0 +1,61 @@
+package access
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mrthoabby/portfolio-api/internal/common"
+)
+
+func TestHandler_Save_InvalidProfileID(t *testing.T) {
+	handler := NewHandler(&Service{})
+
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/admin/profiles/invalid-uuid/members/user-1", strings.NewReader(`{"role":"editor"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.SetPathValue("id", "invalid-uuid")
+	req.SetPathValue("subject", "user-1")
+	w := httptest.NewRecorder()
+
+	handler.Save(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestHandler_Save_InvalidRole(t *testing.T) {
+	handler := NewHandler(&Service{})
+
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/admin/profiles/550e8400-e29b-41d4-a716-446655440000/members/user-1", strings.NewReader(`{"role":"superadmin"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.SetPathValue("id", "550e8400-e29b-41d4-a716-446655440000")
+	req.SetPathValue("subject", "user-1")
+	w := httptest.NewRecorder()
+
+	handler.Save(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "VALIDATION_ERROR")
+}
+
+func TestServiceErrors_Status(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected int
+	}{
+		{"unauthenticated", ErrUnauthenticated, http.StatusUnauthorized},
+		{"forbidden", ErrForbidden, http.StatusForbidden},
+		{"last owner", ErrLastOwner, http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			common.RespondServiceError(w, httptest.NewRequest(http.MethodGet, "/", nil), tt.err)
+			assert.Equal(t, tt.expected, w.Code)
+		})
+	}
+}
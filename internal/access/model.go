@@ -0,0 +1,67 @@
+package access
+
+import "time"
+
+// Role constants for profile memberships
+const (
+	RoleOwner  = "owner"
+	RoleEditor = "editor"
+	RoleViewer = "viewer"
+
+	// RoleSuperadmin is granted through configuration, never through a membership
+	RoleSuperadmin = "superadmin"
+)
+
+// Action is an operation a role may be allowed to perform on a profile
+type Action string
+
+// Action constants, from least to most privileged
+const (
+	// ActionRead covers private data such as the contact inbox
+	ActionRead Action = "read"
+	// ActionEdit covers the profile and its skills, projects and certificates
+	ActionEdit Action = "edit"
+	// ActionManage covers memberships and deleting the profile
+	ActionManage Action = "manage"
+)
+
+// roleActions lists what each membership role may do
+var roleActions = map[string][]Action{
+	RoleOwner:  {ActionRead, ActionEdit, ActionManage},
+	RoleEditor: {ActionRead, ActionEdit},
+	RoleViewer: {ActionRead},
+}
+
+// Membership grants a subject a role on a single profile
+type Membership struct {
+	ID        string    `json:"id" bson:"_id,omitempty"`
+	ProfileID string    `json:"profileId" bson:"profileId"`
+	Subject   string    `json:"subject" bson:"subject"`
+	Role      string    `json:"role" bson:"role"`
+	CreatedAt time.Time `json:"createdAt" bson:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt" bson:"updatedAt"`
+}
+
+type Request struct {
+	Role string `json:"role" validate:"required,oneof=owner editor viewer"`
+}
+
+type Response struct {
+	Members []Membership `json:"members"`
+}
+
+// IsValidRole reports whether role can be assigned through a membership
+func IsValidRole(role string) bool {
+	_, ok := roleActions[role]
+	return ok
+}
+
+// Allows reports whether the role permits the action
+func Allows(role string, action Action) bool {
+	for _, allowed := range roleActions[role] {
+		if allowed == action {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+package access
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/mrthoabby/portfolio-api/internal/common/contracts"
+	"github.com/mrthoabby/portfolio-api/internal/common/types"
+)
+
+type Repository struct {
+	store contracts.Store
+}
+
+func NewRepository(dataSource contracts.DataSource) *Repository {
+	return &Repository{
+		store: dataSource.Store("profile_members"),
+	}
+}
+
+// GetRole returns the role of subject on the profile, or an empty string if it has none
+func (r *Repository) GetRole(ctx context.Context, profileID, subject string) (string, error) {
+	var membership Membership
+	filter := map[string]interface{}{"profileId": profileID, "subject": subject}
+	if err := r.store.FindOne(ctx, filter, &membership); err != nil {
+		if types.IsNotFoundError(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	return membership.Role, nil
+}
+
+func (r *Repository) GetByProfileID(ctx context.Context, profileID string) ([]Membership, error) {
+	var memberships []Membership
+	filter := map[string]interface{}{"profileId": profileID}
+	sortFields := []string{"role", "subject"}
+
+	err := r.store.FindMany(ctx, filter, sortFields, &memberships)
+	if err != nil {
+		return nil, err
+	}
+
+	return memberships, nil
+}
+
+// Save creates the membership or changes the role of an existing one
+func (r *Repository) Save(ctx context.Context, profileID, subject, role string) (*Membership, error) {
+	now := time.Now()
+	filter := map[string]interface{}{"profileId": profileID, "subject": subject}
+
+	err := r.store.UpdateOne(ctx, filter, map[string]interface{}{"role": role, "updatedAt": now})
+	if err == nil {
+		var membership Membership
+		if err := r.store.FindOne(ctx, filter, &membership); err != nil {
+			return nil, err
+		}
+		return &membership, nil
+	}
+	if !types.IsNotFoundError(err) {
+		return nil, err
+	}
+
+	newMembership := &Membership{
+		ID:        uuid.New().String(),
+		ProfileID: profileID,
+		Subject:   subject,
+		Role:      role,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := r.store.InsertOne(ctx, newMembership); err != nil {
+		return nil, err
+	}
+	return newMembership, nil
+}
+
+func (r *Repository) Delete(ctx context.Context, profileID, subject string) error {
+	err := r.store.DeleteOne(ctx, map[string]interface{}{"profileId": profileID, "subject": subject})
+	if err != nil {
+		if types.IsNotFoundError(err) {
+			return types.ErrNotFound{Message: "membership not found"}
+		}
+		return err
+	}
+	return nil
+}
+
+// DeleteByProfileID deletes every membership of a profile
+func (r *Repository) DeleteByProfileID(ctx context.Context, profileID string) error {
+	_, err := r.store.DeleteMany(ctx, map[string]interface{}{"profileId": profileID})
+	return err
+}
+
+// CountByRole counts the memberships of a profile holding the given role
+func (r *Repository) CountByRole(ctx context.Context, profileID, role string) (int64, error) {
+	return r.store.CountRecords(ctx, map[string]interface{}{"profileId": profileID, "role": role})
+}
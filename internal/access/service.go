@@ -0,0 +1,98 @@
+package access
+
+import (
+	"context"
+
+	"github.com/mrthoabby/portfolio-api/internal/common/types"
+)
+
+// ErrLastOwner is returned when a change would leave a profile without an owner
+var ErrLastOwner = types.ErrConflict{Message: "a profile must keep at least one owner"}
+
+// ProfileChecker reports whether a profile exists
+type ProfileChecker interface {
+	Exists(ctx context.Context, id string) (bool, error)
+}
+
+// Service manages the memberships of a profile
+type Service struct {
+	repo       *Repository
+	authorizer *Authorizer
+	profiles   ProfileChecker
+}
+
+func NewService(repo *Repository, authorizer *Authorizer, profiles ProfileChecker) *Service {
+	return &Service{
+		repo:       repo,
+		authorizer: authorizer,
+		profiles:   profiles,
+	}
+}
+
+func (s *Service) GetByProfileID(ctx context.Context, profileID string) ([]Membership, error) {
+	if err := s.authorize(ctx, profileID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByProfileID(ctx, profileID)
+}
+
+// Save grants subject the role on the profile, replacing any previous role
+func (s *Service) Save(ctx context.Context, profileID, subject, role string) (*Membership, error) {
+	if err := s.authorize(ctx, profileID); err != nil {
+		return nil, err
+	}
+	if role != RoleOwner {
+		if err := s.ensureNotLastOwner(ctx, profileID, subject); err != nil {
+			return nil, err
+		}
+	}
+	return s.repo.Save(ctx, profileID, subject, role)
+}
+
+func (s *Service) Delete(ctx context.Context, profileID, subject string) error {
+	if err := s.authorize(ctx, profileID); err != nil {
+		return err
+	}
+	if err := s.ensureNotLastOwner(ctx, profileID, subject); err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, profileID, subject)
+}
+
+func (s *Service) authorize(ctx context.Context, profileID string) error {
+	// Verify profile exists
+	exists, err := s.profiles.Exists(ctx, profileID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return types.ErrNotFound{Message: "profile not found"}
+	}
+
+	return s.authorizer.Authorize(ctx, profileID, ActionManage)
+}
+
+// ensureNotLastOwner rejects removing the owner role from the only owner of a profile.
+// Superadmins may still do it, since they can always restore ownership.
+func (s *Service) ensureNotLastOwner(ctx context.Context, profileID, subject string) error {
+	if s.authorizer.IsSuperadmin(ctx) {
+		return nil
+	}
+
+	role, err := s.repo.GetRole(ctx, profileID, subject)
+	if err != nil {
+		return err
+	}
+	if role != RoleOwner {
+		return nil
+	}
+
+	owners, err := s.repo.CountByRole(ctx, profileID, RoleOwner)
+	if err != nil {
+		return err
+	}
+	if owners <= 1 {
+		return ErrLastOwner
+	}
+	return nil
+}
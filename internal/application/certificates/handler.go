@@ -7,29 +7,41 @@ import (
 )
 
 type Handler struct {
-	service *Service
+	service   *Service
+	validator *common.Validator
 }
 
 func NewHandler(service *Service) *Handler {
-	return &Handler{service: service}
+	return &Handler{
+		service:   service,
+		validator: common.NewValidator(),
+	}
 }
 
+// GetByProfileID handles GET /profiles/{id}/certificates.
+// The optional status query parameter narrows the list to active or expired certificates.
 func (h *Handler) GetByProfileID(w http.ResponseWriter, r *http.Request) {
 	profileID := r.PathValue("id")
 	if profileID == "" {
-		common.RespondError(w, http.StatusBadRequest, "BAD_REQUEST", "Profile ID is required", nil)
+		common.RespondError(w, r, http.StatusBadRequest, "BAD_REQUEST", "Profile ID is required", nil)
 		return
 	}
 
 	// Validate UUID format
 	if !common.IsValidUUID(profileID) {
-		common.RespondError(w, http.StatusBadRequest, "BAD_REQUEST", "Invalid profile ID format", nil)
+		common.RespondError(w, r, http.StatusBadRequest, "BAD_REQUEST", "Invalid profile ID format", nil)
+		return
+	}
+
+	status := r.URL.Query().Get("status")
+	if status != "" && status != StatusActive && status != StatusExpired {
+		common.RespondError(w, r, http.StatusBadRequest, "BAD_REQUEST", "status must be one of active, expired", nil)
 		return
 	}
 
-	certificates, err := h.service.GetByProfileID(r.Context(), profileID)
+	certificates, err := h.service.GetByProfileID(r.Context(), profileID, status)
 	if err != nil {
-		common.RespondError(w, http.StatusNotFound, "NOT_FOUND", err.Error(), nil)
+		common.RespondServiceError(w, r, err)
 		return
 	}
 
@@ -37,3 +49,93 @@ func (h *Handler) GetByProfileID(w http.ResponseWriter, r *http.Request) {
 	common.RespondJSON(w, http.StatusOK, response)
 }
 
+// Create handles POST /admin/profiles/{id}/certificates
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	profileID := r.PathValue("id")
+	if !common.IsValidUUID(profileID) {
+		common.RespondError(w, r, http.StatusBadRequest, "BAD_REQUEST", "Invalid profile ID format", nil)
+		return
+	}
+
+	var certificateReq Request
+	if !h.decodeAndValidate(w, r, &certificateReq) {
+		return
+	}
+
+	certificate, err := h.service.Create(r.Context(), profileID, &certificateReq)
+	if err != nil {
+		common.RespondServiceError(w, r, err)
+		return
+	}
+
+	common.RespondJSON(w, http.StatusCreated, certificate)
+}
+
+// Update handles PUT /admin/profiles/{id}/certificates/{certificateId}
+func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
+	profileID, certificateID, ok := certificateIDsFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	var certificateReq Request
+	if !h.decodeAndValidate(w, r, &certificateReq) {
+		return
+	}
+
+	certificate, err := h.service.Update(r.Context(), profileID, certificateID, &certificateReq)
+	if err != nil {
+		common.RespondServiceError(w, r, err)
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, certificate)
+}
+
+// Delete handles DELETE /admin/profiles/{id}/certificates/{certificateId}
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
+	profileID, certificateID, ok := certificateIDsFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(r.Context(), profileID, certificateID); err != nil {
+		common.RespondServiceError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// decodeAndValidate decodes a certificate request, sanitizing it before validation so the rules apply to the stored values
+func (h *Handler) decodeAndValidate(w http.ResponseWriter, r *http.Request, certificateReq *Request) bool {
+	if err := common.DecodeJSON(r, certificateReq); err != nil {
+		common.RespondServiceError(w, r, err)
+		return false
+	}
+	sanitize(certificateReq)
+
+	if err := h.validator.Validate(r, certificateReq); err != nil {
+		common.RespondServiceError(w, r, err)
+		return false
+	}
+	return true
+}
+
+func sanitize(certificateReq *Request) {
+	certificateReq.Name = common.SanitizeString(common.StripHTMLTags(certificateReq.Name))
+	certificateReq.Issuer = common.SanitizeString(common.StripHTMLTags(certificateReq.Issuer))
+	for i, skill := range certificateReq.Skills {
+		certificateReq.Skills[i] = common.SanitizeString(common.StripHTMLTags(skill))
+	}
+}
+
+func certificateIDsFromPath(w http.ResponseWriter, r *http.Request) (string, string, bool) {
+	profileID := r.PathValue("id")
+	certificateID := r.PathValue("certificateId")
+	if !common.IsValidUUID(profileID) || !common.IsValidUUID(certificateID) {
+		common.RespondError(w, r, http.StatusBadRequest, "BAD_REQUEST", "Invalid profile or certificate ID format", nil)
+		return "", "", false
+	}
+	return profileID, certificateID, true
+}
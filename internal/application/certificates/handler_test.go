@@ -2,11 +2,15 @@ package certificates
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/mrthoabby/portfolio-api/internal/common"
 )
 
 func TestHandler_GetByProfileID_MissingID(t *testing.T) {
@@ -29,3 +33,79 @@ func TestNewHandler(t *testing.T) {
 	assert.Equal(t, service, handler.service)
 }
 
+func TestHandler_GetByProfileID_InvalidStatus(t *testing.T) {
+	handler := NewHandler(&Service{})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/profiles/123e4567-e89b-12d3-a456-426614174000/certificates?status=revoked", nil)
+	req.SetPathValue("id", "123e4567-e89b-12d3-a456-426614174000")
+	w := httptest.NewRecorder()
+
+	handler.GetByProfileID(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestHandler_Create_ExpiryBeforeIssue(t *testing.T) {
+	handler := NewHandler(&Service{})
+
+	body := `{"name": "CKA", "issuer": "CNCF", "issuedAt": "2024-05-01T00:00:00Z", "expiresAt": "2023-05-01T00:00:00Z"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/profiles/123e4567-e89b-12d3-a456-426614174000/certificates", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.SetPathValue("id", "123e4567-e89b-12d3-a456-426614174000")
+	w := httptest.NewRecorder()
+
+	handler.Create(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var response struct {
+		Error struct {
+			Details []common.FieldError `json:"details"`
+		} `json:"error"`
+	}
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Equal(t, []common.FieldError{{Field: "expiresAt", Rule: "gtfield", Param: "issuedAt", Message: "expiresAt must be greater than issuedAt"}}, response.Error.Details)
+}
+
+func TestHandler_Create_MissingIssuedAt(t *testing.T) {
+	handler := NewHandler(&Service{})
+
+	body := `{"name": "CKA", "issuer": "CNCF"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/profiles/123e4567-e89b-12d3-a456-426614174000/certificates", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.SetPathValue("id", "123e4567-e89b-12d3-a456-426614174000")
+	w := httptest.NewRecorder()
+
+	handler.Create(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"field":"issuedAt"`)
+}
+
+func TestHandler_Create_RejectsMarkupOnlyValues(t *testing.T) {
+	tests := []struct {
+		name  string
+		body  string
+		field string
+	}{
+		{"name", `{"name": "<b></b>", "issuer": "CNCF", "issuedAt": "2024-05-01T00:00:00Z"}`, "name"},
+		{"issuer", `{"name": "CKA", "issuer": "<i></i>", "issuedAt": "2024-05-01T00:00:00Z"}`, "issuer"},
+		{"skill", `{"name": "CKA", "issuer": "CNCF", "skills": ["<br>"], "issuedAt": "2024-05-01T00:00:00Z"}`, "skills[0]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewHandler(&Service{})
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/profiles/123e4567-e89b-12d3-a456-426614174000/certificates", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			req.SetPathValue("id", "123e4567-e89b-12d3-a456-426614174000")
+			w := httptest.NewRecorder()
+
+			handler.Create(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Contains(t, w.Body.String(), `"field":"`+tt.field+`"`)
+		})
+	}
+}
@@ -1,15 +1,44 @@
 package certificates
 
+import "time"
+
+// Status values accepted by the public listing filter
+const (
+	StatusActive  = "active"
+	StatusExpired = "expired"
+)
+
 type Certificate struct {
-	ID            string   `json:"id" bson:"_id,omitempty"`
-	ProfileID     string   `json:"profileId" bson:"profileId"`
-	Name          string   `json:"name" bson:"name"`
-	Issuer        string   `json:"issuer" bson:"issuer"`
-	CredentialID  *string  `json:"credentialId,omitempty" bson:"credentialId,omitempty"`
-	CredentialURL *string  `json:"credentialUrl,omitempty" bson:"credentialUrl,omitempty"`
-	Skills        []string `json:"skills" bson:"skills"`
+	ID            string     `json:"id" bson:"_id,omitempty"`
+	ProfileID     string     `json:"profileId" bson:"profileId"`
+	Name          string     `json:"name" bson:"name"`
+	Issuer        string     `json:"issuer" bson:"issuer"`
+	CredentialID  *string    `json:"credentialId,omitempty" bson:"credentialId,omitempty"`
+	CredentialURL *string    `json:"credentialUrl,omitempty" bson:"credentialUrl,omitempty"`
+	Skills        []string   `json:"skills" bson:"skills"`
+	IssuedAt      *time.Time `json:"issuedAt,omitempty" bson:"issuedAt,omitempty"`
+	ExpiresAt     *time.Time `json:"expiresAt,omitempty" bson:"expiresAt,omitempty"`
+	// Expired is computed when the certificate is read and never stored
+	Expired bool `json:"expired" bson:"-"`
 }
 
 type Response struct {
 	Certificates []Certificate `json:"certificates"`
 }
+
+// Request is the payload for creating or replacing a certificate.
+// Certificates without an expiry date never expire.
+type Request struct {
+	Name          string     `json:"name" validate:"required,min=1,max=150"`
+	Issuer        string     `json:"issuer" validate:"required,min=1,max=150"`
+	CredentialID  *string    `json:"credentialId,omitempty" validate:"omitempty,max=100"`
+	CredentialURL *string    `json:"credentialUrl,omitempty" validate:"omitempty,url"`
+	Skills        []string   `json:"skills" validate:"max=30,dive,min=1,max=50"`
+	IssuedAt      *time.Time `json:"issuedAt" validate:"required"`
+	ExpiresAt     *time.Time `json:"expiresAt,omitempty" validate:"omitempty,gtfield=IssuedAt"`
+}
+
+// IsExpired reports whether the certificate expired at the given instant
+func (c *Certificate) IsExpired(now time.Time) bool {
+	return c.ExpiresAt != nil && !c.ExpiresAt.After(now)
+}
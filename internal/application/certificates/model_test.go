@@ -0,0 +1,26 @@
+package certificates
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCertificate_IsExpired(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+
+	assert.False(t, (&Certificate{}).IsExpired(now))
+	assert.False(t, (&Certificate{ExpiresAt: &future}).IsExpired(now))
+	assert.True(t, (&Certificate{ExpiresAt: &past}).IsExpired(now))
+	assert.True(t, (&Certificate{ExpiresAt: &now}).IsExpired(now))
+}
+
+func TestCertificate_ExpiredIsSerialized(t *testing.T) {
+	data, err := json.Marshal(Certificate{Name: "CKA", Expired: false})
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `"expired":false`)
+}
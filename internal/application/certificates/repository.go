@@ -2,8 +2,12 @@ package certificates
 
 import (
 	"context"
+	"time"
+
+	"github.com/google/uuid"
 
 	"github.com/mrthoabby/portfolio-api/internal/common/contracts"
+	"github.com/mrthoabby/portfolio-api/internal/common/types"
 )
 
 type Repository struct {
@@ -16,10 +20,22 @@ func NewRepository(dataSource contracts.DataSource) *Repository {
 	}
 }
 
-func (r *Repository) GetByProfileID(ctx context.Context, profileID string) ([]Certificate, error) {
+// GetByProfileID returns the certificates of a profile, most recently issued first.
+// status narrows the result to active or expired certificates as of now; empty returns all.
+func (r *Repository) GetByProfileID(ctx context.Context, profileID, status string, now time.Time) ([]Certificate, error) {
 	var certificates []Certificate
 	filter := map[string]interface{}{"profileId": profileID}
-	sortFields := []string{"name"}
+	sortFields := []string{"-issuedAt", "name"}
+
+	switch status {
+	case StatusActive:
+		filter["$or"] = []map[string]interface{}{
+			{"expiresAt": nil},
+			{"expiresAt": map[string]interface{}{"$gt": now}},
+		}
+	case StatusExpired:
+		filter["expiresAt"] = map[string]interface{}{"$lte": now}
+	}
 
 	err := r.store.FindMany(ctx, filter, sortFields, &certificates)
 	if err != nil {
@@ -28,3 +44,96 @@ func (r *Repository) GetByProfileID(ctx context.Context, profileID string) ([]Ce
 
 	return certificates, nil
 }
+
+func (r *Repository) GetByID(ctx context.Context, profileID, id string) (*Certificate, error) {
+	var certificate Certificate
+	err := r.store.FindOne(ctx, map[string]interface{}{"_id": id, "profileId": profileID}, &certificate)
+	if err != nil {
+		if types.IsNotFoundError(err) {
+			return nil, types.ErrNotFound{Message: "certificate not found"}
+		}
+		return nil, err
+	}
+	return &certificate, nil
+}
+
+func (r *Repository) Create(ctx context.Context, profileID string, certificate *Request) (*Certificate, error) {
+	newCertificate := &Certificate{
+		ID:            uuid.New().String(),
+		ProfileID:     profileID,
+		Name:          certificate.Name,
+		Issuer:        certificate.Issuer,
+		CredentialID:  certificate.CredentialID,
+		CredentialURL: certificate.CredentialURL,
+		Skills:        certificate.Skills,
+		IssuedAt:      certificate.IssuedAt,
+		ExpiresAt:     certificate.ExpiresAt,
+	}
+	if newCertificate.Skills == nil {
+		newCertificate.Skills = []string{}
+	}
+
+	if err := r.store.InsertOne(ctx, newCertificate); err != nil {
+		return nil, err
+	}
+	return newCertificate, nil
+}
+
+// Update replaces the writable fields of a certificate, removing optional fields the request omits
+func (r *Repository) Update(ctx context.Context, profileID, id string, certificate *Request) error {
+	skills := certificate.Skills
+	if skills == nil {
+		skills = []string{}
+	}
+
+	update := contracts.Update{
+		Set: map[string]interface{}{
+			"name":     certificate.Name,
+			"issuer":   certificate.Issuer,
+			"skills":   skills,
+			"issuedAt": *certificate.IssuedAt,
+		},
+	}
+
+	if certificate.CredentialID != nil {
+		update.Set["credentialId"] = *certificate.CredentialID
+	} else {
+		update.Unset = append(update.Unset, "credentialId")
+	}
+	if certificate.CredentialURL != nil {
+		update.Set["credentialUrl"] = *certificate.CredentialURL
+	} else {
+		update.Unset = append(update.Unset, "credentialUrl")
+	}
+	if certificate.ExpiresAt != nil {
+		update.Set["expiresAt"] = *certificate.ExpiresAt
+	} else {
+		update.Unset = append(update.Unset, "expiresAt")
+	}
+
+	err := r.store.UpdateOneWith(ctx, map[string]interface{}{"_id": id, "profileId": profileID}, update)
+	if err != nil {
+		if types.IsNotFoundError(err) {
+			return types.ErrNotFound{Message: "certificate not found"}
+		}
+		return err
+	}
+	return nil
+}
+
+// DeleteByProfileID deletes every certificate of a profile
+func (r *Repository) DeleteByProfileID(ctx context.Context, profileID string) error {
+	_, err := r.store.DeleteMany(ctx, map[string]interface{}{"profileId": profileID})
+	return err
+}
+
+func (r *Repository) Delete(ctx context.Context, profileID, id string) error {
+	err := r.store.DeleteOne(ctx, map[string]interface{}{"_id": id, "profileId": profileID})
+	if err != nil {
+		if types.IsNotFoundError(err) {
+			return types.ErrNotFound{Message: "certificate not found"}
+		}
+		return err
+	}
+	return nil
+}
@@ -2,37 +2,104 @@ package certificates
 
 import (
 	"context"
-	"errors"
+	"time"
 
+	"github.com/mrthoabby/portfolio-api/internal/access"
 	"github.com/mrthoabby/portfolio-api/internal/application/profile"
+	"github.com/mrthoabby/portfolio-api/internal/common/types"
 )
 
 type Service struct {
 	repo           *Repository
 	profileService *profile.Service
+	authorizer     *access.Authorizer
+	now            func() time.Time
 }
 
-func NewService(repo *Repository, profileService *profile.Service) *Service {
+func NewService(repo *Repository, profileService *profile.Service, authorizer *access.Authorizer) *Service {
 	return &Service{
 		repo:           repo,
 		profileService: profileService,
+		authorizer:     authorizer,
+		now:            time.Now,
 	}
 }
 
-func (s *Service) GetByProfileID(ctx context.Context, profileID string) ([]Certificate, error) {
+// GetByProfileID returns the certificates of a profile with their expired flag set.
+// status is StatusActive, StatusExpired or empty for all certificates.
+func (s *Service) GetByProfileID(ctx context.Context, profileID, status string) ([]Certificate, error) {
 	// Verify profile exists
 	exists, err := s.profileService.Exists(ctx, profileID)
 	if err != nil {
 		return nil, err
 	}
 	if !exists {
-		return nil, errors.New("profile not found")
+		return nil, types.ErrNotFound{Message: "profile not found"}
 	}
 
-	certificates, err := s.repo.GetByProfileID(ctx, profileID)
+	return s.ListByProfileID(ctx, profileID, status)
+}
+
+// ListByProfileID is GetByProfileID without the profile existence check,
+// for callers that already validated the profile
+func (s *Service) ListByProfileID(ctx context.Context, profileID, status string) ([]Certificate, error) {
+	now := s.now()
+	certificates, err := s.repo.GetByProfileID(ctx, profileID, status, now)
 	if err != nil {
 		return nil, err
 	}
+	for i := range certificates {
+		certificates[i].Expired = certificates[i].IsExpired(now)
+	}
 	return certificates, nil
 }
 
+func (s *Service) Create(ctx context.Context, profileID string, certificate *Request) (*Certificate, error) {
+	if err := s.authorize(ctx, profileID); err != nil {
+		return nil, err
+	}
+
+	created, err := s.repo.Create(ctx, profileID, certificate)
+	if err != nil {
+		return nil, err
+	}
+	created.Expired = created.IsExpired(s.now())
+	return created, nil
+}
+
+// Update replaces a certificate and returns the stored result
+func (s *Service) Update(ctx context.Context, profileID, id string, certificate *Request) (*Certificate, error) {
+	if err := s.authorize(ctx, profileID); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(ctx, profileID, id, certificate); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.repo.GetByID(ctx, profileID, id)
+	if err != nil {
+		return nil, err
+	}
+	updated.Expired = updated.IsExpired(s.now())
+	return updated, nil
+}
+
+func (s *Service) Delete(ctx context.Context, profileID, id string) error {
+	if err := s.authorize(ctx, profileID); err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, profileID, id)
+}
+
+func (s *Service) authorize(ctx context.Context, profileID string) error {
+	// Verify profile exists
+	exists, err := s.profileService.Exists(ctx, profileID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return types.ErrNotFound{Message: "profile not found"}
+	}
+
+	return s.authorizer.Authorize(ctx, profileID, access.ActionEdit)
+}
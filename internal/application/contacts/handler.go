@@ -1,64 +1,66 @@
 package contacts
 
 import (
-	"encoding/json"
+	"errors"
 	"net/http"
-
-	"github.com/go-playground/validator/v10"
+	"strings"
 
 	"github.com/mrthoabby/portfolio-api/internal/common"
+	"github.com/mrthoabby/portfolio-api/internal/spam"
 )
 
 type Handler struct {
 	service   *Service
-	validator *validator.Validate
+	validator *common.Validator
 }
 
 func NewHandler(service *Service) *Handler {
 	return &Handler{
 		service:   service,
-		validator: validator.New(),
+		validator: common.NewValidator(),
 	}
 }
 
 func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
 	profileID := r.PathValue("id")
 	if profileID == "" {
-		common.RespondError(w, http.StatusBadRequest, "BAD_REQUEST", "Profile ID is required", nil)
+		common.RespondError(w, r, http.StatusBadRequest, "BAD_REQUEST", "Profile ID is required", nil)
 		return
 	}
 
 	// Validate UUID format
 	if !common.IsValidUUID(profileID) {
-		common.RespondError(w, http.StatusBadRequest, "BAD_REQUEST", "Invalid profile ID format", nil)
+		common.RespondError(w, r, http.StatusBadRequest, "BAD_REQUEST", "Invalid profile ID format", nil)
 		return
 	}
 
 	var contactReq Request
-	if err := json.NewDecoder(r.Body).Decode(&contactReq); err != nil {
-		// Check if body was too large
-		if err.Error() == "http: request body too large" {
-			common.RespondError(w, http.StatusRequestEntityTooLarge, "PAYLOAD_TOO_LARGE", "Request body too large", nil)
-			return
-		}
-		common.RespondError(w, http.StatusBadRequest, "BAD_REQUEST", "Invalid request body", nil)
+	if err := common.DecodeJSON(r, &contactReq); err != nil {
+		common.RespondServiceError(w, r, err)
 		return
 	}
 
+	// Screen the input as sent: sanitizing strips the markup spam often hides links in
+	submission := spam.Submission{
+		Honeypot:  contactReq.Website,
+		FormToken: contactReq.FormToken,
+		Text:      []string{contactReq.Name, contactReq.Message},
+	}
+
 	// Sanitize inputs before validation
 	sanitized := common.SanitizeContactInput(contactReq.Name, contactReq.Email, contactReq.Message)
 	contactReq.Name = sanitized.Name
 	contactReq.Email = sanitized.Email
 	contactReq.Message = sanitized.Message
 
-	if err := h.validator.Struct(&contactReq); err != nil {
-		common.RespondError(w, http.StatusBadRequest, "VALIDATION_ERROR", "Validation failed", err.Error())
+	if err := h.validator.Validate(r, &contactReq); err != nil {
+		common.RespondServiceError(w, r, err)
 		return
 	}
 
-	contact, err := h.service.Create(r.Context(), profileID, &contactReq)
+	contact, err := h.service.Create(r.Context(), profileID, &contactReq, submission)
 	if err != nil {
-		common.RespondError(w, http.StatusNotFound, "NOT_FOUND", err.Error(), nil)
+		common.RespondServiceError(w, r, err)
 		return
 	}
 
@@ -67,7 +69,242 @@ func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
 		Message:     "Contact message sent successfully",
 		ContactedAt: contact.ContactedAt,
 	}
+	if contact.Status == StatusUnverified {
+		response.Message = "Please confirm your email address with the link sent to you to deliver your message"
+		response.VerificationRequired = true
+	}
 
 	common.RespondJSON(w, http.StatusCreated, response)
 }
 
+// Verify handles GET /contacts/verify?token=, the link emailed to contact senders
+func (h *Handler) Verify(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		common.RespondError(w, r, http.StatusBadRequest, "BAD_REQUEST", "Verification token is required", nil)
+		return
+	}
+
+	if _, err := h.service.Verify(r.Context(), token); err != nil {
+		common.RespondServiceError(w, r, err)
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, VerifyResponse{Message: "Email address confirmed, your message was delivered"})
+}
+
+// FormToken handles GET /profiles/{id}/contacts/form-token, called when the contact form is rendered
+func (h *Handler) FormToken(w http.ResponseWriter, r *http.Request) {
+	profileID := r.PathValue("id")
+	if !common.IsValidUUID(profileID) {
+		common.RespondError(w, r, http.StatusBadRequest, "BAD_REQUEST", "Invalid profile ID format", nil)
+		return
+	}
+
+	token, err := h.service.FormToken(r.Context(), profileID)
+	if err != nil {
+		common.RespondServiceError(w, r, err)
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, token)
+}
+
+// GetByProfileID handles GET /admin/profiles/{id}/contacts.
+// Supported query parameters: status (comma separated), assignee ("none" for unassigned),
+// hasNotes, contacted, from, to (RFC 3339), cursor and limit.
+func (h *Handler) GetByProfileID(w http.ResponseWriter, r *http.Request) {
+	profileID := r.PathValue("id")
+	if !common.IsValidUUID(profileID) {
+		common.RespondError(w, r, http.StatusBadRequest, "BAD_REQUEST", "Invalid profile ID format", nil)
+		return
+	}
+
+	filter, err := parseListFilter(r)
+	if err != nil {
+		common.RespondError(w, r, http.StatusBadRequest, "BAD_REQUEST", err.Error(), nil)
+		return
+	}
+
+	contacts, hasMore, err := h.service.GetByProfileID(r.Context(), profileID, filter)
+	if err != nil {
+		common.RespondServiceError(w, r, err)
+		return
+	}
+
+	response := PageResponse{Contacts: contacts}
+	if response.Contacts == nil {
+		response.Contacts = []Contact{}
+	}
+	if hasMore {
+		last := contacts[len(contacts)-1]
+		response.NextCursor = common.Cursor{CreatedAt: last.CreatedAt, ID: last.ID}.Encode()
+	}
+
+	common.RespondJSON(w, http.StatusOK, response)
+}
+
+// GetByID handles GET /admin/profiles/{id}/contacts/{contactId}
+func (h *Handler) GetByID(w http.ResponseWriter, r *http.Request) {
+	profileID, contactID, ok := contactIDsFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	contact, err := h.service.GetByID(r.Context(), profileID, contactID)
+	if err != nil {
+		common.RespondServiceError(w, r, err)
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, contact)
+}
+
+// MarkContacted handles POST /admin/profiles/{id}/contacts/{contactId}/contacted
+func (h *Handler) MarkContacted(w http.ResponseWriter, r *http.Request) {
+	profileID, contactID, ok := contactIDsFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	contact, err := h.service.MarkContacted(r.Context(), profileID, contactID)
+	if err != nil {
+		common.RespondServiceError(w, r, err)
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, contact)
+}
+
+// UpdateStatus handles PUT /admin/profiles/{id}/contacts/{contactId}/status
+func (h *Handler) UpdateStatus(w http.ResponseWriter, r *http.Request) {
+	profileID, contactID, ok := contactIDsFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	var statusReq StatusRequest
+	if !h.decodeAndValidate(w, r, &statusReq) {
+		return
+	}
+
+	contact, err := h.service.Transition(r.Context(), profileID, contactID, statusReq.Status)
+	if err != nil {
+		common.RespondServiceError(w, r, err)
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, contact)
+}
+
+// AddNote handles POST /admin/profiles/{id}/contacts/{contactId}/notes
+func (h *Handler) AddNote(w http.ResponseWriter, r *http.Request) {
+	profileID, contactID, ok := contactIDsFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	var noteReq NoteRequest
+	if !h.decodeAndValidate(w, r, &noteReq) {
+		return
+	}
+
+	contact, err := h.service.AddNote(r.Context(), profileID, contactID, common.SanitizeString(noteReq.Body))
+	if err != nil {
+		common.RespondServiceError(w, r, err)
+		return
+	}
+
+	common.RespondJSON(w, http.StatusCreated, contact)
+}
+
+// Assign handles PUT /admin/profiles/{id}/contacts/{contactId}/assignee
+func (h *Handler) Assign(w http.ResponseWriter, r *http.Request) {
+	profileID, contactID, ok := contactIDsFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	var assigneeReq AssigneeRequest
+	if !h.decodeAndValidate(w, r, &assigneeReq) {
+		return
+	}
+
+	contact, err := h.service.Assign(r.Context(), profileID, contactID, strings.TrimSpace(assigneeReq.Assignee))
+	if err != nil {
+		common.RespondServiceError(w, r, err)
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, contact)
+}
+
+func (h *Handler) decodeAndValidate(w http.ResponseWriter, r *http.Request, dst interface{}) bool {
+	if err := common.DecodeJSON(r, dst); err != nil {
+		common.RespondServiceError(w, r, err)
+		return false
+	}
+
+	if err := h.validator.Validate(r, dst); err != nil {
+		common.RespondServiceError(w, r, err)
+		return false
+	}
+	return true
+}
+
+func contactIDsFromPath(w http.ResponseWriter, r *http.Request) (string, string, bool) {
+	profileID := r.PathValue("id")
+	contactID := r.PathValue("contactId")
+	if !common.IsValidUUID(profileID) || !common.IsValidUUID(contactID) {
+		common.RespondError(w, r, http.StatusBadRequest, "BAD_REQUEST", "Invalid profile or contact ID format", nil)
+		return "", "", false
+	}
+	return profileID, contactID, true
+}
+
+func parseListFilter(r *http.Request) (ListFilter, error) {
+	query := r.URL.Query()
+	var filter ListFilter
+	var err error
+
+	if filter.Limit, err = common.ParsePageLimit(query); err != nil {
+		return filter, err
+	}
+	if filter.Contacted, err = common.ParseBoolParam(query, "contacted"); err != nil {
+		return filter, err
+	}
+	if filter.HasNotes, err = common.ParseBoolParam(query, "hasNotes"); err != nil {
+		return filter, err
+	}
+
+	if value := query.Get("status"); value != "" {
+		for _, status := range strings.Split(value, ",") {
+			status = strings.TrimSpace(status)
+			if !IsValidStatus(status) {
+				return filter, errors.New("status must be one of new, read, replied, archived, spam")
+			}
+			filter.Statuses = append(filter.Statuses, status)
+		}
+	}
+
+	if query.Has("assignee") {
+		assignee := strings.TrimSpace(query.Get("assignee"))
+		filter.Assignee = &assignee
+	}
+	if filter.From, err = common.ParseTimeParam(query, "from"); err != nil {
+		return filter, err
+	}
+	if filter.To, err = common.ParseTimeParam(query, "to"); err != nil {
+		return filter, err
+	}
+
+	if value := query.Get("cursor"); value != "" {
+		cursor, err := common.DecodeCursor(value)
+		if err != nil {
+			return filter, err
+		}
+		filter.Cursor = &cursor
+	}
+
+	return filter, nil
+}
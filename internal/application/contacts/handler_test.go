@@ -29,6 +29,7 @@ func TestHandler_Create_InvalidJSON(t *testing.T) {
 	handler := NewHandler(service)
 
 	req := httptest.NewRequest("POST", "/api/v1/profiles/test-id/contacts", strings.NewReader("invalid json"))
+	req.Header.Set("Content-Type", "application/json")
 	req = req.WithContext(context.Background())
 	req.SetPathValue("id", "test-id")
 	w := httptest.NewRecorder()
@@ -38,6 +39,36 @@ func TestHandler_Create_InvalidJSON(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
 
+func TestHandler_Create_RejectsBody(t *testing.T) {
+	tests := []struct {
+		name           string
+		contentType    string
+		body           string
+		expectedStatus int
+		expectedCode   string
+	}{
+		{"form encoded", "application/x-www-form-urlencoded", "name=Test", http.StatusUnsupportedMediaType, "UNSUPPORTED_MEDIA_TYPE"},
+		{"unknown field", "application/json", `{"name": "Test User", "email": "test@example.com", "message": "Hello there, friend", "admin": true}`, http.StatusBadRequest, "BAD_REQUEST"},
+		{"trailing garbage", "application/json", `{"name": "Test User"} {}`, http.StatusBadRequest, "BAD_REQUEST"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewHandler(&Service{})
+
+			req := httptest.NewRequest("POST", "/api/v1/profiles/550e8400-e29b-41d4-a716-446655440000/contacts", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			req.SetPathValue("id", "550e8400-e29b-41d4-a716-446655440000")
+			w := httptest.NewRecorder()
+
+			handler.Create(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tt.expectedCode)
+		})
+	}
+}
+
 func TestHandler_Create_InvalidRequest(t *testing.T) {
 	service := &Service{}
 	handler := NewHandler(service)
@@ -50,6 +81,7 @@ func TestHandler_Create_InvalidRequest(t *testing.T) {
 
 	body, _ := json.Marshal(contactReq)
 	req := httptest.NewRequest("POST", "/api/v1/profiles/test-id/contacts", strings.NewReader(string(body)))
+	req.Header.Set("Content-Type", "application/json")
 	req = req.WithContext(context.Background())
 	req.SetPathValue("id", "test-id")
 	w := httptest.NewRecorder()
@@ -67,3 +99,100 @@ func TestNewHandler(t *testing.T) {
 	assert.NotNil(t, handler.validator)
 }
 
+
+func TestHandler_GetByProfileID_InvalidQuery(t *testing.T) {
+	handler := NewHandler(&Service{})
+
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{"invalid contacted", "contacted=maybe"},
+		{"invalid from", "from=yesterday"},
+		{"invalid limit", "limit=0"},
+		{"invalid cursor", "cursor=%25%25"},
+		{"invalid status", "status=new,deleted"},
+		{"invalid hasNotes", "hasNotes=sometimes"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/api/v1/admin/profiles/550e8400-e29b-41d4-a716-446655440000/contacts?"+tt.query, nil)
+			req.SetPathValue("id", "550e8400-e29b-41d4-a716-446655440000")
+			w := httptest.NewRecorder()
+
+			handler.GetByProfileID(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+}
+
+func TestHandler_MarkContacted_InvalidID(t *testing.T) {
+	handler := NewHandler(&Service{})
+
+	req := httptest.NewRequest("POST", "/api/v1/admin/profiles/550e8400-e29b-41d4-a716-446655440000/contacts/not-a-uuid/contacted", nil)
+	req.SetPathValue("id", "550e8400-e29b-41d4-a716-446655440000")
+	req.SetPathValue("contactId", "not-a-uuid")
+	w := httptest.NewRecorder()
+
+	handler.MarkContacted(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestHandler_UpdateStatus_InvalidStatus(t *testing.T) {
+	handler := NewHandler(&Service{})
+
+	req := httptest.NewRequest("PUT", "/api/v1/admin/profiles/550e8400-e29b-41d4-a716-446655440000/contacts/6ba7b810-9dad-11d1-80b4-00c04fd430c8/status", strings.NewReader(`{"status":"deleted"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.SetPathValue("id", "550e8400-e29b-41d4-a716-446655440000")
+	req.SetPathValue("contactId", "6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+	w := httptest.NewRecorder()
+
+	handler.UpdateStatus(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "VALIDATION_ERROR")
+}
+
+func TestHandler_AddNote_EmptyBody(t *testing.T) {
+	handler := NewHandler(&Service{})
+
+	req := httptest.NewRequest("POST", "/api/v1/admin/profiles/550e8400-e29b-41d4-a716-446655440000/contacts/6ba7b810-9dad-11d1-80b4-00c04fd430c8/notes", strings.NewReader(`{"body":""}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.SetPathValue("id", "550e8400-e29b-41d4-a716-446655440000")
+	req.SetPathValue("contactId", "6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+	w := httptest.NewRecorder()
+
+	handler.AddNote(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestHandler_FormToken_InvalidID(t *testing.T) {
+	handler := NewHandler(&Service{})
+
+	req := httptest.NewRequest("GET", "/api/v1/profiles/not-a-uuid/contacts/form-token", nil)
+	req.SetPathValue("id", "not-a-uuid")
+	w := httptest.NewRecorder()
+
+	handler.FormToken(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestHandler_Verify_InvalidToken(t *testing.T) {
+	handler := NewHandler(&Service{})
+
+	for _, query := range []string{"", "token=contact-1.123.signature"} {
+		t.Run(query, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/api/v1/contacts/verify?"+query, nil)
+			w := httptest.NewRecorder()
+
+			handler.Verify(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+}
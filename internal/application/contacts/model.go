@@ -1,26 +1,107 @@
 package contacts
 
-import "time"
+import (
+	"time"
 
+	"github.com/mrthoabby/portfolio-api/internal/common"
+)
+
+// Contact is a message left through the contact form. Contacts whose sender must confirm their
+// email address are held as unverified until VerifyBy, when they are deleted.
 type Contact struct {
-	ID          string    `json:"id" bson:"_id,omitempty"`
-	ProfileID   string    `json:"profileId" bson:"profileId"`
-	Name        string    `json:"name" bson:"name" validate:"required,min=2,max=100"`
-	Email       string    `json:"email" bson:"email" validate:"required,email"`
-	Message     string    `json:"message" bson:"message" validate:"required,min=10,max=1000"`
-	Contacted   bool      `json:"contacted" bson:"contacted"`
-	ContactedAt time.Time `json:"contactedAt" bson:"contactedAt"`
-	CreatedAt   time.Time `json:"createdAt" bson:"createdAt"`
+	ID          string       `json:"id" bson:"_id,omitempty"`
+	ProfileID   string       `json:"profileId" bson:"profileId"`
+	Name        string       `json:"name" bson:"name" validate:"required,min=2,max=100"`
+	Email       string       `json:"email" bson:"email" validate:"required,email"`
+	Message     string       `json:"message" bson:"message" validate:"required,min=10,max=1000"`
+	Status      string       `json:"status" bson:"status"`
+	Spam        bool         `json:"spam" bson:"spam"`
+	SpamScore   int          `json:"spamScore,omitempty" bson:"spamScore,omitempty"`
+	SpamReasons []string     `json:"spamReasons,omitempty" bson:"spamReasons,omitempty"`
+	History     []Transition `json:"history" bson:"history"`
+	Notes       []Note       `json:"notes" bson:"notes"`
+	Assignee    string       `json:"assignee,omitempty" bson:"assignee,omitempty"`
+	Contacted   bool         `json:"contacted" bson:"contacted"`
+	ContactedAt time.Time    `json:"contactedAt" bson:"contactedAt"`
+	VerifyBy    *time.Time   `json:"-" bson:"verifyBy,omitempty"`
+	VerifiedAt  *time.Time   `json:"verifiedAt,omitempty" bson:"verifiedAt,omitempty"`
+	CreatedAt   time.Time    `json:"createdAt" bson:"createdAt"`
+	UpdatedAt   time.Time    `json:"updatedAt" bson:"updatedAt"`
+}
+
+// Transition records a status change of a contact
+type Transition struct {
+	From string    `json:"from,omitempty" bson:"from,omitempty"`
+	To   string    `json:"to" bson:"to"`
+	By   string    `json:"by,omitempty" bson:"by,omitempty"`
+	At   time.Time `json:"at" bson:"at"`
+}
+
+// Note is a private annotation left by a profile member
+type Note struct {
+	ID        string    `json:"id" bson:"id"`
+	Body      string    `json:"body" bson:"body"`
+	Author    string    `json:"author" bson:"author"`
+	CreatedAt time.Time `json:"createdAt" bson:"createdAt"`
 }
 
 type Request struct {
 	Name    string `json:"name" validate:"required,min=2,max=100"`
 	Email   string `json:"email" validate:"required,email"`
 	Message string `json:"message" validate:"required,min=10,max=1000"`
+	// Website is a honeypot: the form hides it, so only bots fill it in
+	Website string `json:"website"`
+	// FormToken is the token issued when the form was rendered
+	FormToken string `json:"formToken"`
 }
 
 type Response struct {
 	ID          string    `json:"id"`
 	Message     string    `json:"message"`
 	ContactedAt time.Time `json:"contactedAt"`
+	// VerificationRequired is set when the message is only delivered once the sender follows
+	// the link emailed to them
+	VerificationRequired bool `json:"verificationRequired"`
+}
+
+// VerifyResponse confirms that a contact was verified
+type VerifyResponse struct {
+	Message string `json:"message"`
+}
+
+// FormTokenResponse is issued when the contact form is rendered and must be sent back with it
+type FormTokenResponse struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+type StatusRequest struct {
+	Status string `json:"status" validate:"required,oneof=new read replied archived spam"`
+}
+
+type NoteRequest struct {
+	Body string `json:"body" validate:"required,min=1,max=2000"`
+}
+
+// AssigneeRequest assigns a contact to a profile member; an empty assignee unassigns it
+type AssigneeRequest struct {
+	Assignee string `json:"assignee" validate:"max=200"`
+}
+
+// ListFilter selects the contacts returned by the admin inbox
+type ListFilter struct {
+	Statuses  []string
+	Assignee  *string
+	HasNotes  *bool
+	Contacted *bool
+	From      *time.Time
+	To        *time.Time
+	Cursor    *common.Cursor
+	Limit     int
+}
+
+// PageResponse is a page of the admin inbox
+type PageResponse struct {
+	Contacts   []Contact `json:"contacts"`
+	NextCursor string    `json:"nextCursor,omitempty"`
 }
@@ -6,9 +6,15 @@ import (
 
 	"github.com/google/uuid"
 
+	"github.com/mrthoabby/portfolio-api/internal/common"
 	"github.com/mrthoabby/portfolio-api/internal/common/contracts"
+	"github.com/mrthoabby/portfolio-api/internal/common/types"
+	"github.com/mrthoabby/portfolio-api/internal/spam"
 )
 
+// AssigneeNone filters the inbox on contacts without an assignee
+const AssigneeNone = "none"
+
 type Repository struct {
 	store contracts.Store
 }
@@ -19,16 +25,41 @@ func NewRepository(dataSource contracts.DataSource) *Repository {
 	}
 }
 
-func (r *Repository) Create(ctx context.Context, profileID string, contact *Request) (*Contact, error) {
+// EnsureIndexes creates the index deleting the contacts that were not verified in time
+func (r *Repository) EnsureIndexes(ctx context.Context) error {
+	var expireAt time.Duration
+	return r.store.EnsureIndex(ctx, contracts.Index{Fields: []string{"verifyBy"}, ExpireAfter: &expireAt})
+}
+
+// Create stores a new contact; contacts the spam filter flagged start in the spam status.
+// Other contacts are held as unverified until verifyBy when it is set.
+func (r *Repository) Create(ctx context.Context, profileID string, contact *Request, verdict spam.Verdict, verifyBy *time.Time) (*Contact, error) {
 	now := time.Now()
+	status := StatusNew
+	switch {
+	case verdict.Spam:
+		status = StatusSpam
+		verifyBy = nil
+	case verifyBy != nil:
+		status = StatusUnverified
+	}
+
 	newContact := &Contact{
-		ID:        uuid.New().String(),
-		ProfileID: profileID,
-		Name:      contact.Name,
-		Email:     contact.Email,
-		Message:   contact.Message,
-		Contacted: false,
-		CreatedAt: now,
+		ID:          uuid.New().String(),
+		ProfileID:   profileID,
+		Name:        contact.Name,
+		Email:       contact.Email,
+		Message:     contact.Message,
+		Status:      status,
+		Spam:        verdict.Spam,
+		SpamScore:   verdict.Score,
+		SpamReasons: verdict.Reasons,
+		History:     []Transition{{To: status, At: now}},
+		Notes:       []Note{},
+		Contacted:   false,
+		VerifyBy:    verifyBy,
+		CreatedAt:   now,
+		UpdatedAt:   now,
 	}
 
 	err := r.store.InsertOne(ctx, newContact)
@@ -38,3 +69,226 @@ func (r *Repository) Create(ctx context.Context, profileID string, contact *Requ
 
 	return newContact, nil
 }
+
+// GetByProfileID returns one page of contacts, newest first.
+// It fetches one extra record to know whether another page follows.
+func (r *Repository) GetByProfileID(ctx context.Context, profileID string, listFilter ListFilter) ([]Contact, bool, error) {
+	filter := map[string]interface{}{"profileId": profileID}
+
+	if len(listFilter.Statuses) > 0 {
+		filter["status"] = map[string]interface{}{"$in": storedStatuses(listFilter.Statuses)}
+	} else {
+		filter["status"] = notUnverified
+	}
+
+	if listFilter.Assignee != nil {
+		if *listFilter.Assignee == AssigneeNone {
+			filter["assignee"] = nil
+		} else {
+			filter["assignee"] = *listFilter.Assignee
+		}
+	}
+
+	if listFilter.HasNotes != nil {
+		filter["notes.0"] = map[string]interface{}{"$exists": *listFilter.HasNotes}
+	}
+
+	if listFilter.Contacted != nil {
+		filter["contacted"] = *listFilter.Contacted
+	}
+
+	createdAt := map[string]interface{}{}
+	if listFilter.From != nil {
+		createdAt["$gte"] = *listFilter.From
+	}
+	if listFilter.To != nil {
+		createdAt["$lte"] = *listFilter.To
+	}
+	if len(createdAt) > 0 {
+		filter["createdAt"] = createdAt
+	}
+
+	if listFilter.Cursor != nil {
+		filter["$or"] = common.AfterCursorFilter(*listFilter.Cursor)
+	}
+
+	var contacts []Contact
+	opts := contracts.FindOptions{
+		Sort:  []string{"-createdAt", "-_id"},
+		Limit: int64(listFilter.Limit) + 1,
+	}
+	if err := r.store.FindManyWithOptions(ctx, filter, opts, &contacts); err != nil {
+		return nil, false, err
+	}
+
+	hasMore := len(contacts) > listFilter.Limit
+	if hasMore {
+		contacts = contacts[:listFilter.Limit]
+	}
+	return contacts, hasMore, nil
+}
+
+// GetByID returns a contact of the profile; unverified contacts are not found
+func (r *Repository) GetByID(ctx context.Context, profileID, id string) (*Contact, error) {
+	var contact Contact
+	err := r.store.FindOne(ctx, map[string]interface{}{"_id": id, "profileId": profileID, "status": notUnverified}, &contact)
+	if err != nil {
+		if types.IsNotFoundError(err) {
+			return nil, types.ErrNotFound{Message: "contact not found"}
+		}
+		return nil, err
+	}
+	return &contact, nil
+}
+
+// Transition moves a contact from one status to another and appends the change to its history.
+// The update only applies while the contact is still in the from status, so concurrent
+// transitions cannot both succeed; the loser gets ErrInvalidTransition.
+func (r *Repository) Transition(ctx context.Context, contact *Contact, to, by string) error {
+	from := contact.CurrentStatus()
+	now := time.Now()
+
+	filter := map[string]interface{}{
+		"_id":       contact.ID,
+		"profileId": contact.ProfileID,
+		"status":    map[string]interface{}{"$in": storedStatuses([]string{from})},
+	}
+	set := map[string]interface{}{
+		"status":    to,
+		"updatedAt": now,
+	}
+	if to == StatusReplied && !contact.Contacted {
+		set["contacted"] = true
+		set["contactedAt"] = now
+	}
+
+	update := contracts.Update{
+		Set:  set,
+		Push: map[string]interface{}{"history": Transition{From: from, To: to, By: by, At: now}},
+	}
+	// The spam flag follows the status, so contacts released from spam no longer read as spam
+	switch {
+	case to == StatusSpam:
+		set["spam"] = true
+	case from == StatusSpam:
+		set["spam"] = false
+		update.Unset = []string{"spamScore", "spamReasons"}
+	}
+
+	if err := r.store.UpdateOneWith(ctx, filter, update); err != nil {
+		if types.IsNotFoundError(err) {
+			return ErrInvalidTransition
+		}
+		return err
+	}
+	return nil
+}
+
+// MarkContacted records that the owner got back to the sender of a contact. Contacts the lifecycle
+// lets move to replied do so; archived and spam contacts keep their status and are only flagged.
+func (r *Repository) MarkContacted(ctx context.Context, contact *Contact, by string) error {
+	if CanTransition(contact.CurrentStatus(), StatusReplied) {
+		return r.Transition(ctx, contact, StatusReplied, by)
+	}
+	if contact.Contacted {
+		return nil
+	}
+
+	now := time.Now()
+	return r.update(ctx, contact.ProfileID, contact.ID, contracts.Update{
+		Set: map[string]interface{}{
+			"contacted":   true,
+			"contactedAt": now,
+			"updatedAt":   now,
+		},
+	})
+}
+
+// DeleteByProfileID deletes every contact of a profile, unverified ones included
+func (r *Repository) DeleteByProfileID(ctx context.Context, profileID string) error {
+	_, err := r.store.DeleteMany(ctx, map[string]interface{}{"profileId": profileID})
+	return err
+}
+
+// Verify moves an unverified contact to the new status. It reports false along with the contact
+// when the contact was verified before, so following a link twice is harmless.
+func (r *Repository) Verify(ctx context.Context, id string) (*Contact, bool, error) {
+	now := time.Now()
+	update := contracts.Update{
+		Set: map[string]interface{}{
+			"status":     StatusNew,
+			"verifiedAt": now,
+			"updatedAt":  now,
+		},
+		Unset: []string{"verifyBy"},
+		Push:  map[string]interface{}{"history": Transition{From: StatusUnverified, To: StatusNew, At: now}},
+	}
+
+	var contact Contact
+	filter := map[string]interface{}{"_id": id, "status": StatusUnverified}
+	err := r.store.FindOneAndUpdate(ctx, filter, update, contracts.FindOneAndUpdateOptions{}, &contact)
+	if err == nil {
+		return &contact, true, nil
+	}
+	if !types.IsNotFoundError(err) {
+		return nil, false, err
+	}
+
+	// Either verified already or expired and deleted
+	err = r.store.FindOne(ctx, map[string]interface{}{"_id": id, "verifiedAt": map[string]interface{}{"$exists": true}}, &contact)
+	if err != nil {
+		if types.IsNotFoundError(err) {
+			return nil, false, types.ErrNotFound{Message: "contact not found"}
+		}
+		return nil, false, err
+	}
+	return &contact, false, nil
+}
+
+func (r *Repository) AddNote(ctx context.Context, profileID, id string, note Note) error {
+	update := contracts.Update{
+		Set:  map[string]interface{}{"updatedAt": time.Now()},
+		Push: map[string]interface{}{"notes": note},
+	}
+	return r.update(ctx, profileID, id, update)
+}
+
+// SetAssignee assigns the contact to a subject, or unassigns it when assignee is empty
+func (r *Repository) SetAssignee(ctx context.Context, profileID, id, assignee string) error {
+	update := contracts.Update{
+		Set: map[string]interface{}{"updatedAt": time.Now()},
+	}
+	if assignee == "" {
+		update.Unset = []string{"assignee"}
+	} else {
+		update.Set["assignee"] = assignee
+	}
+	return r.update(ctx, profileID, id, update)
+}
+
+func (r *Repository) update(ctx context.Context, profileID, id string, update contracts.Update) error {
+	err := r.store.UpdateOneWith(ctx, map[string]interface{}{"_id": id, "profileId": profileID, "status": notUnverified}, update)
+	if err != nil {
+		if types.IsNotFoundError(err) {
+			return types.ErrNotFound{Message: "contact not found"}
+		}
+		return err
+	}
+	return nil
+}
+
+// notUnverified filters out the contacts still waiting for their sender to verify them
+var notUnverified = map[string]interface{}{"$ne": StatusUnverified}
+
+// storedStatuses expands statuses into the values they may have in the store:
+// contacts created before the lifecycle existed have no status and count as new
+func storedStatuses(statuses []string) []interface{} {
+	values := make([]interface{}, 0, len(statuses)+1)
+	for _, status := range statuses {
+		values = append(values, status)
+		if status == StatusNew {
+			values = append(values, nil)
+		}
+	}
+	return values
+}
@@ -0,0 +1,152 @@
+package contacts
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mrthoabby/portfolio-api/internal/common/contracts"
+	"github.com/mrthoabby/portfolio-api/internal/spam"
+)
+
+// insertStore records the records inserted into it
+type insertStore struct {
+	contracts.Store
+	inserted []interface{}
+}
+
+func (s *insertStore) InsertOne(_ context.Context, record interface{}) error {
+	s.inserted = append(s.inserted, record)
+	return nil
+}
+
+// contactStore holds a single contact, applying the status, spam and contacted fields of updates to it
+type contactStore struct {
+	contracts.Store
+	contact Contact
+}
+
+func (s *contactStore) UpdateOneWith(_ context.Context, _ map[string]interface{}, update contracts.Update) error {
+	if status, ok := update.Set["status"]; ok {
+		s.contact.Status = status.(string)
+	}
+	if spam, ok := update.Set["spam"]; ok {
+		s.contact.Spam = spam.(bool)
+	}
+	if contacted, ok := update.Set["contacted"]; ok {
+		s.contact.Contacted = contacted.(bool)
+		s.contact.ContactedAt = update.Set["contactedAt"].(time.Time)
+	}
+	for _, field := range update.Unset {
+		switch field {
+		case "spamScore":
+			s.contact.SpamScore = 0
+		case "spamReasons":
+			s.contact.SpamReasons = nil
+		}
+	}
+	return nil
+}
+
+func TestRepository_Create_SpamVerdict(t *testing.T) {
+	request := &Request{Name: "Ada", Email: "ada@example.com", Message: "Hello there, friend"}
+
+	tests := []struct {
+		name           string
+		verdict        spam.Verdict
+		expectedStatus string
+	}{
+		{"legitimate", spam.Verdict{Score: 2, Reasons: []string{spam.ReasonLinks}}, StatusNew},
+		{"spam", spam.Verdict{Spam: true, Score: 6, Reasons: []string{spam.ReasonHoneypot, spam.ReasonPhrases}}, StatusSpam},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &insertStore{}
+			repo := &Repository{store: store}
+
+			contact, err := repo.Create(context.Background(), "profile-1", request, tt.verdict, nil)
+			assert.NoError(t, err)
+			assert.Equal(t, []interface{}{contact}, store.inserted)
+
+			assert.Equal(t, tt.expectedStatus, contact.Status)
+			assert.Equal(t, tt.verdict.Spam, contact.Spam)
+			assert.Equal(t, tt.verdict.Score, contact.SpamScore)
+			assert.Equal(t, tt.verdict.Reasons, contact.SpamReasons)
+			assert.Equal(t, []Transition{{To: tt.expectedStatus, At: contact.CreatedAt}}, contact.History)
+		})
+	}
+}
+
+func TestRepository_Create_Unverified(t *testing.T) {
+	request := &Request{Name: "Ada", Email: "ada@example.com", Message: "Hello there, friend"}
+	verifyBy := time.Now().Add(time.Hour)
+
+	store := &insertStore{}
+	repo := &Repository{store: store}
+
+	contact, err := repo.Create(context.Background(), "profile-1", request, spam.Verdict{}, &verifyBy)
+	assert.NoError(t, err)
+	assert.Equal(t, StatusUnverified, contact.Status)
+	assert.Equal(t, &verifyBy, contact.VerifyBy)
+
+	// Spam is not held for verification, so its sender is never emailed
+	contact, err = repo.Create(context.Background(), "profile-1", request, spam.Verdict{Spam: true}, &verifyBy)
+	assert.NoError(t, err)
+	assert.Equal(t, StatusSpam, contact.Status)
+	assert.Nil(t, contact.VerifyBy)
+}
+
+func TestRepository_Transition_Spam(t *testing.T) {
+	store := &contactStore{contact: Contact{
+		ID:          "contact-1",
+		ProfileID:   "profile-1",
+		Status:      StatusSpam,
+		Spam:        true,
+		SpamScore:   6,
+		SpamReasons: []string{spam.ReasonLinks},
+	}}
+	repo := &Repository{store: store}
+
+	// Released from spam, the contact no longer reads as spam
+	assert.NoError(t, repo.Transition(context.Background(), &store.contact, StatusNew, "owner"))
+	assert.Equal(t, StatusNew, store.contact.Status)
+	assert.False(t, store.contact.Spam)
+	assert.Zero(t, store.contact.SpamScore)
+	assert.Nil(t, store.contact.SpamReasons)
+
+	assert.NoError(t, repo.Transition(context.Background(), &store.contact, StatusRead, "owner"))
+	assert.False(t, store.contact.Spam)
+
+	// Marked as spam by hand, it reads as spam again
+	assert.NoError(t, repo.Transition(context.Background(), &store.contact, StatusSpam, "owner"))
+	assert.Equal(t, StatusSpam, store.contact.Status)
+	assert.True(t, store.contact.Spam)
+}
+
+func TestRepository_MarkContacted(t *testing.T) {
+	tests := []struct {
+		status         string
+		expectedStatus string
+	}{
+		{StatusNew, StatusReplied},
+		{StatusRead, StatusReplied},
+		// Outside of the lifecycle, the contact is only flagged
+		{StatusArchived, StatusArchived},
+		{StatusSpam, StatusSpam},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.status, func(t *testing.T) {
+			store := &contactStore{contact: Contact{ID: "contact-1", ProfileID: "profile-1", Status: tt.status}}
+			repo := &Repository{store: store}
+
+			assert.NoError(t, repo.MarkContacted(context.Background(), &store.contact, "owner"))
+			assert.Equal(t, tt.expectedStatus, store.contact.Status)
+			assert.True(t, store.contact.Contacted)
+			assert.False(t, store.contact.ContactedAt.IsZero())
+		})
+	}
+}
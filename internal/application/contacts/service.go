@@ -2,36 +2,318 @@ package contacts
 
 import (
 	"context"
-	"errors"
+	"time"
 
+	"github.com/google/uuid"
+
+	"github.com/mrthoabby/portfolio-api/internal/access"
 	"github.com/mrthoabby/portfolio-api/internal/application/profile"
+	"github.com/mrthoabby/portfolio-api/internal/application/settings"
+	"github.com/mrthoabby/portfolio-api/internal/common"
+	"github.com/mrthoabby/portfolio-api/internal/common/contracts"
+	"github.com/mrthoabby/portfolio-api/internal/common/types"
+	"github.com/mrthoabby/portfolio-api/internal/events"
+	"github.com/mrthoabby/portfolio-api/internal/spam"
+)
+
+var (
+	// ErrInvalidAssignee is returned when a contact is assigned to someone without access to the profile
+	ErrInvalidAssignee = types.ErrValidation{Message: "assignee must be a member of the profile"}
+
+	// ErrContactsDisabled is returned when the profile settings turned the contact form off
+	ErrContactsDisabled = types.ErrConflict{Code: "CONTACTS_DISABLED", Message: "this profile does not accept contact messages"}
 )
 
 type Service struct {
-	repo           *Repository
-	profileService *profile.Service
+	repo            *Repository
+	profileService  *profile.Service
+	settingsService *settings.Service
+	authorizer      *access.Authorizer
+	spamFilter      *spam.Filter
+	transactor      contracts.Transactor
+	publisher       events.Publisher
+	verifier        *Verifier
+	mailLimiter     MailLimiter
 }
 
-func NewService(repo *Repository, profileService *profile.Service) *Service {
+// MailLimiter caps the emails contact submissions make the API send to their sender
+type MailLimiter interface {
+	// AllowMail records an email to address requested from clientIP, returning how long to wait when it is over the limit
+	AllowMail(ctx context.Context, address, clientIP string) (time.Duration, bool)
+}
+
+// NewService creates the contacts service. With a verifier, contacts are only delivered to the
+// profile once their sender followed the verification link emailed to them. The mail limiter,
+// required when senders are emailed, rejects submissions that would email an address too often.
+func NewService(repo *Repository, profileService *profile.Service, settingsService *settings.Service, authorizer *access.Authorizer, spamFilter *spam.Filter, transactor contracts.Transactor, publisher events.Publisher, verifier *Verifier, mailLimiter MailLimiter) *Service {
 	return &Service{
-		repo:           repo,
-		profileService: profileService,
+		repo:            repo,
+		profileService:  profileService,
+		settingsService: settingsService,
+		authorizer:      authorizer,
+		spamFilter:      spamFilter,
+		transactor:      transactor,
+		publisher:       publisher,
+		verifier:        verifier,
+		mailLimiter:     mailLimiter,
+	}
+}
+
+// FormToken issues the token the contact form of a profile must be submitted with
+func (s *Service) FormToken(ctx context.Context, profileID string) (*FormTokenResponse, error) {
+	if _, err := s.intakeSettings(ctx, profileID); err != nil {
+		return nil, err
+	}
+
+	token, expiresAt := s.spamFilter.IssueFormToken(profileID)
+	return &FormTokenResponse{Token: token, ExpiresAt: expiresAt}, nil
+}
+
+// Create stores a contact after screening the submission it came from. Suspected spam is
+// stored with the spam status rather than dropped, so it can still be recovered from the inbox.
+// When verification is required, other contacts are held as unverified and their sender is
+// emailed a verification link; spam is never emailed, so forged addresses are not spammed back.
+func (s *Service) Create(ctx context.Context, profileID string, contact *Request, submission spam.Submission) (*Contact, error) {
+	profileSettings, err := s.intakeSettings(ctx, profileID)
+	if err != nil {
+		return nil, err
+	}
+
+	submission.Scope = profileID
+	submission.Threshold = profileSettings.SpamThreshold
+	verdict, err := s.spamFilter.Check(ctx, submission)
+	if err != nil {
+		return nil, err
+	}
+
+	// Spam is never emailed; anything else may be, so the form cannot be used to flood an address
+	if s.mailLimiter != nil && !verdict.Spam {
+		if retryAfter, ok := s.mailLimiter.AllowMail(ctx, contact.Email, common.ClientIPFromContext(ctx)); !ok {
+			return nil, types.ErrRateLimited{Message: "too many messages sent from this address, please try again later", RetryAfter: retryAfter}
+		}
+	}
+
+	var verifyBy *time.Time
+	if s.verifier != nil {
+		deadline := s.verifier.Deadline(time.Now())
+		verifyBy = &deadline
+	}
+
+	// The event is written with the contact, so its side effects cannot be lost
+	var createdContact *Contact
+	err = s.transactor.WithTransaction(ctx, func(ctx context.Context) error {
+		created, err := s.repo.Create(ctx, profileID, contact, verdict, verifyBy)
+		if err != nil {
+			return err
+		}
+
+		var event events.Event
+		if created.Status == StatusUnverified {
+			event, err = events.New(events.TypeContactVerificationRequested, profileID, events.ContactVerificationRequested{
+				ContactID: created.ID,
+				Name:      created.Name,
+				Email:     created.Email,
+				Message:   created.Message,
+				Token:     s.verifier.Issue(created.ID, *created.VerifyBy),
+				ExpiresAt: *created.VerifyBy,
+				CreatedAt: created.CreatedAt,
+			})
+		} else {
+			event, err = contactCreatedEvent(created)
+		}
+		if err != nil {
+			return err
+		}
+		if err := s.publisher.Publish(ctx, event); err != nil {
+			return err
+		}
+
+		createdContact = created
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return createdContact, nil
+}
+
+// Verify delivers the contact a verification link was issued for, as if it had just been created
+func (s *Service) Verify(ctx context.Context, token string) (*Contact, error) {
+	if s.verifier == nil {
+		return nil, ErrInvalidVerificationToken
+	}
+	id, err := s.verifier.Verify(token, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	var verifiedContact *Contact
+	err = s.transactor.WithTransaction(ctx, func(ctx context.Context) error {
+		verified, verifiedNow, err := s.repo.Verify(ctx, id)
+		if err != nil {
+			return err
+		}
+		verifiedContact = verified
+		if !verifiedNow {
+			return nil
+		}
+
+		event, err := contactCreatedEvent(verified)
+		if err != nil {
+			return err
+		}
+		return s.publisher.Publish(ctx, event)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return verifiedContact, nil
+}
+
+// GetByProfileID returns a page of the profile's contact inbox
+func (s *Service) GetByProfileID(ctx context.Context, profileID string, filter ListFilter) ([]Contact, bool, error) {
+	if err := s.authorize(ctx, profileID, access.ActionRead); err != nil {
+		return nil, false, err
+	}
+	return s.repo.GetByProfileID(ctx, profileID, filter)
+}
+
+func (s *Service) GetByID(ctx context.Context, profileID, id string) (*Contact, error) {
+	if err := s.authorize(ctx, profileID, access.ActionRead); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(ctx, profileID, id)
+}
+
+// Transition moves a contact to a new status, enforcing the lifecycle state machine
+func (s *Service) Transition(ctx context.Context, profileID, id, to string) (*Contact, error) {
+	if err := s.authorize(ctx, profileID, access.ActionEdit); err != nil {
+		return nil, err
 	}
+
+	contact, err := s.repo.GetByID(ctx, profileID, id)
+	if err != nil {
+		return nil, err
+	}
+	if !CanTransition(contact.CurrentStatus(), to) {
+		return nil, ErrInvalidTransition
+	}
+
+	if err := s.repo.Transition(ctx, contact, to, subjectFromContext(ctx)); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(ctx, profileID, id)
 }
 
-func (s *Service) Create(ctx context.Context, profileID string, contact *Request) (*Contact, error) {
+// MarkContacted records that the owner got back to the person behind a contact, moving it to
+// replied when its status allows. Contacts that were already marked are returned unchanged.
+func (s *Service) MarkContacted(ctx context.Context, profileID, id string) (*Contact, error) {
+	if err := s.authorize(ctx, profileID, access.ActionEdit); err != nil {
+		return nil, err
+	}
+
+	contact, err := s.repo.GetByID(ctx, profileID, id)
+	if err != nil {
+		return nil, err
+	}
+	if contact.CurrentStatus() == StatusReplied {
+		return contact, nil
+	}
+
+	if err := s.repo.MarkContacted(ctx, contact, subjectFromContext(ctx)); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(ctx, profileID, id)
+}
+
+func (s *Service) AddNote(ctx context.Context, profileID, id, body string) (*Contact, error) {
+	if err := s.authorize(ctx, profileID, access.ActionEdit); err != nil {
+		return nil, err
+	}
+
+	note := Note{
+		ID:        uuid.New().String(),
+		Body:      body,
+		Author:    subjectFromContext(ctx),
+		CreatedAt: time.Now(),
+	}
+	if err := s.repo.AddNote(ctx, profileID, id, note); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(ctx, profileID, id)
+}
+
+// Assign sets the profile member responsible for a contact; an empty assignee unassigns it
+func (s *Service) Assign(ctx context.Context, profileID, id, assignee string) (*Contact, error) {
+	if err := s.authorize(ctx, profileID, access.ActionEdit); err != nil {
+		return nil, err
+	}
+
+	if assignee != "" {
+		isMember, err := s.authorizer.IsMember(ctx, profileID, assignee)
+		if err != nil {
+			return nil, err
+		}
+		if !isMember {
+			return nil, ErrInvalidAssignee
+		}
+	}
+
+	if err := s.repo.SetAssignee(ctx, profileID, id, assignee); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(ctx, profileID, id)
+}
+
+// intakeSettings returns the settings of a profile accepting contacts
+func (s *Service) intakeSettings(ctx context.Context, profileID string) (*settings.Settings, error) {
 	// Verify profile exists
 	exists, err := s.profileService.Exists(ctx, profileID)
 	if err != nil {
 		return nil, err
 	}
 	if !exists {
-		return nil, errors.New("profile not found")
+		return nil, types.ErrNotFound{Message: "profile not found"}
 	}
 
-	createdContact, err := s.repo.Create(ctx, profileID, contact)
+	profileSettings, err := s.settingsService.ForProfile(ctx, profileID)
 	if err != nil {
 		return nil, err
 	}
-	return createdContact, nil
+	if !profileSettings.ContactsEnabled {
+		return nil, ErrContactsDisabled
+	}
+	return profileSettings, nil
+}
+
+func (s *Service) authorize(ctx context.Context, profileID string, action access.Action) error {
+	// Verify profile exists
+	exists, err := s.profileService.Exists(ctx, profileID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return types.ErrNotFound{Message: "profile not found"}
+	}
+
+	return s.authorizer.Authorize(ctx, profileID, action)
+}
+
+func contactCreatedEvent(contact *Contact) (events.Event, error) {
+	return events.New(events.TypeContactCreated, contact.ProfileID, events.ContactCreated{
+		ContactID: contact.ID,
+		Name:      contact.Name,
+		Email:     contact.Email,
+		Message:   contact.Message,
+		Spam:      contact.Spam,
+		Verified:  contact.VerifiedAt != nil,
+		CreatedAt: contact.CreatedAt,
+	})
+}
+
+func subjectFromContext(ctx context.Context) string {
+	if principal := common.PrincipalFromContext(ctx); principal != nil {
+		return principal.Subject
+	}
+	return ""
 }
@@ -0,0 +1,53 @@
+package contacts
+
+import "github.com/mrthoabby/portfolio-api/internal/common/types"
+
+// Status constants for the contact lifecycle
+const (
+	StatusNew      = "new"
+	StatusRead     = "read"
+	StatusReplied  = "replied"
+	StatusArchived = "archived"
+	StatusSpam     = "spam"
+
+	// StatusUnverified holds a contact, hidden from the inbox, until its sender confirms their
+	// email address. It is not part of the lifecycle: verifying moves the contact to new.
+	StatusUnverified = "unverified"
+)
+
+// ErrInvalidTransition is returned when a contact cannot move to the requested status
+var ErrInvalidTransition = types.ErrConflict{Code: "INVALID_TRANSITION", Message: "contact cannot move to the requested status"}
+
+// transitions lists the statuses each status may move to
+var transitions = map[string][]string{
+	StatusNew:      {StatusRead, StatusReplied, StatusArchived, StatusSpam},
+	StatusRead:     {StatusNew, StatusReplied, StatusArchived, StatusSpam},
+	StatusReplied:  {StatusArchived},
+	StatusArchived: {StatusRead},
+	StatusSpam:     {StatusNew},
+}
+
+// IsValidStatus reports whether status is part of the lifecycle
+func IsValidStatus(status string) bool {
+	_, ok := transitions[status]
+	return ok
+}
+
+// CanTransition reports whether a contact may move from one status to another
+func CanTransition(from, to string) bool {
+	for _, allowed := range transitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// CurrentStatus returns the contact status, treating contacts stored before the
+// lifecycle existed as new
+func (c *Contact) CurrentStatus() string {
+	if c.Status == "" {
+		return StatusNew
+	}
+	return c.Status
+}
@@ -0,0 +1,54 @@
+package contacts
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanTransition(t *testing.T) {
+	tests := []struct {
+		from     string
+		to       string
+		expected bool
+	}{
+		{StatusNew, StatusRead, true},
+		{StatusNew, StatusReplied, true},
+		{StatusNew, StatusSpam, true},
+		{StatusRead, StatusNew, true},
+		{StatusRead, StatusArchived, true},
+		{StatusReplied, StatusArchived, true},
+		{StatusReplied, StatusNew, false},
+		{StatusReplied, StatusSpam, false},
+		{StatusArchived, StatusRead, true},
+		{StatusArchived, StatusReplied, false},
+		{StatusSpam, StatusNew, true},
+		{StatusSpam, StatusReplied, false},
+		{StatusNew, StatusNew, false},
+		{"unknown", StatusRead, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.from+"->"+tt.to, func(t *testing.T) {
+			assert.Equal(t, tt.expected, CanTransition(tt.from, tt.to))
+		})
+	}
+}
+
+func TestIsValidStatus(t *testing.T) {
+	for _, status := range []string{StatusNew, StatusRead, StatusReplied, StatusArchived, StatusSpam} {
+		assert.True(t, IsValidStatus(status))
+	}
+	assert.False(t, IsValidStatus("deleted"))
+	assert.False(t, IsValidStatus(""))
+}
+
+func TestContact_CurrentStatus(t *testing.T) {
+	assert.Equal(t, StatusNew, (&Contact{}).CurrentStatus())
+	assert.Equal(t, StatusArchived, (&Contact{Status: StatusArchived}).CurrentStatus())
+}
+
+func TestStoredStatuses(t *testing.T) {
+	assert.Equal(t, []interface{}{StatusNew, nil, StatusRead}, storedStatuses([]string{StatusNew, StatusRead}))
+	assert.Equal(t, []interface{}{StatusSpam}, storedStatuses([]string{StatusSpam}))
+}
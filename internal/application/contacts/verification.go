@@ -0,0 +1,73 @@
+package contacts
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mrthoabby/portfolio-api/internal/common/types"
+)
+
+// DefaultVerificationTTL is how long a verification link can be followed
+const DefaultVerificationTTL = 24 * time.Hour
+
+var (
+	// ErrInvalidVerificationToken is returned for verification links that were not issued by the API
+	ErrInvalidVerificationToken = types.ErrValidation{Message: "invalid verification link"}
+
+	// ErrVerificationExpired is returned for verification links followed too late; the contact is gone by then
+	ErrVerificationExpired = types.ErrValidation{Message: "verification link expired"}
+)
+
+// Verifier issues and checks the links confirming the email address of a contact sender.
+// A token is "<contact ID>.<expiry unix seconds>.<signature>", so no state is kept for it.
+type Verifier struct {
+	secret []byte
+	ttl    time.Duration
+}
+
+// NewVerifier creates a verifier signing tokens with secret, valid for ttl
+func NewVerifier(secret []byte, ttl time.Duration) *Verifier {
+	if ttl <= 0 {
+		ttl = DefaultVerificationTTL
+	}
+	return &Verifier{secret: secret, ttl: ttl}
+}
+
+// Deadline returns until when a contact created at now can be verified
+func (v *Verifier) Deadline(now time.Time) time.Time {
+	return now.Add(v.ttl).Truncate(time.Second)
+}
+
+// Issue returns a token verifying the contact until expiresAt
+func (v *Verifier) Issue(contactID string, expiresAt time.Time) string {
+	expiry := strconv.FormatInt(expiresAt.Unix(), 10)
+	return contactID + "." + expiry + "." + v.sign(contactID, expiry)
+}
+
+// Verify returns the ID of the contact the token verifies
+func (v *Verifier) Verify(token string, now time.Time) (string, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return "", ErrInvalidVerificationToken
+	}
+	contactID, expiry, signature := parts[0], parts[1], parts[2]
+
+	unix, err := strconv.ParseInt(expiry, 10, 64)
+	if err != nil || !hmac.Equal([]byte(signature), []byte(v.sign(contactID, expiry))) {
+		return "", ErrInvalidVerificationToken
+	}
+	if now.After(time.Unix(unix, 0)) {
+		return "", ErrVerificationExpired
+	}
+	return contactID, nil
+}
+
+func (v *Verifier) sign(contactID, expiry string) string {
+	mac := hmac.New(sha256.New, v.secret)
+	mac.Write([]byte(contactID + "\x00" + expiry))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
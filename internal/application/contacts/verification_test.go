@@ -0,0 +1,43 @@
+package contacts
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifier(t *testing.T) {
+	verifier := NewVerifier([]byte("secret"), time.Hour)
+	now := time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC)
+
+	expiresAt := verifier.Deadline(now)
+	assert.Equal(t, now.Add(time.Hour), expiresAt)
+
+	token := verifier.Issue("contact-1", expiresAt)
+	id, err := verifier.Verify(token, now.Add(59*time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, "contact-1", id)
+
+	_, err = verifier.Verify(token, now.Add(61*time.Minute))
+	assert.Equal(t, ErrVerificationExpired, err)
+
+	parts := strings.Split(token, ".")
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"other contact", "contact-2." + parts[1] + "." + parts[2]},
+		{"extended expiry", parts[0] + ".9999999999." + parts[2]},
+		{"other secret", NewVerifier([]byte("other"), time.Hour).Issue("contact-1", expiresAt)},
+		{"malformed", "contact-1"},
+		{"empty", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := verifier.Verify(tt.token, now)
+			assert.Equal(t, ErrInvalidVerificationToken, err)
+		})
+	}
+}
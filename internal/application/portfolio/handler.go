@@ -0,0 +1,72 @@
+package portfolio
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/mrthoabby/portfolio-api/internal/common"
+)
+
+type Handler struct {
+	service *Service
+}
+
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// Get handles GET /profiles/{id}/portfolio.
+// The optional include query parameter is a comma-separated list of sections; all sections by default.
+func (h *Handler) Get(w http.ResponseWriter, r *http.Request) {
+	profileID := r.PathValue("id")
+	if profileID == "" {
+		common.RespondError(w, r, http.StatusBadRequest, "BAD_REQUEST", "Profile ID is required", nil)
+		return
+	}
+
+	// Validate UUID format
+	if !common.IsValidUUID(profileID) {
+		common.RespondError(w, r, http.StatusBadRequest, "BAD_REQUEST", "Invalid profile ID format", nil)
+		return
+	}
+
+	sections, ok := parseSections(r.URL.Query().Get("include"))
+	if !ok {
+		common.RespondError(w, r, http.StatusBadRequest, "BAD_REQUEST", "include must be a comma-separated list of: "+strings.Join(AllSections, ", "), nil)
+		return
+	}
+
+	portfolio, err := h.service.Get(r.Context(), profileID, sections)
+	if err != nil {
+		common.RespondServiceError(w, r, err)
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, portfolio)
+}
+
+// parseSections reads the include parameter, returning false when it names an unknown section
+func parseSections(include string) ([]string, bool) {
+	if strings.TrimSpace(include) == "" {
+		return AllSections, true
+	}
+
+	var sections []string
+	for _, section := range strings.Split(include, ",") {
+		section = strings.TrimSpace(section)
+		if section == "" {
+			continue
+		}
+		if !slices.Contains(AllSections, section) {
+			return nil, false
+		}
+		if !slices.Contains(sections, section) {
+			sections = append(sections, section)
+		}
+	}
+	if len(sections) == 0 {
+		return AllSections, true
+	}
+	return sections, true
+}
@@ -0,0 +1,61 @@
+package portfolio
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_Get_InvalidProfileID(t *testing.T) {
+	handler := NewHandler(&Service{})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/profiles/invalid-uuid/portfolio", nil)
+	req.SetPathValue("id", "invalid-uuid")
+	w := httptest.NewRecorder()
+
+	handler.Get(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestHandler_Get_UnknownSection(t *testing.T) {
+	handler := NewHandler(&Service{})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/profiles/123e4567-e89b-12d3-a456-426614174000/portfolio?include=skills,contacts", nil)
+	req.SetPathValue("id", "123e4567-e89b-12d3-a456-426614174000")
+	w := httptest.NewRecorder()
+
+	handler.Get(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestParseSections(t *testing.T) {
+	tests := []struct {
+		name     string
+		include  string
+		expected []string
+		ok       bool
+	}{
+		{"empty selects all", "", AllSections, true},
+		{"only separators selects all", " , ", AllSections, true},
+		{"subset keeps order", "faq, skills", []string{SectionFAQ, SectionSkills}, true},
+		{"duplicates removed", "skills,skills", []string{SectionSkills}, true},
+		{"unknown section", "skills,contacts", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sections, ok := parseSections(tt.include)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.expected, sections)
+		})
+	}
+}
+
+func TestNonNil(t *testing.T) {
+	assert.Equal(t, []string{}, *nonNil[string](nil))
+	assert.Equal(t, []string{"a"}, *nonNil([]string{"a"}))
+}
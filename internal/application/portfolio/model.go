@@ -0,0 +1,31 @@
+package portfolio
+
+import (
+	"github.com/mrthoabby/portfolio-api/internal/application/certificates"
+	"github.com/mrthoabby/portfolio-api/internal/application/profile"
+	"github.com/mrthoabby/portfolio-api/internal/application/projects"
+	"github.com/mrthoabby/portfolio-api/internal/application/questions"
+	"github.com/mrthoabby/portfolio-api/internal/application/skills"
+)
+
+// Section names accepted by the include query parameter
+const (
+	SectionProfile      = "profile"
+	SectionSkills       = "skills"
+	SectionProjects     = "projects"
+	SectionCertificates = "certificates"
+	SectionFAQ          = "faq"
+)
+
+// AllSections is used when the request does not select sections
+var AllSections = []string{SectionProfile, SectionSkills, SectionProjects, SectionCertificates, SectionFAQ}
+
+// Response is the composite portfolio document. Sections that were not requested are omitted;
+// requested sections without records are empty lists.
+type Response struct {
+	Profile      *profile.Profile            `json:"profile,omitempty"`
+	Skills       *[]skills.Skill             `json:"skills,omitempty"`
+	Projects     *[]projects.Project         `json:"projects,omitempty"`
+	Certificates *[]certificates.Certificate `json:"certificates,omitempty"`
+	FAQ          *[]questions.FAQEntry       `json:"faq,omitempty"`
+}
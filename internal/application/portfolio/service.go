@@ -0,0 +1,110 @@
+package portfolio
+
+import (
+	"context"
+	"slices"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/mrthoabby/portfolio-api/internal/application/certificates"
+	"github.com/mrthoabby/portfolio-api/internal/application/profile"
+	"github.com/mrthoabby/portfolio-api/internal/application/projects"
+	"github.com/mrthoabby/portfolio-api/internal/application/questions"
+	"github.com/mrthoabby/portfolio-api/internal/application/skills"
+	"github.com/mrthoabby/portfolio-api/internal/common/types"
+)
+
+type Service struct {
+	profileService      *profile.Service
+	skillsService       *skills.Service
+	projectsService     *projects.Service
+	certificatesService *certificates.Service
+	questionsService    *questions.Service
+}
+
+func NewService(
+	profileService *profile.Service,
+	skillsService *skills.Service,
+	projectsService *projects.Service,
+	certificatesService *certificates.Service,
+	questionsService *questions.Service,
+) *Service {
+	return &Service{
+		profileService:      profileService,
+		skillsService:       skillsService,
+		projectsService:     projectsService,
+		certificatesService: certificatesService,
+		questionsService:    questionsService,
+	}
+}
+
+// Get validates the profile once and then loads the requested sections concurrently.
+// The first failing section cancels the others.
+func (s *Service) Get(ctx context.Context, profileID string, sections []string) (*Response, error) {
+	response := &Response{}
+
+	// Loading the profile doubles as the existence check when it is requested
+	if slices.Contains(sections, SectionProfile) {
+		profile, err := s.profileService.GetByID(ctx, profileID)
+		if err != nil {
+			return nil, err
+		}
+		response.Profile = profile
+	} else {
+		exists, err := s.profileService.Exists(ctx, profileID)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, types.ErrNotFound{Message: "profile not found"}
+		}
+	}
+
+	group, groupCtx := errgroup.WithContext(ctx)
+
+	if slices.Contains(sections, SectionSkills) {
+		group.Go(func() error {
+			skills, err := s.skillsService.ListByProfileID(groupCtx, profileID)
+			response.Skills = nonNil(skills)
+			return err
+		})
+	}
+
+	if slices.Contains(sections, SectionProjects) {
+		group.Go(func() error {
+			projects, err := s.projectsService.ListByProfileID(groupCtx, profileID)
+			response.Projects = nonNil(projects)
+			return err
+		})
+	}
+
+	if slices.Contains(sections, SectionCertificates) {
+		group.Go(func() error {
+			certificates, err := s.certificatesService.ListByProfileID(groupCtx, profileID, "")
+			response.Certificates = nonNil(certificates)
+			return err
+		})
+	}
+
+	if slices.Contains(sections, SectionFAQ) {
+		group.Go(func() error {
+			faq, err := s.questionsService.ListFAQ(groupCtx, profileID)
+			response.FAQ = nonNil(faq)
+			return err
+		})
+	}
+
+	if err := group.Wait(); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// nonNil returns a pointer to the slice, replacing nil with an empty slice so the section
+// serializes as [] rather than being omitted
+func nonNil[T any](items []T) *[]T {
+	if items == nil {
+		items = []T{}
+	}
+	return &items
+}
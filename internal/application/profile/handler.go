@@ -1,37 +1,174 @@
 package profile
 
 import (
+	"encoding/json"
 	"net/http"
 
 	"github.com/mrthoabby/portfolio-api/internal/common"
 )
 
 type Handler struct {
-	service *Service
+	service   *Service
+	validator *common.Validator
 }
 
 func NewHandler(service *Service) *Handler {
-	return &Handler{service: service}
+	return &Handler{
+		service:   service,
+		validator: common.NewValidator(),
+	}
 }
 
 func (h *Handler) GetByID(w http.ResponseWriter, r *http.Request) {
-	profileID := r.PathValue("id")
-	if profileID == "" {
-		common.RespondError(w, http.StatusBadRequest, "BAD_REQUEST", "Profile ID is required", nil)
+	profileID, ok := profileIDFromPath(w, r)
+	if !ok {
 		return
 	}
 
-	// Validate UUID format
-	if !common.IsValidUUID(profileID) {
-		common.RespondError(w, http.StatusBadRequest, "BAD_REQUEST", "Invalid profile ID format", nil)
+	profile, err := h.service.GetByID(r.Context(), profileID)
+	if err != nil {
+		common.RespondServiceError(w, r, err)
 		return
 	}
 
-	profile, err := h.service.GetByID(r.Context(), profileID)
+	common.RespondJSON(w, http.StatusOK, profile)
+}
+
+// Create handles POST /admin/profiles
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	var profileReq Request
+	if err := common.DecodeJSON(r, &profileReq); err != nil {
+		common.RespondServiceError(w, r, err)
+		return
+	}
+
+	if !h.validate(w, r, &profileReq) {
+		return
+	}
+
+	profile, err := h.service.Create(r.Context(), &profileReq)
+	if err != nil {
+		common.RespondServiceError(w, r, err)
+		return
+	}
+
+	common.RespondJSON(w, http.StatusCreated, profile)
+}
+
+// Update handles PUT /admin/profiles/{id}, replacing every writable field
+func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
+	profileID, ok := profileIDFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	var profileReq Request
+	if err := common.DecodeJSON(r, &profileReq); err != nil {
+		common.RespondServiceError(w, r, err)
+		return
+	}
+
+	if !h.validate(w, r, &profileReq) {
+		return
+	}
+
+	h.update(w, r, profileID, &profileReq)
+}
+
+// Patch handles PATCH /admin/profiles/{id} with a JSON merge patch (RFC 7386) body
+func (h *Handler) Patch(w http.ResponseWriter, r *http.Request) {
+	profileID, ok := profileIDFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	patch, err := common.ReadMergePatch(r)
 	if err != nil {
-		common.RespondError(w, http.StatusNotFound, "NOT_FOUND", "Profile not found", nil)
+		common.RespondServiceError(w, r, err)
+		return
+	}
+
+	current, err := h.service.GetByID(r.Context(), profileID)
+	if err != nil {
+		common.RespondServiceError(w, r, err)
+		return
+	}
+
+	original, err := json.Marshal(current.ToRequest())
+	if err != nil {
+		common.RespondError(w, r, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to update profile", nil)
+		return
+	}
+
+	patched, err := common.ApplyMergePatch(original, patch)
+	if err != nil {
+		common.RespondError(w, r, http.StatusBadRequest, "BAD_REQUEST", "Invalid merge patch document", nil)
+		return
+	}
+
+	var profileReq Request
+	if err := common.UnmarshalStrict(patched, &profileReq); err != nil {
+		common.RespondServiceError(w, r, err)
+		return
+	}
+
+	if !h.validate(w, r, &profileReq) {
+		return
+	}
+
+	h.update(w, r, profileID, &profileReq)
+}
+
+// Delete handles DELETE /admin/profiles/{id}
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
+	profileID, ok := profileIDFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(r.Context(), profileID); err != nil {
+		common.RespondServiceError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) update(w http.ResponseWriter, r *http.Request, profileID string, profileReq *Request) {
+	profile, err := h.service.Update(r.Context(), profileID, profileReq)
+	if err != nil {
+		common.RespondServiceError(w, r, err)
 		return
 	}
 
 	common.RespondJSON(w, http.StatusOK, profile)
 }
+
+func (h *Handler) validate(w http.ResponseWriter, r *http.Request, profileReq *Request) bool {
+	// Sanitize free-text inputs before validation
+	profileReq.Name = common.SanitizeString(common.StripHTMLTags(profileReq.Name))
+	profileReq.ProfessionTittle = common.SanitizeString(common.StripHTMLTags(profileReq.ProfessionTittle))
+	profileReq.AboutMe = common.SanitizeString(common.StripHTMLTags(profileReq.AboutMe))
+
+	if err := h.validator.Validate(r, profileReq); err != nil {
+		common.RespondServiceError(w, r, err)
+		return false
+	}
+	return true
+}
+
+func profileIDFromPath(w http.ResponseWriter, r *http.Request) (string, bool) {
+	profileID := r.PathValue("id")
+	if profileID == "" {
+		common.RespondError(w, r, http.StatusBadRequest, "BAD_REQUEST", "Profile ID is required", nil)
+		return "", false
+	}
+
+	// Validate UUID format
+	if !common.IsValidUUID(profileID) {
+		common.RespondError(w, r, http.StatusBadRequest, "BAD_REQUEST", "Invalid profile ID format", nil)
+		return "", false
+	}
+
+	return profileID, true
+}
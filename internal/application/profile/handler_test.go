@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -27,5 +28,55 @@ func TestNewHandler(t *testing.T) {
 	handler := NewHandler(service)
 	assert.NotNil(t, handler)
 	assert.Equal(t, service, handler.service)
+	assert.NotNil(t, handler.validator)
 }
 
+func TestHandler_Create_InvalidJSON(t *testing.T) {
+	handler := NewHandler(&Service{})
+
+	req := httptest.NewRequest("POST", "/api/v1/admin/profiles", strings.NewReader("invalid json"))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	handler.Create(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestHandler_Create_InvalidRequest(t *testing.T) {
+	handler := NewHandler(&Service{})
+
+	body := `{"name": "J", "title": "", "photoUrl": "not-a-url"}`
+	req := httptest.NewRequest("POST", "/api/v1/admin/profiles", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	handler.Create(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "VALIDATION_ERROR")
+}
+
+func TestHandler_Update_InvalidID(t *testing.T) {
+	handler := NewHandler(&Service{})
+
+	req := httptest.NewRequest("PUT", "/api/v1/admin/profiles/invalid-uuid", strings.NewReader(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.SetPathValue("id", "invalid-uuid")
+	w := httptest.NewRecorder()
+
+	handler.Update(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestHandler_Delete_MissingID(t *testing.T) {
+	handler := NewHandler(&Service{})
+
+	req := httptest.NewRequest("DELETE", "/api/v1/admin/profiles/", nil)
+	w := httptest.NewRecorder()
+
+	handler.Delete(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -12,3 +12,23 @@ type Profile struct {
 	CreatedAt           time.Time  `json:"createdAt" bson:"createdAt"`
 	UpdatedAt           time.Time  `json:"updatedAt" bson:"updatedAt"`
 }
+
+// Request holds the writable fields of a profile for create and update operations
+type Request struct {
+	Name                string     `json:"name" validate:"required,min=2,max=100"`
+	PhotoURL            string     `json:"photoUrl" validate:"omitempty,url,max=2048"`
+	ProfessionTittle    string     `json:"title" validate:"required,min=2,max=150"`
+	AboutMe             string     `json:"aboutMe" validate:"max=5000"`
+	FirstExperienceDate *time.Time `json:"firstExperienceDate,omitempty"`
+}
+
+// ToRequest returns the writable fields of the profile, used as the base document for merge patches
+func (p *Profile) ToRequest() Request {
+	return Request{
+		Name:                p.Name,
+		PhotoURL:            p.PhotoURL,
+		ProfessionTittle:    p.ProfessionTittle,
+		AboutMe:             p.AboutMe,
+		FirstExperienceDate: p.FirstExperienceDate,
+	}
+}
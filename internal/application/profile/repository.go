@@ -2,6 +2,9 @@ package profile
 
 import (
 	"context"
+	"time"
+
+	"github.com/google/uuid"
 
 	"github.com/mrthoabby/portfolio-api/internal/common/contracts"
 	"github.com/mrthoabby/portfolio-api/internal/common/types"
@@ -36,3 +39,56 @@ func (r *Repository) Exists(ctx context.Context, id string) (bool, error) {
 	}
 	return count > 0, nil
 }
+
+func (r *Repository) Create(ctx context.Context, profile *Request) (*Profile, error) {
+	now := time.Now()
+	newProfile := &Profile{
+		ID:                  uuid.New().String(),
+		Name:                profile.Name,
+		PhotoURL:            profile.PhotoURL,
+		ProfessionTittle:    profile.ProfessionTittle,
+		AboutMe:             profile.AboutMe,
+		FirstExperienceDate: profile.FirstExperienceDate,
+		CreatedAt:           now,
+		UpdatedAt:           now,
+	}
+
+	err := r.store.InsertOne(ctx, newProfile)
+	if err != nil {
+		return nil, err
+	}
+
+	return newProfile, nil
+}
+
+// Update replaces the writable fields of a profile and refreshes UpdatedAt
+func (r *Repository) Update(ctx context.Context, id string, profile *Request) error {
+	update := map[string]interface{}{
+		"name":                profile.Name,
+		"photoUrl":            profile.PhotoURL,
+		"title":               profile.ProfessionTittle,
+		"aboutMe":             profile.AboutMe,
+		"firstExperienceDate": profile.FirstExperienceDate,
+		"updatedAt":           time.Now(),
+	}
+
+	err := r.store.UpdateOne(ctx, map[string]interface{}{"_id": id}, update)
+	if err != nil {
+		if types.IsNotFoundError(err) {
+			return types.ErrNotFound{Message: "profile not found"}
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *Repository) Delete(ctx context.Context, id string) error {
+	err := r.store.DeleteOne(ctx, map[string]interface{}{"_id": id})
+	if err != nil {
+		if types.IsNotFoundError(err) {
+			return types.ErrNotFound{Message: "profile not found"}
+		}
+		return err
+	}
+	return nil
+}
@@ -2,25 +2,79 @@ package profile
 
 import (
 	"context"
-	"errors"
+
+	"github.com/mrthoabby/portfolio-api/internal/access"
+	"github.com/mrthoabby/portfolio-api/internal/common/contracts"
 )
 
+// Dependent keeps records that belong to profiles and are deleted along with them
+type Dependent interface {
+	DeleteByProfileID(ctx context.Context, profileID string) error
+}
+
 type Service struct {
-	repo *Repository
+	repo       *Repository
+	authorizer *access.Authorizer
+	transactor contracts.Transactor
+	dependents []Dependent
 }
 
-func NewService(repo *Repository) *Service {
-	return &Service{repo: repo}
+func NewService(repo *Repository, authorizer *access.Authorizer, transactor contracts.Transactor) *Service {
+	return &Service{
+		repo:       repo,
+		authorizer: authorizer,
+		transactor: transactor,
+	}
+}
+
+// Cascade registers records deleted along with a profile. The domains depending on profiles are
+// built after this service, so they are registered once they exist.
+func (s *Service) Cascade(dependents ...Dependent) {
+	s.dependents = append(s.dependents, dependents...)
 }
 
 func (s *Service) GetByID(ctx context.Context, id string) (*Profile, error) {
-	profile, err := s.repo.GetByID(ctx, id)
-	if err != nil {
-		return nil, errors.New("profile not found")
-	}
-	return profile, nil
+	return s.repo.GetByID(ctx, id)
 }
 
 func (s *Service) Exists(ctx context.Context, id string) (bool, error) {
 	return s.repo.Exists(ctx, id)
 }
+
+// Create stores a new profile. Only superadmins may create profiles.
+func (s *Service) Create(ctx context.Context, profile *Request) (*Profile, error) {
+	if err := s.authorizer.RequireSuperadmin(ctx); err != nil {
+		return nil, err
+	}
+	return s.repo.Create(ctx, profile)
+}
+
+// Update replaces the writable fields of an existing profile and returns the stored result
+func (s *Service) Update(ctx context.Context, id string, profile *Request) (*Profile, error) {
+	if err := s.authorizer.Authorize(ctx, id, access.ActionEdit); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(ctx, id, profile); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(ctx, id)
+}
+
+// Delete removes a profile and, in the same transaction, every record registered through Cascade
+func (s *Service) Delete(ctx context.Context, id string) error {
+	if err := s.authorizer.Authorize(ctx, id, access.ActionManage); err != nil {
+		return err
+	}
+
+	return s.transactor.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.Delete(ctx, id); err != nil {
+			return err
+		}
+		for _, dependent := range s.dependents {
+			if err := dependent.DeleteByProfileID(ctx, id); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,65 @@
+package profile
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mrthoabby/portfolio-api/internal/access"
+	"github.com/mrthoabby/portfolio-api/internal/common"
+	"github.com/mrthoabby/portfolio-api/internal/common/contracts"
+)
+
+// profileStore holds the IDs of the stored profiles
+type profileStore struct {
+	contracts.Store
+	ids map[string]bool
+}
+
+func (s *profileStore) DeleteOne(_ context.Context, filter map[string]interface{}) error {
+	delete(s.ids, filter["_id"].(string))
+	return nil
+}
+
+// transactor runs functions directly, counting the transactions started
+type transactor struct {
+	calls int
+}
+
+func (t *transactor) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	t.calls++
+	return fn(ctx)
+}
+
+// dependent records the profiles whose records it deleted
+type dependent struct {
+	deleted []string
+	err     error
+}
+
+func (d *dependent) DeleteByProfileID(_ context.Context, profileID string) error {
+	d.deleted = append(d.deleted, profileID)
+	return d.err
+}
+
+func TestService_Delete_Cascades(t *testing.T) {
+	store := &profileStore{ids: map[string]bool{"profile-1": true}}
+	transactions := &transactor{}
+	service := NewService(&Repository{store: store}, access.NewAuthorizer(nil, []string{"admin"}), transactions)
+	members, contacts := &dependent{}, &dependent{}
+	service.Cascade(members, contacts)
+	ctx := common.WithPrincipal(context.Background(), &common.Principal{Subject: "admin"})
+
+	assert.NoError(t, service.Delete(ctx, "profile-1"))
+	assert.Empty(t, store.ids)
+	assert.Equal(t, []string{"profile-1"}, members.deleted)
+	assert.Equal(t, []string{"profile-1"}, contacts.deleted)
+	assert.Equal(t, 1, transactions.calls)
+
+	// A failing dependent fails the transaction, so nothing is deleted
+	failure := errors.New("database unavailable")
+	service.Cascade(&dependent{err: failure})
+	assert.ErrorIs(t, service.Delete(ctx, "profile-2"), failure)
+}
@@ -1,35 +1,40 @@
 package projects
 
 import (
+	"encoding/json"
 	"net/http"
 
 	"github.com/mrthoabby/portfolio-api/internal/common"
 )
 
 type Handler struct {
-	service *Service
+	service   *Service
+	validator *common.Validator
 }
 
 func NewHandler(service *Service) *Handler {
-	return &Handler{service: service}
+	return &Handler{
+		service:   service,
+		validator: common.NewValidator(),
+	}
 }
 
 func (h *Handler) GetByProfileID(w http.ResponseWriter, r *http.Request) {
 	profileID := r.PathValue("id")
 	if profileID == "" {
-		common.RespondError(w, http.StatusBadRequest, "BAD_REQUEST", "Profile ID is required", nil)
+		common.RespondError(w, r, http.StatusBadRequest, "BAD_REQUEST", "Profile ID is required", nil)
 		return
 	}
 
 	// Validate UUID format
 	if !common.IsValidUUID(profileID) {
-		common.RespondError(w, http.StatusBadRequest, "BAD_REQUEST", "Invalid profile ID format", nil)
+		common.RespondError(w, r, http.StatusBadRequest, "BAD_REQUEST", "Invalid profile ID format", nil)
 		return
 	}
 
 	projects, err := h.service.GetByProfileID(r.Context(), profileID)
 	if err != nil {
-		common.RespondError(w, http.StatusNotFound, "NOT_FOUND", err.Error(), nil)
+		common.RespondServiceError(w, r, err)
 		return
 	}
 
@@ -37,3 +42,194 @@ func (h *Handler) GetByProfileID(w http.ResponseWriter, r *http.Request) {
 	common.RespondJSON(w, http.StatusOK, response)
 }
 
+// GetAllByProfileID handles GET /admin/profiles/{id}/projects, including hidden projects
+func (h *Handler) GetAllByProfileID(w http.ResponseWriter, r *http.Request) {
+	profileID, ok := profileIDFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	projects, err := h.service.GetAllByProfileID(r.Context(), profileID)
+	if err != nil {
+		common.RespondServiceError(w, r, err)
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, Response{Projects: projects})
+}
+
+// Create handles POST /admin/profiles/{id}/projects
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	profileID, ok := profileIDFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	var projectReq Request
+	if !h.decode(w, r, &projectReq) || !h.validateProject(w, r, &projectReq) {
+		return
+	}
+
+	project, err := h.service.Create(r.Context(), profileID, &projectReq)
+	if err != nil {
+		common.RespondServiceError(w, r, err)
+		return
+	}
+
+	common.RespondJSON(w, http.StatusCreated, project)
+}
+
+// Update handles PUT /admin/profiles/{id}/projects/{projectId}
+func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
+	profileID, projectID, ok := projectIDsFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	var projectReq Request
+	if !h.decode(w, r, &projectReq) || !h.validateProject(w, r, &projectReq) {
+		return
+	}
+
+	h.update(w, r, profileID, projectID, &projectReq)
+}
+
+// Patch handles PATCH /admin/profiles/{id}/projects/{projectId} with a JSON merge patch (RFC 7386).
+// Sending {"visible": false} hides a project; {"pinned": true} or {"featured": true} toggles the flags.
+func (h *Handler) Patch(w http.ResponseWriter, r *http.Request) {
+	profileID, projectID, ok := projectIDsFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	patch, err := common.ReadMergePatch(r)
+	if err != nil {
+		common.RespondServiceError(w, r, err)
+		return
+	}
+
+	current, err := h.service.GetByID(r.Context(), profileID, projectID)
+	if err != nil {
+		common.RespondServiceError(w, r, err)
+		return
+	}
+
+	original, err := json.Marshal(current.ToRequest())
+	if err != nil {
+		common.RespondError(w, r, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to update project", nil)
+		return
+	}
+
+	patched, err := common.ApplyMergePatch(original, patch)
+	if err != nil {
+		common.RespondError(w, r, http.StatusBadRequest, "BAD_REQUEST", "Invalid merge patch document", nil)
+		return
+	}
+
+	var projectReq Request
+	if err := common.UnmarshalStrict(patched, &projectReq); err != nil {
+		common.RespondServiceError(w, r, err)
+		return
+	}
+
+	if !h.validateProject(w, r, &projectReq) {
+		return
+	}
+
+	h.update(w, r, profileID, projectID, &projectReq)
+}
+
+// Delete handles DELETE /admin/profiles/{id}/projects/{projectId}
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
+	profileID, projectID, ok := projectIDsFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(r.Context(), profileID, projectID); err != nil {
+		common.RespondServiceError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Reorder handles PUT /admin/profiles/{id}/projects/order
+func (h *Handler) Reorder(w http.ResponseWriter, r *http.Request) {
+	profileID, ok := profileIDFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	var reorderReq ReorderRequest
+	if !h.decode(w, r, &reorderReq) || !h.validate(w, r, &reorderReq) {
+		return
+	}
+
+	projects, err := h.service.Reorder(r.Context(), profileID, reorderReq.IDs)
+	if err != nil {
+		common.RespondServiceError(w, r, err)
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, Response{Projects: projects})
+}
+
+func (h *Handler) update(w http.ResponseWriter, r *http.Request, profileID, projectID string, projectReq *Request) {
+	project, err := h.service.Update(r.Context(), profileID, projectID, projectReq)
+	if err != nil {
+		common.RespondServiceError(w, r, err)
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, project)
+}
+
+func (h *Handler) decode(w http.ResponseWriter, r *http.Request, dst interface{}) bool {
+	if err := common.DecodeJSON(r, dst); err != nil {
+		common.RespondServiceError(w, r, err)
+		return false
+	}
+	return true
+}
+
+func (h *Handler) validate(w http.ResponseWriter, r *http.Request, dst interface{}) bool {
+	if err := h.validator.Validate(r, dst); err != nil {
+		common.RespondServiceError(w, r, err)
+		return false
+	}
+	return true
+}
+
+// validateProject sanitizes a project request before validating it, so the rules apply to the stored values
+func (h *Handler) validateProject(w http.ResponseWriter, r *http.Request, projectReq *Request) bool {
+	sanitize(projectReq)
+	return h.validate(w, r, projectReq)
+}
+
+func sanitize(projectReq *Request) {
+	projectReq.Name = common.SanitizeString(common.StripHTMLTags(projectReq.Name))
+	projectReq.Description = common.SanitizeString(common.StripHTMLTags(projectReq.Description))
+	for i, tech := range projectReq.TechStack {
+		projectReq.TechStack[i] = common.SanitizeString(common.StripHTMLTags(tech))
+	}
+}
+
+func profileIDFromPath(w http.ResponseWriter, r *http.Request) (string, bool) {
+	profileID := r.PathValue("id")
+	if !common.IsValidUUID(profileID) {
+		common.RespondError(w, r, http.StatusBadRequest, "BAD_REQUEST", "Invalid profile ID format", nil)
+		return "", false
+	}
+	return profileID, true
+}
+
+func projectIDsFromPath(w http.ResponseWriter, r *http.Request) (string, string, bool) {
+	profileID := r.PathValue("id")
+	projectID := r.PathValue("projectId")
+	if !common.IsValidUUID(profileID) || !common.IsValidUUID(projectID) {
+		common.RespondError(w, r, http.StatusBadRequest, "BAD_REQUEST", "Invalid profile or project ID format", nil)
+		return "", "", false
+	}
+	return profileID, projectID, true
+}
@@ -2,11 +2,15 @@ package projects
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/mrthoabby/portfolio-api/internal/common"
 )
 
 func TestHandler_GetByProfileID_MissingID(t *testing.T) {
@@ -29,3 +33,96 @@ func TestNewHandler(t *testing.T) {
 	assert.Equal(t, service, handler.service)
 }
 
+func TestHandler_Create_InvalidURL(t *testing.T) {
+	handler := NewHandler(&Service{})
+
+	body := `{"name": "Portfolio API", "githubUrl": "not a url"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/profiles/123e4567-e89b-12d3-a456-426614174000/projects", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.SetPathValue("id", "123e4567-e89b-12d3-a456-426614174000")
+	w := httptest.NewRecorder()
+
+	handler.Create(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var response struct {
+		Error struct {
+			Details []common.FieldError `json:"details"`
+		} `json:"error"`
+	}
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Equal(t, []common.FieldError{{Field: "githubUrl", Rule: "url", Message: "githubUrl must be a valid URL"}}, response.Error.Details)
+}
+
+func TestHandler_Create_RejectsMarkupOnlyValues(t *testing.T) {
+	tests := []struct {
+		name  string
+		body  string
+		field string
+	}{
+		{"name", `{"name": "<b></b>"}`, "name"},
+		{"tech", `{"name": "Portfolio API", "techStack": ["Go", "<i></i>"]}`, "techStack[1]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewHandler(&Service{})
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/profiles/123e4567-e89b-12d3-a456-426614174000/projects", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			req.SetPathValue("id", "123e4567-e89b-12d3-a456-426614174000")
+			w := httptest.NewRecorder()
+
+			handler.Create(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Contains(t, w.Body.String(), `"field":"`+tt.field+`"`)
+		})
+	}
+}
+
+func TestHandler_Patch_InvalidProjectID(t *testing.T) {
+	handler := NewHandler(&Service{})
+
+	req := httptest.NewRequest(http.MethodPatch, "/api/v1/admin/profiles/123e4567-e89b-12d3-a456-426614174000/projects/bad", strings.NewReader(`{"visible": false}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.SetPathValue("id", "123e4567-e89b-12d3-a456-426614174000")
+	req.SetPathValue("projectId", "bad")
+	w := httptest.NewRecorder()
+
+	handler.Patch(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestHandler_Reorder_RequiresIDs(t *testing.T) {
+	handler := NewHandler(&Service{})
+
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/admin/profiles/123e4567-e89b-12d3-a456-426614174000/projects/order", strings.NewReader(`{"ids": ["not-a-uuid"]}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.SetPathValue("id", "123e4567-e89b-12d3-a456-426614174000")
+	w := httptest.NewRecorder()
+
+	handler.Reorder(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"field":"ids[0]"`)
+}
+
+func TestProject_ToRequest_MergePatch(t *testing.T) {
+	project := Project{Name: "Portfolio API", TechStack: []string{"Go"}, Visible: true, Order: 3}
+
+	original, err := json.Marshal(project.ToRequest())
+	assert.NoError(t, err)
+
+	patched, err := common.ApplyMergePatch(original, []byte(`{"visible": false, "pinned": true}`))
+	assert.NoError(t, err)
+
+	var projectReq Request
+	assert.NoError(t, json.Unmarshal(patched, &projectReq))
+	assert.False(t, projectReq.Visible)
+	assert.True(t, projectReq.Pinned)
+	assert.Equal(t, 3, *projectReq.Order)
+	assert.Equal(t, []string{"Go"}, projectReq.TechStack)
+}
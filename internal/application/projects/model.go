@@ -12,9 +12,50 @@ type Project struct {
 	LiveURL         *string   `json:"liveUrl,omitempty" bson:"liveUrl,omitempty"`
 	ImageDiagramURL *string   `json:"imageDiagramUrl,omitempty" bson:"imageDiagramUrl,omitempty"`
 	Visible         bool      `json:"visible" bson:"visible"`
+	Pinned          bool      `json:"pinned" bson:"pinned"`
+	Featured        bool      `json:"featured" bson:"featured"`
+	Order           int       `json:"order" bson:"order"`
 	CreatedAt       time.Time `json:"createdAt" bson:"createdAt"`
+	UpdatedAt       time.Time `json:"updatedAt" bson:"updatedAt"`
 }
 
 type Response struct {
 	Projects []Project `json:"projects"`
 }
+
+// Request is the payload for creating or replacing a project.
+// Pinned projects are listed first; featured projects are highlighted by clients.
+type Request struct {
+	Name            string   `json:"name" validate:"required,min=1,max=100"`
+	Description     string   `json:"description" validate:"max=2000"`
+	TechStack       []string `json:"techStack" validate:"max=30,dive,min=1,max=50"`
+	GitHubURL       *string  `json:"githubUrl,omitempty" validate:"omitempty,url"`
+	LiveURL         *string  `json:"liveUrl,omitempty" validate:"omitempty,url"`
+	ImageDiagramURL *string  `json:"imageDiagramUrl,omitempty" validate:"omitempty,url"`
+	Visible         bool     `json:"visible"`
+	Pinned          bool     `json:"pinned"`
+	Featured        bool     `json:"featured"`
+	Order           *int     `json:"order,omitempty" validate:"omitempty,min=0"`
+}
+
+// ReorderRequest lists every project ID of a profile in the desired order
+type ReorderRequest struct {
+	IDs []string `json:"ids" validate:"required,min=1,dive,uuid"`
+}
+
+// ToRequest returns the writable fields of the project, used as the base document for merge patches
+func (p *Project) ToRequest() Request {
+	order := p.Order
+	return Request{
+		Name:            p.Name,
+		Description:     p.Description,
+		TechStack:       p.TechStack,
+		GitHubURL:       p.GitHubURL,
+		LiveURL:         p.LiveURL,
+		ImageDiagramURL: p.ImageDiagramURL,
+		Visible:         p.Visible,
+		Pinned:          p.Pinned,
+		Featured:        p.Featured,
+		Order:           &order,
+	}
+}
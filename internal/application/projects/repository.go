@@ -2,10 +2,18 @@ package projects
 
 import (
 	"context"
+	"time"
+
+	"github.com/google/uuid"
 
 	"github.com/mrthoabby/portfolio-api/internal/common/contracts"
+	"github.com/mrthoabby/portfolio-api/internal/common/types"
 )
 
+// listSort puts pinned projects first, then follows the manual order,
+// falling back to the newest projects when the order ties
+var listSort = []string{"-pinned", "order", "-createdAt"}
+
 type Repository struct {
 	store contracts.Store
 }
@@ -16,18 +24,196 @@ func NewRepository(dataSource contracts.DataSource) *Repository {
 	}
 }
 
+// GetByProfileID returns the visible projects of a profile
 func (r *Repository) GetByProfileID(ctx context.Context, profileID string) ([]Project, error) {
 	var projects []Project
 	filter := map[string]interface{}{
 		"profileId": profileID,
 		"visible":   true,
 	}
-	sortFields := []string{"-createdAt"} // "-" prefix for descending order
 
-	err := r.store.FindMany(ctx, filter, sortFields, &projects)
+	err := r.store.FindMany(ctx, filter, listSort, &projects)
 	if err != nil {
 		return nil, err
 	}
 
 	return projects, nil
 }
+
+// GetAllByProfileID returns every project of a profile, hidden ones included
+func (r *Repository) GetAllByProfileID(ctx context.Context, profileID string) ([]Project, error) {
+	var projects []Project
+	err := r.store.FindMany(ctx, map[string]interface{}{"profileId": profileID}, listSort, &projects)
+	if err != nil {
+		return nil, err
+	}
+	return projects, nil
+}
+
+func (r *Repository) GetByID(ctx context.Context, profileID, id string) (*Project, error) {
+	var project Project
+	err := r.store.FindOne(ctx, map[string]interface{}{"_id": id, "profileId": profileID}, &project)
+	if err != nil {
+		if types.IsNotFoundError(err) {
+			return nil, types.ErrNotFound{Message: "project not found"}
+		}
+		return nil, err
+	}
+	return &project, nil
+}
+
+// Create stores a new project. Without an explicit order the project goes after the existing ones.
+func (r *Repository) Create(ctx context.Context, profileID string, project *Request) (*Project, error) {
+	order, err := r.orderOrNext(ctx, profileID, project.Order)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	newProject := &Project{
+		ID:              uuid.New().String(),
+		ProfileID:       profileID,
+		Name:            project.Name,
+		Description:     project.Description,
+		TechStack:       project.TechStack,
+		GitHubURL:       project.GitHubURL,
+		LiveURL:         project.LiveURL,
+		ImageDiagramURL: project.ImageDiagramURL,
+		Visible:         project.Visible,
+		Pinned:          project.Pinned,
+		Featured:        project.Featured,
+		Order:           order,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	if newProject.TechStack == nil {
+		newProject.TechStack = []string{}
+	}
+
+	if err := r.store.InsertOne(ctx, newProject); err != nil {
+		return nil, err
+	}
+	return newProject, nil
+}
+
+// Update replaces the writable fields of a project. The order is kept unless the request sets it.
+func (r *Repository) Update(ctx context.Context, profileID, id string, project *Request) error {
+	techStack := project.TechStack
+	if techStack == nil {
+		techStack = []string{}
+	}
+
+	update := contracts.Update{
+		Set: map[string]interface{}{
+			"name":        project.Name,
+			"description": project.Description,
+			"techStack":   techStack,
+			"visible":     project.Visible,
+			"pinned":      project.Pinned,
+			"featured":    project.Featured,
+			"updatedAt":   time.Now(),
+		},
+	}
+	if project.Order != nil {
+		update.Set["order"] = *project.Order
+	}
+
+	optionalURLs := map[string]*string{
+		"githubUrl":       project.GitHubURL,
+		"liveUrl":         project.LiveURL,
+		"imageDiagramUrl": project.ImageDiagramURL,
+	}
+	for field, value := range optionalURLs {
+		if value == nil {
+			update.Unset = append(update.Unset, field)
+		} else {
+			update.Set[field] = *value
+		}
+	}
+
+	return r.update(ctx, profileID, id, update)
+}
+
+func (r *Repository) Delete(ctx context.Context, profileID, id string) error {
+	err := r.store.DeleteOne(ctx, map[string]interface{}{"_id": id, "profileId": profileID})
+	if err != nil {
+		if types.IsNotFoundError(err) {
+			return types.ErrNotFound{Message: "project not found"}
+		}
+		return err
+	}
+	return nil
+}
+
+// DeleteByProfileID deletes every project of a profile
+func (r *Repository) DeleteByProfileID(ctx context.Context, profileID string) error {
+	_, err := r.store.DeleteMany(ctx, map[string]interface{}{"profileId": profileID})
+	return err
+}
+
+// Reorder assigns each project its position in ids
+func (r *Repository) Reorder(ctx context.Context, profileID string, ids []string) error {
+	now := time.Now()
+	for position, id := range ids {
+		update := contracts.Update{Set: map[string]interface{}{"order": position, "updatedAt": now}}
+		if err := r.update(ctx, profileID, id, update); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BackfillOrder gives the projects stored before they had an order a position after the other projects
+// of their profile, newest first as they were listed then. Without it, the manual order would list them first.
+func (r *Repository) BackfillOrder(ctx context.Context) error {
+	var unordered []Project
+	filter := map[string]interface{}{"order": map[string]interface{}{"$exists": false}}
+	if err := r.store.FindMany(ctx, filter, []string{"profileId", "-createdAt"}, &unordered); err != nil {
+		return err
+	}
+
+	next := make(map[string]int)
+	for _, project := range unordered {
+		position, ok := next[project.ProfileID]
+		if !ok {
+			count, err := r.store.CountRecords(ctx, map[string]interface{}{"profileId": project.ProfileID})
+			if err != nil {
+				return err
+			}
+			position = int(count)
+		}
+		next[project.ProfileID] = position + 1
+
+		// Projects reordered meanwhile, e.g. by another instance starting, keep their new position
+		err := r.store.UpdateOneWith(ctx,
+			map[string]interface{}{"_id": project.ID, "order": map[string]interface{}{"$exists": false}},
+			contracts.Update{Set: map[string]interface{}{"order": position}},
+		)
+		if err != nil && !types.IsNotFoundError(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Repository) update(ctx context.Context, profileID, id string, update contracts.Update) error {
+	err := r.store.UpdateOneWith(ctx, map[string]interface{}{"_id": id, "profileId": profileID}, update)
+	if err != nil {
+		if types.IsNotFoundError(err) {
+			return types.ErrNotFound{Message: "project not found"}
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *Repository) orderOrNext(ctx context.Context, profileID string, order *int) (int, error) {
+	if order != nil {
+		return *order, nil
+	}
+	count, err := r.store.CountRecords(ctx, map[string]interface{}{"profileId": profileID})
+	if err != nil {
+		return 0, err
+	}
+	return int(count), nil
+}
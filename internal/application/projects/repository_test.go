@@ -0,0 +1,73 @@
+package projects
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrthoabby/portfolio-api/internal/common/contracts"
+)
+
+// unorderedStore holds projects, returning those without an order in the sort the backfill asks for
+type unorderedStore struct {
+	contracts.Store
+	projects []Project
+	ordered  map[string]bool
+}
+
+func (s *unorderedStore) FindMany(_ context.Context, _ map[string]interface{}, _ []string, results interface{}) error {
+	var unordered []Project
+	for _, project := range s.projects {
+		if !s.ordered[project.ID] {
+			unordered = append(unordered, project)
+		}
+	}
+	*results.(*[]Project) = unordered
+	return nil
+}
+
+func (s *unorderedStore) CountRecords(_ context.Context, filter map[string]interface{}) (int64, error) {
+	var count int64
+	for _, project := range s.projects {
+		if project.ProfileID == filter["profileId"] {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (s *unorderedStore) UpdateOneWith(_ context.Context, filter map[string]interface{}, update contracts.Update) error {
+	for i := range s.projects {
+		if s.projects[i].ID == filter["_id"] {
+			s.projects[i].Order = update.Set["order"].(int)
+			s.ordered[s.projects[i].ID] = true
+		}
+	}
+	return nil
+}
+
+func TestRepository_BackfillOrder(t *testing.T) {
+	now := time.Now()
+	store := &unorderedStore{
+		projects: []Project{
+			// Sorted by profile, newest first, as the repository requests
+			{ID: "legacy-new", ProfileID: "profile-1", CreatedAt: now},
+			{ID: "legacy-old", ProfileID: "profile-1", CreatedAt: now.Add(-time.Hour)},
+			{ID: "ordered", ProfileID: "profile-1", Order: 0},
+			{ID: "other", ProfileID: "profile-2", CreatedAt: now},
+		},
+		ordered: map[string]bool{"ordered": true},
+	}
+	repo := &Repository{store: store}
+
+	require.NoError(t, repo.BackfillOrder(context.Background()))
+
+	orders := map[string]int{}
+	for _, project := range store.projects {
+		orders[project.ID] = project.Order
+	}
+	assert.Equal(t, map[string]int{"legacy-new": 3, "legacy-old": 4, "ordered": 0, "other": 1}, orders)
+}
@@ -2,20 +2,30 @@ package projects
 
 import (
 	"context"
-	"errors"
 
+	"github.com/mrthoabby/portfolio-api/internal/access"
 	"github.com/mrthoabby/portfolio-api/internal/application/profile"
+	"github.com/mrthoabby/portfolio-api/internal/common"
+	"github.com/mrthoabby/portfolio-api/internal/common/contracts"
+	"github.com/mrthoabby/portfolio-api/internal/common/types"
+	"github.com/mrthoabby/portfolio-api/internal/events"
 )
 
 type Service struct {
 	repo           *Repository
 	profileService *profile.Service
+	authorizer     *access.Authorizer
+	transactor     contracts.Transactor
+	publisher      events.Publisher
 }
 
-func NewService(repo *Repository, profileService *profile.Service) *Service {
+func NewService(repo *Repository, profileService *profile.Service, authorizer *access.Authorizer, transactor contracts.Transactor, publisher events.Publisher) *Service {
 	return &Service{
 		repo:           repo,
 		profileService: profileService,
+		authorizer:     authorizer,
+		transactor:     transactor,
+		publisher:      publisher,
 	}
 }
 
@@ -26,12 +36,154 @@ func (s *Service) GetByProfileID(ctx context.Context, profileID string) ([]Proje
 		return nil, err
 	}
 	if !exists {
-		return nil, errors.New("profile not found")
+		return nil, types.ErrNotFound{Message: "profile not found"}
 	}
 
-	projects, err := s.repo.GetByProfileID(ctx, profileID)
+	return s.ListByProfileID(ctx, profileID)
+}
+
+// ListByProfileID returns the visible projects of a profile without checking that the profile exists.
+// Callers that already validated the profile use it to skip the extra lookup.
+func (s *Service) ListByProfileID(ctx context.Context, profileID string) ([]Project, error) {
+	return s.repo.GetByProfileID(ctx, profileID)
+}
+
+// GetAllByProfileID returns every project of a profile, hidden ones included
+func (s *Service) GetAllByProfileID(ctx context.Context, profileID string) ([]Project, error) {
+	if err := s.authorize(ctx, profileID, access.ActionRead); err != nil {
+		return nil, err
+	}
+	return s.repo.GetAllByProfileID(ctx, profileID)
+}
+
+func (s *Service) GetByID(ctx context.Context, profileID, id string) (*Project, error) {
+	if err := s.authorize(ctx, profileID, access.ActionRead); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(ctx, profileID, id)
+}
+
+// Create stores a project, announcing it when it is created visible
+func (s *Service) Create(ctx context.Context, profileID string, project *Request) (*Project, error) {
+	if err := s.authorize(ctx, profileID, access.ActionEdit); err != nil {
+		return nil, err
+	}
+
+	var createdProject *Project
+	err := s.transactor.WithTransaction(ctx, func(ctx context.Context) error {
+		created, err := s.repo.Create(ctx, profileID, project)
+		if err != nil {
+			return err
+		}
+		if created.Visible {
+			if err := s.publishPublished(ctx, created); err != nil {
+				return err
+			}
+		}
+
+		createdProject = created
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return createdProject, nil
+}
+
+// Update replaces a project and returns the stored result, announcing it when it becomes visible
+func (s *Service) Update(ctx context.Context, profileID, id string, project *Request) (*Project, error) {
+	if err := s.authorize(ctx, profileID, access.ActionEdit); err != nil {
+		return nil, err
+	}
+
+	var updatedProject *Project
+	err := s.transactor.WithTransaction(ctx, func(ctx context.Context) error {
+		current, err := s.repo.GetByID(ctx, profileID, id)
+		if err != nil {
+			return err
+		}
+		if err := s.repo.Update(ctx, profileID, id, project); err != nil {
+			return err
+		}
+		updated, err := s.repo.GetByID(ctx, profileID, id)
+		if err != nil {
+			return err
+		}
+		if !current.Visible && updated.Visible {
+			if err := s.publishPublished(ctx, updated); err != nil {
+				return err
+			}
+		}
+
+		updatedProject = updated
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
-	return projects, nil
+	return updatedProject, nil
+}
+
+func (s *Service) publishPublished(ctx context.Context, project *Project) error {
+	event, err := events.New(events.TypeProjectPublished, project.ProfileID, events.ProjectPublished{
+		ProjectID:   project.ID,
+		Name:        project.Name,
+		Description: project.Description,
+		TechStack:   project.TechStack,
+		GitHubURL:   project.GitHubURL,
+		LiveURL:     project.LiveURL,
+		PublishedAt: project.UpdatedAt,
+	})
+	if err != nil {
+		return err
+	}
+	return s.publisher.Publish(ctx, event)
+}
+
+func (s *Service) Delete(ctx context.Context, profileID, id string) error {
+	if err := s.authorize(ctx, profileID, access.ActionEdit); err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, profileID, id)
+}
+
+// Reorder sets the display order of a profile's projects. ids must list every project,
+// hidden ones included, exactly once. They are reordered in a single transaction.
+func (s *Service) Reorder(ctx context.Context, profileID string, ids []string) ([]Project, error) {
+	if err := s.authorize(ctx, profileID, access.ActionEdit); err != nil {
+		return nil, err
+	}
+
+	err := s.transactor.WithTransaction(ctx, func(ctx context.Context) error {
+		existing, err := s.repo.GetAllByProfileID(ctx, profileID)
+		if err != nil {
+			return err
+		}
+		existingIDs := make([]string, 0, len(existing))
+		for _, project := range existing {
+			existingIDs = append(existingIDs, project.ID)
+		}
+		if !common.IsPermutation(ids, existingIDs) {
+			return common.FieldErrors{{Field: "ids", Rule: "permutation", Message: "ids must list every project of the profile exactly once"}}
+		}
+
+		return s.repo.Reorder(ctx, profileID, ids)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetAllByProfileID(ctx, profileID)
+}
+
+func (s *Service) authorize(ctx context.Context, profileID string, action access.Action) error {
+	// Verify profile exists
+	exists, err := s.profileService.Exists(ctx, profileID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return types.ErrNotFound{Message: "profile not found"}
+	}
+
+	return s.authorizer.Authorize(ctx, profileID, action)
 }
@@ -1,54 +1,48 @@
 package questions
 
 import (
-	"encoding/json"
+	"errors"
 	"net/http"
 
-	"github.com/go-playground/validator/v10"
-
 	"github.com/mrthoabby/portfolio-api/internal/common"
 )
 
 type Handler struct {
 	service   *Service
-	validator *validator.Validate
+	validator *common.Validator
 }
 
 func NewHandler(service *Service) *Handler {
 	return &Handler{
 		service:   service,
-		validator: validator.New(),
+		validator: common.NewValidator(),
 	}
 }
 
 func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
 	profileID := r.PathValue("id")
 	if profileID == "" {
-		common.RespondError(w, http.StatusBadRequest, "BAD_REQUEST", "Profile ID is required", nil)
+		common.RespondError(w, r, http.StatusBadRequest, "BAD_REQUEST", "Profile ID is required", nil)
 		return
 	}
 
 	// Validate UUID format
 	if !common.IsValidUUID(profileID) {
-		common.RespondError(w, http.StatusBadRequest, "BAD_REQUEST", "Invalid profile ID format", nil)
+		common.RespondError(w, r, http.StatusBadRequest, "BAD_REQUEST", "Invalid profile ID format", nil)
 		return
 	}
 
 	var questionReq Request
-	if err := json.NewDecoder(r.Body).Decode(&questionReq); err != nil {
-		if err.Error() == "http: request body too large" {
-			common.RespondError(w, http.StatusRequestEntityTooLarge, "PAYLOAD_TOO_LARGE", "Request body too large", nil)
-			return
-		}
-		common.RespondError(w, http.StatusBadRequest, "BAD_REQUEST", "Invalid request body", nil)
+	if err := common.DecodeJSON(r, &questionReq); err != nil {
+		common.RespondServiceError(w, r, err)
 		return
 	}
 
 	// Sanitize message input
 	questionReq.Message = common.SanitizeString(common.StripHTMLTags(questionReq.Message))
 
-	if err := h.validator.Struct(&questionReq); err != nil {
-		common.RespondError(w, http.StatusBadRequest, "VALIDATION_ERROR", "Validation failed", err.Error())
+	if err := h.validator.Validate(r, &questionReq); err != nil {
+		common.RespondServiceError(w, r, err)
 		return
 	}
 
@@ -57,7 +51,7 @@ func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
 
 	question, err := h.service.Create(r.Context(), profileID, questionReq.Message, clientIP)
 	if err != nil {
-		common.RespondError(w, http.StatusNotFound, "NOT_FOUND", err.Error(), nil)
+		common.RespondServiceError(w, r, err)
 		return
 	}
 
@@ -69,3 +63,185 @@ func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
 
 	common.RespondJSON(w, http.StatusCreated, response)
 }
+
+// GetFAQ handles GET /profiles/{id}/faq, listing published questions and answers
+func (h *Handler) GetFAQ(w http.ResponseWriter, r *http.Request) {
+	profileID := r.PathValue("id")
+	if profileID == "" {
+		common.RespondError(w, r, http.StatusBadRequest, "BAD_REQUEST", "Profile ID is required", nil)
+		return
+	}
+
+	// Validate UUID format
+	if !common.IsValidUUID(profileID) {
+		common.RespondError(w, r, http.StatusBadRequest, "BAD_REQUEST", "Invalid profile ID format", nil)
+		return
+	}
+
+	faq, err := h.service.GetFAQ(r.Context(), profileID)
+	if err != nil {
+		common.RespondServiceError(w, r, err)
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, FAQResponse{FAQ: faq})
+}
+
+// GetByProfileID handles GET /admin/profiles/{id}/questions.
+// Supported query parameters: status, cursor and limit.
+func (h *Handler) GetByProfileID(w http.ResponseWriter, r *http.Request) {
+	profileID := r.PathValue("id")
+	if !common.IsValidUUID(profileID) {
+		common.RespondError(w, r, http.StatusBadRequest, "BAD_REQUEST", "Invalid profile ID format", nil)
+		return
+	}
+
+	filter, err := parseListFilter(r)
+	if err != nil {
+		common.RespondError(w, r, http.StatusBadRequest, "BAD_REQUEST", err.Error(), nil)
+		return
+	}
+
+	questions, hasMore, err := h.service.GetByProfileID(r.Context(), profileID, filter)
+	if err != nil {
+		common.RespondServiceError(w, r, err)
+		return
+	}
+
+	response := PageResponse{Questions: questions}
+	if response.Questions == nil {
+		response.Questions = []Question{}
+	}
+	if hasMore {
+		last := questions[len(questions)-1]
+		response.NextCursor = common.Cursor{CreatedAt: last.CreatedAt, ID: last.ID}.Encode()
+	}
+
+	common.RespondJSON(w, http.StatusOK, response)
+}
+
+// Answer handles POST /admin/profiles/{id}/questions/{questionId}/answer
+func (h *Handler) Answer(w http.ResponseWriter, r *http.Request) {
+	profileID, questionID, ok := questionIDsFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	var answerReq AnswerRequest
+	if !h.decodeAndValidate(w, r, &answerReq) {
+		return
+	}
+
+	answer := common.SanitizeString(common.StripHTMLTags(answerReq.Answer))
+	question, err := h.service.Answer(r.Context(), profileID, questionID, answer)
+	if err != nil {
+		common.RespondServiceError(w, r, err)
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, question)
+}
+
+// Reject handles POST /admin/profiles/{id}/questions/{questionId}/reject
+func (h *Handler) Reject(w http.ResponseWriter, r *http.Request) {
+	profileID, questionID, ok := questionIDsFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	var rejectReq RejectRequest
+	if r.ContentLength != 0 && !h.decodeAndValidate(w, r, &rejectReq) {
+		return
+	}
+
+	reason := common.SanitizeString(common.StripHTMLTags(rejectReq.Reason))
+	question, err := h.service.Reject(r.Context(), profileID, questionID, reason)
+	if err != nil {
+		common.RespondServiceError(w, r, err)
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, question)
+}
+
+// Publish handles POST /admin/profiles/{id}/questions/{questionId}/publish
+func (h *Handler) Publish(w http.ResponseWriter, r *http.Request) {
+	profileID, questionID, ok := questionIDsFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	question, err := h.service.Publish(r.Context(), profileID, questionID)
+	if err != nil {
+		common.RespondServiceError(w, r, err)
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, question)
+}
+
+// Unpublish handles POST /admin/profiles/{id}/questions/{questionId}/unpublish
+func (h *Handler) Unpublish(w http.ResponseWriter, r *http.Request) {
+	profileID, questionID, ok := questionIDsFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	question, err := h.service.Unpublish(r.Context(), profileID, questionID)
+	if err != nil {
+		common.RespondServiceError(w, r, err)
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, question)
+}
+
+func (h *Handler) decodeAndValidate(w http.ResponseWriter, r *http.Request, dst interface{}) bool {
+	if err := common.DecodeJSON(r, dst); err != nil {
+		common.RespondServiceError(w, r, err)
+		return false
+	}
+
+	if err := h.validator.Validate(r, dst); err != nil {
+		common.RespondServiceError(w, r, err)
+		return false
+	}
+	return true
+}
+
+func parseListFilter(r *http.Request) (ListFilter, error) {
+	query := r.URL.Query()
+	var filter ListFilter
+	var err error
+
+	if filter.Limit, err = common.ParsePageLimit(query); err != nil {
+		return filter, err
+	}
+
+	filter.Status = query.Get("status")
+	switch filter.Status {
+	case "", StatusPending, StatusAnswered, StatusRejected, StatusPublished:
+	default:
+		return filter, errors.New("status must be one of pending, answered, rejected, published")
+	}
+
+	if value := query.Get("cursor"); value != "" {
+		cursor, err := common.DecodeCursor(value)
+		if err != nil {
+			return filter, err
+		}
+		filter.Cursor = &cursor
+	}
+
+	return filter, nil
+}
+
+func questionIDsFromPath(w http.ResponseWriter, r *http.Request) (string, string, bool) {
+	profileID := r.PathValue("id")
+	questionID := r.PathValue("questionId")
+	if !common.IsValidUUID(profileID) || !common.IsValidUUID(questionID) {
+		common.RespondError(w, r, http.StatusBadRequest, "BAD_REQUEST", "Invalid profile or question ID format", nil)
+		return "", "", false
+	}
+	return profileID, questionID, true
+}
@@ -15,6 +15,7 @@ func TestHandler_Create_InvalidProfileID(t *testing.T) {
 
 	body := `{"message": "Test question message"}`
 	req := httptest.NewRequest(http.MethodPost, "/api/v1/profiles/invalid-uuid/questions", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
 	req.SetPathValue("id", "invalid-uuid")
 	rec := httptest.NewRecorder()
 
@@ -46,6 +47,7 @@ func TestHandler_Create_MissingProfileID(t *testing.T) {
 
 	body := `{"message": "Test question message"}`
 	req := httptest.NewRequest(http.MethodPost, "/api/v1/profiles//questions", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
 	req.SetPathValue("id", "")
 	rec := httptest.NewRecorder()
 
@@ -63,6 +65,7 @@ func TestHandler_Create_InvalidJSON(t *testing.T) {
 
 	body := `{invalid json}`
 	req := httptest.NewRequest(http.MethodPost, "/api/v1/profiles/550e8400-e29b-41d4-a716-446655440000/questions", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
 	req.SetPathValue("id", "550e8400-e29b-41d4-a716-446655440000")
 	rec := httptest.NewRecorder()
 
@@ -72,3 +75,78 @@ func TestHandler_Create_InvalidJSON(t *testing.T) {
 		t.Errorf("expected status %d, got %d", http.StatusBadRequest, rec.Code)
 	}
 }
+
+func TestHandler_GetFAQ_InvalidProfileID(t *testing.T) {
+	handler := &Handler{}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/profiles/invalid-uuid/faq", nil)
+	req.SetPathValue("id", "invalid-uuid")
+	rec := httptest.NewRecorder()
+
+	handler.GetFAQ(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, rec.Code)
+	}
+}
+
+func TestHandler_GetByProfileID_InvalidStatus(t *testing.T) {
+	handler := &Handler{}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/profiles/123e4567-e89b-12d3-a456-426614174000/questions?status=deleted", nil)
+	req.SetPathValue("id", "123e4567-e89b-12d3-a456-426614174000")
+	rec := httptest.NewRecorder()
+
+	handler.GetByProfileID(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, rec.Code)
+	}
+}
+
+func TestHandler_Answer_InvalidQuestionID(t *testing.T) {
+	handler := NewHandler(nil)
+
+	body := `{"answer": "A valid answer"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/profiles/123e4567-e89b-12d3-a456-426614174000/questions/invalid-uuid/answer", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.SetPathValue("id", "123e4567-e89b-12d3-a456-426614174000")
+	req.SetPathValue("questionId", "invalid-uuid")
+	rec := httptest.NewRecorder()
+
+	handler.Answer(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, rec.Code)
+	}
+}
+
+func TestHandler_Answer_EmptyAnswer(t *testing.T) {
+	handler := NewHandler(nil)
+
+	body := `{"answer": ""}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/profiles/123e4567-e89b-12d3-a456-426614174000/questions/123e4567-e89b-12d3-a456-426614174001/answer", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.SetPathValue("id", "123e4567-e89b-12d3-a456-426614174000")
+	req.SetPathValue("questionId", "123e4567-e89b-12d3-a456-426614174001")
+	rec := httptest.NewRecorder()
+
+	handler.Answer(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, rec.Code)
+	}
+}
+
+func TestFAQEntry_OmitsAskerIP(t *testing.T) {
+	question := Question{ID: "q-1", Message: "Question?", Answer: "Answer.", IP: "203.0.113.7"}
+
+	data, err := json.Marshal(question.ToFAQEntry())
+	if err != nil {
+		t.Fatalf("failed to marshal FAQ entry: %v", err)
+	}
+
+	if bytes.Contains(data, []byte("203.0.113.7")) {
+		t.Errorf("expected FAQ entry not to expose the asker IP, got %s", data)
+	}
+}
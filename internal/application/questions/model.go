@@ -1,13 +1,32 @@
 package questions
 
-import "time"
+import (
+	"time"
+
+	"github.com/mrthoabby/portfolio-api/internal/common"
+)
+
+// Status constants for question moderation
+const (
+	StatusPending   = "pending"
+	StatusAnswered  = "answered"
+	StatusRejected  = "rejected"
+	StatusPublished = "published"
+)
 
 type Question struct {
-	ID        string    `json:"id" bson:"_id,omitempty"`
-	ProfileID string    `json:"profileId" bson:"profileId"`
-	Message   string    `json:"message" bson:"message"`
-	IP        string    `json:"ip" bson:"ip"`
-	CreatedAt time.Time `json:"createdAt" bson:"createdAt"`
+	ID             string     `json:"id" bson:"_id,omitempty"`
+	ProfileID      string     `json:"profileId" bson:"profileId"`
+	Message        string     `json:"message" bson:"message"`
+	IP             string     `json:"ip" bson:"ip"`
+	Status         string     `json:"status" bson:"status"`
+	Answer         string     `json:"answer,omitempty" bson:"answer,omitempty"`
+	AnsweredBy     string     `json:"answeredBy,omitempty" bson:"answeredBy,omitempty"`
+	AnsweredAt     *time.Time `json:"answeredAt,omitempty" bson:"answeredAt,omitempty"`
+	RejectedReason string     `json:"rejectedReason,omitempty" bson:"rejectedReason,omitempty"`
+	PublishedAt    *time.Time `json:"publishedAt,omitempty" bson:"publishedAt,omitempty"`
+	CreatedAt      time.Time  `json:"createdAt" bson:"createdAt"`
+	UpdatedAt      time.Time  `json:"updatedAt" bson:"updatedAt"`
 }
 
 type Request struct {
@@ -19,3 +38,56 @@ type Response struct {
 	Message   string    `json:"message"`
 	CreatedAt time.Time `json:"createdAt"`
 }
+
+type AnswerRequest struct {
+	Answer string `json:"answer" validate:"required,min=2,max=5000"`
+}
+
+type RejectRequest struct {
+	Reason string `json:"reason" validate:"max=500"`
+}
+
+// ListFilter selects the questions returned by the moderation queue
+type ListFilter struct {
+	Status string
+	Cursor *common.Cursor
+	Limit  int
+}
+
+// PageResponse is a page of the moderation queue
+type PageResponse struct {
+	Questions  []Question `json:"questions"`
+	NextCursor string     `json:"nextCursor,omitempty"`
+}
+
+// FAQEntry is a published question and its answer. It deliberately leaves out
+// everything about the person who asked, including the stored IP.
+type FAQEntry struct {
+	ID          string     `json:"id"`
+	Question    string     `json:"question"`
+	Answer      string     `json:"answer"`
+	PublishedAt *time.Time `json:"publishedAt,omitempty"`
+}
+
+type FAQResponse struct {
+	FAQ []FAQEntry `json:"faq"`
+}
+
+// CurrentStatus returns the question status, treating questions stored before
+// moderation existed as pending
+func (q *Question) CurrentStatus() string {
+	if q.Status == "" {
+		return StatusPending
+	}
+	return q.Status
+}
+
+// ToFAQEntry returns the public view of a published question
+func (q *Question) ToFAQEntry() FAQEntry {
+	return FAQEntry{
+		ID:          q.ID,
+		Question:    q.Message,
+		Answer:      q.Answer,
+		PublishedAt: q.PublishedAt,
+	}
+}
@@ -6,9 +6,14 @@ import (
 
 	"github.com/google/uuid"
 
+	"github.com/mrthoabby/portfolio-api/internal/common"
 	"github.com/mrthoabby/portfolio-api/internal/common/contracts"
+	"github.com/mrthoabby/portfolio-api/internal/common/types"
 )
 
+// ErrInvalidTransition is returned when a question is not in a status that allows the operation
+var ErrInvalidTransition = types.ErrConflict{Code: "INVALID_TRANSITION", Message: "question is not in a status that allows this operation"}
+
 type Repository struct {
 	store contracts.Store
 }
@@ -26,7 +31,9 @@ func (r *Repository) Create(ctx context.Context, profileID, message, ip string)
 		ProfileID: profileID,
 		Message:   message,
 		IP:        ip,
+		Status:    StatusPending,
 		CreatedAt: now,
+		UpdatedAt: now,
 	}
 
 	err := r.store.InsertOne(ctx, newQuestion)
@@ -37,3 +44,108 @@ func (r *Repository) Create(ctx context.Context, profileID, message, ip string)
 	return newQuestion, nil
 }
 
+// GetByProfileID returns one page of questions, newest first.
+// It fetches one extra record to know whether another page follows.
+func (r *Repository) GetByProfileID(ctx context.Context, profileID string, listFilter ListFilter) ([]Question, bool, error) {
+	filter := map[string]interface{}{"profileId": profileID}
+
+	if listFilter.Status != "" {
+		filter["status"] = map[string]interface{}{"$in": storedStatuses(listFilter.Status)}
+	}
+	if listFilter.Cursor != nil {
+		filter["$or"] = common.AfterCursorFilter(*listFilter.Cursor)
+	}
+
+	var questions []Question
+	opts := contracts.FindOptions{
+		Sort:  []string{"-createdAt", "-_id"},
+		Limit: int64(listFilter.Limit) + 1,
+	}
+	if err := r.store.FindManyWithOptions(ctx, filter, opts, &questions); err != nil {
+		return nil, false, err
+	}
+
+	hasMore := len(questions) > listFilter.Limit
+	if hasMore {
+		questions = questions[:listFilter.Limit]
+	}
+	return questions, hasMore, nil
+}
+
+// GetPublished returns the published questions of a profile, most recently published first
+func (r *Repository) GetPublished(ctx context.Context, profileID string) ([]Question, error) {
+	var questions []Question
+	filter := map[string]interface{}{
+		"profileId": profileID,
+		"status":    StatusPublished,
+	}
+	sortFields := []string{"-publishedAt"}
+
+	err := r.store.FindMany(ctx, filter, sortFields, &questions)
+	if err != nil {
+		return nil, err
+	}
+
+	return questions, nil
+}
+
+func (r *Repository) GetByID(ctx context.Context, profileID, id string) (*Question, error) {
+	var question Question
+	err := r.store.FindOne(ctx, map[string]interface{}{"_id": id, "profileId": profileID}, &question)
+	if err != nil {
+		if types.IsNotFoundError(err) {
+			return nil, types.ErrNotFound{Message: "question not found"}
+		}
+		return nil, err
+	}
+	return &question, nil
+}
+
+// DeleteByProfileID deletes every question of a profile
+func (r *Repository) DeleteByProfileID(ctx context.Context, profileID string) error {
+	_, err := r.store.DeleteMany(ctx, map[string]interface{}{"profileId": profileID})
+	return err
+}
+
+// Transition applies update to a question currently in one of the from statuses and moves it
+// to the to status. Returns ErrInvalidTransition when the question is in any other status.
+func (r *Repository) Transition(ctx context.Context, profileID, id string, from []string, to string, update contracts.Update) error {
+	if update.Set == nil {
+		update.Set = map[string]interface{}{}
+	}
+	update.Set["status"] = to
+	update.Set["updatedAt"] = time.Now()
+
+	filter := map[string]interface{}{
+		"_id":       id,
+		"profileId": profileID,
+		"status":    map[string]interface{}{"$in": storedStatuses(from...)},
+	}
+
+	err := r.store.UpdateOneWith(ctx, filter, update)
+	if err == nil {
+		return nil
+	}
+	if !types.IsNotFoundError(err) {
+		return err
+	}
+
+	// Tell a missing question apart from one in the wrong status
+	if _, err := r.GetByID(ctx, profileID, id); err != nil {
+		return err
+	}
+	return ErrInvalidTransition
+}
+
+// storedStatuses expands statuses into the values they may have in the store:
+// questions created before moderation existed have no status and count as pending
+func storedStatuses(statuses ...string) []interface{} {
+	values := make([]interface{}, 0, len(statuses)+1)
+	for _, status := range statuses {
+		values = append(values, status)
+		if status == StatusPending {
+			values = append(values, nil)
+		}
+	}
+	return values
+}
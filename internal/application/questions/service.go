@@ -2,20 +2,37 @@ package questions
 
 import (
 	"context"
-	"errors"
+	"time"
 
+	"github.com/mrthoabby/portfolio-api/internal/access"
 	"github.com/mrthoabby/portfolio-api/internal/application/profile"
+	"github.com/mrthoabby/portfolio-api/internal/application/settings"
+	"github.com/mrthoabby/portfolio-api/internal/common"
+	"github.com/mrthoabby/portfolio-api/internal/common/contracts"
+	"github.com/mrthoabby/portfolio-api/internal/common/types"
+	"github.com/mrthoabby/portfolio-api/internal/events"
 )
 
+// ErrQuestionsDisabled is returned when the profile settings turned questions off
+var ErrQuestionsDisabled = types.ErrConflict{Code: "QUESTIONS_DISABLED", Message: "this profile does not accept questions"}
+
 type Service struct {
-	repo           *Repository
-	profileService *profile.Service
+	repo            *Repository
+	profileService  *profile.Service
+	settingsService *settings.Service
+	authorizer      *access.Authorizer
+	transactor      contracts.Transactor
+	publisher       events.Publisher
 }
 
-func NewService(repo *Repository, profileService *profile.Service) *Service {
+func NewService(repo *Repository, profileService *profile.Service, settingsService *settings.Service, authorizer *access.Authorizer, transactor contracts.Transactor, publisher events.Publisher) *Service {
 	return &Service{
-		repo:           repo,
-		profileService: profileService,
+		repo:            repo,
+		profileService:  profileService,
+		settingsService: settingsService,
+		authorizer:      authorizer,
+		transactor:      transactor,
+		publisher:       publisher,
 	}
 }
 
@@ -26,13 +43,148 @@ func (s *Service) Create(ctx context.Context, profileID, message, ip string) (*Q
 		return nil, err
 	}
 	if !exists {
-		return nil, errors.New("profile not found")
+		return nil, types.ErrNotFound{Message: "profile not found"}
+	}
+
+	profileSettings, err := s.settingsService.ForProfile(ctx, profileID)
+	if err != nil {
+		return nil, err
+	}
+	if !profileSettings.QuestionsEnabled {
+		return nil, ErrQuestionsDisabled
 	}
 
-	createdQuestion, err := s.repo.Create(ctx, profileID, message, ip)
+	// The event is written with the question, so its side effects cannot be lost
+	var createdQuestion *Question
+	err = s.transactor.WithTransaction(ctx, func(ctx context.Context) error {
+		created, err := s.repo.Create(ctx, profileID, message, ip)
+		if err != nil {
+			return err
+		}
+
+		event, err := events.New(events.TypeQuestionCreated, profileID, events.QuestionCreated{
+			QuestionID: created.ID,
+			Message:    created.Message,
+			CreatedAt:  created.CreatedAt,
+		})
+		if err != nil {
+			return err
+		}
+		if err := s.publisher.Publish(ctx, event); err != nil {
+			return err
+		}
+
+		createdQuestion = created
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
 	return createdQuestion, nil
 }
 
+// GetFAQ returns the published questions and answers of a profile
+func (s *Service) GetFAQ(ctx context.Context, profileID string) ([]FAQEntry, error) {
+	// Verify profile exists
+	exists, err := s.profileService.Exists(ctx, profileID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, types.ErrNotFound{Message: "profile not found"}
+	}
+
+	return s.ListFAQ(ctx, profileID)
+}
+
+// ListFAQ returns the published questions of a profile without checking that the profile exists.
+// Callers that already validated the profile use it to skip the extra lookup.
+func (s *Service) ListFAQ(ctx context.Context, profileID string) ([]FAQEntry, error) {
+	questions, err := s.repo.GetPublished(ctx, profileID)
+	if err != nil {
+		return nil, err
+	}
+
+	faq := make([]FAQEntry, 0, len(questions))
+	for i := range questions {
+		faq = append(faq, questions[i].ToFAQEntry())
+	}
+	return faq, nil
+}
+
+// GetByProfileID returns a page of the profile's moderation queue
+func (s *Service) GetByProfileID(ctx context.Context, profileID string, filter ListFilter) ([]Question, bool, error) {
+	if err := s.authorize(ctx, profileID, access.ActionRead); err != nil {
+		return nil, false, err
+	}
+	return s.repo.GetByProfileID(ctx, profileID, filter)
+}
+
+// Answer sets the answer of a pending or answered question
+func (s *Service) Answer(ctx context.Context, profileID, id, answer string) (*Question, error) {
+	now := time.Now()
+	update := contracts.Update{
+		Set: map[string]interface{}{
+			"answer":     answer,
+			"answeredBy": subjectFromContext(ctx),
+			"answeredAt": now,
+		},
+		Unset: []string{"rejectedReason"},
+	}
+	return s.transition(ctx, profileID, id, []string{StatusPending, StatusAnswered}, StatusAnswered, update)
+}
+
+// Reject takes a question out of the moderation queue without answering it
+func (s *Service) Reject(ctx context.Context, profileID, id, reason string) (*Question, error) {
+	update := contracts.Update{}
+	if reason != "" {
+		update.Set = map[string]interface{}{"rejectedReason": reason}
+	}
+	return s.transition(ctx, profileID, id, []string{StatusPending, StatusAnswered}, StatusRejected, update)
+}
+
+// Publish makes an answered question visible in the public FAQ
+func (s *Service) Publish(ctx context.Context, profileID, id string) (*Question, error) {
+	update := contracts.Update{
+		Set: map[string]interface{}{"publishedAt": time.Now()},
+	}
+	return s.transition(ctx, profileID, id, []string{StatusAnswered}, StatusPublished, update)
+}
+
+// Unpublish removes a question from the public FAQ, keeping its answer
+func (s *Service) Unpublish(ctx context.Context, profileID, id string) (*Question, error) {
+	update := contracts.Update{
+		Unset: []string{"publishedAt"},
+	}
+	return s.transition(ctx, profileID, id, []string{StatusPublished}, StatusAnswered, update)
+}
+
+func (s *Service) transition(ctx context.Context, profileID, id string, from []string, to string, update contracts.Update) (*Question, error) {
+	if err := s.authorize(ctx, profileID, access.ActionEdit); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Transition(ctx, profileID, id, from, to, update); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(ctx, profileID, id)
+}
+
+func (s *Service) authorize(ctx context.Context, profileID string, action access.Action) error {
+	// Verify profile exists
+	exists, err := s.profileService.Exists(ctx, profileID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return types.ErrNotFound{Message: "profile not found"}
+	}
+
+	return s.authorizer.Authorize(ctx, profileID, action)
+}
+
+func subjectFromContext(ctx context.Context) string {
+	if principal := common.PrincipalFromContext(ctx); principal != nil {
+		return principal.Subject
+	}
+	return ""
+}
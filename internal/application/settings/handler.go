@@ -0,0 +1,64 @@
+package settings
+
+import (
+	"net/http"
+
+	"github.com/mrthoabby/portfolio-api/internal/common"
+)
+
+type Handler struct {
+	service   *Service
+	validator *common.Validator
+}
+
+func NewHandler(service *Service) *Handler {
+	return &Handler{
+		service:   service,
+		validator: common.NewValidator(),
+	}
+}
+
+// GetByProfileID handles GET /admin/profiles/{id}/settings
+func (h *Handler) GetByProfileID(w http.ResponseWriter, r *http.Request) {
+	profileID := r.PathValue("id")
+	if !common.IsValidUUID(profileID) {
+		common.RespondError(w, r, http.StatusBadRequest, "BAD_REQUEST", "Invalid profile ID format", nil)
+		return
+	}
+
+	settings, err := h.service.GetByProfileID(r.Context(), profileID)
+	if err != nil {
+		common.RespondServiceError(w, r, err)
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, settings)
+}
+
+// Update handles PUT /admin/profiles/{id}/settings, replacing every setting
+func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
+	profileID := r.PathValue("id")
+	if !common.IsValidUUID(profileID) {
+		common.RespondError(w, r, http.StatusBadRequest, "BAD_REQUEST", "Invalid profile ID format", nil)
+		return
+	}
+
+	var settingsReq Request
+	if err := common.DecodeJSON(r, &settingsReq); err != nil {
+		common.RespondServiceError(w, r, err)
+		return
+	}
+
+	if err := h.validator.Validate(r, &settingsReq); err != nil {
+		common.RespondServiceError(w, r, err)
+		return
+	}
+
+	settings, err := h.service.Update(r.Context(), profileID, &settingsReq)
+	if err != nil {
+		common.RespondServiceError(w, r, err)
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, settings)
+}
@@ -0,0 +1,57 @@
+package settings
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_InvalidProfileID(t *testing.T) {
+	handler := NewHandler(&Service{})
+
+	for name, handle := range map[string]http.HandlerFunc{"get": handler.GetByProfileID, "update": handler.Update} {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/api/v1/admin/profiles/not-a-uuid/settings", strings.NewReader("{}"))
+			req.Header.Set("Content-Type", "application/json")
+			req.SetPathValue("id", "not-a-uuid")
+			w := httptest.NewRecorder()
+
+			handle(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+}
+
+func TestHandler_Update_InvalidRequest(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"invalid email", `{"notifications": {"emails": ["not-an-email"]}}`},
+		{"negative threshold", `{"spamThreshold": -1}`},
+		{"threshold too high", `{"spamThreshold": 101}`},
+		{"auto-reply too long", `{"autoReplyMessage": "` + strings.Repeat("a", 2001) + `"}`},
+		{"invalid webhook ID", `{"notifications": {"webhookIds": ["hook-1"]}}`},
+		{"unknown event type", `{"notifications": {"webhookEvents": ["contact.deleted"]}}`},
+		{"unknown field", `{"contactForm": false}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewHandler(&Service{})
+
+			req := httptest.NewRequest("PUT", "/api/v1/admin/profiles/550e8400-e29b-41d4-a716-446655440000/settings", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			req.SetPathValue("id", "550e8400-e29b-41d4-a716-446655440000")
+			w := httptest.NewRecorder()
+
+			handler.Update(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+}
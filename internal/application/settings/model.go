@@ -0,0 +1,76 @@
+package settings
+
+import (
+	"slices"
+	"time"
+)
+
+// Settings control the public features of a profile. Profiles that never saved settings get Defaults.
+type Settings struct {
+	ProfileID        string        `json:"profileId" bson:"_id"`
+	ContactsEnabled  bool          `json:"contactsEnabled" bson:"contactsEnabled"`
+	QuestionsEnabled bool          `json:"questionsEnabled" bson:"questionsEnabled"`
+	Notifications    Notifications `json:"notifications" bson:"notifications"`
+	SpamThreshold    int           `json:"spamThreshold,omitempty" bson:"spamThreshold,omitempty"`
+	AutoReplyMessage string        `json:"autoReplyMessage,omitempty" bson:"autoReplyMessage,omitempty"`
+	UpdatedAt        *time.Time    `json:"updatedAt,omitempty" bson:"updatedAt,omitempty"`
+}
+
+// Notifications routes what happens on a profile
+type Notifications struct {
+	// Emails are notified about new contacts and questions instead of the server recipients when set
+	Emails []string `json:"emails" bson:"emails"`
+	// Webhooks delivers events to the profile webhooks; while disabled, events are discarded rather than queued
+	Webhooks bool `json:"webhooks" bson:"webhooks"`
+	// WebhookIDs, when set, limits deliveries to these webhooks of the profile
+	WebhookIDs []string `json:"webhookIds" bson:"webhookIds"`
+	// WebhookEvents, when set, limits deliveries to these event types
+	WebhookEvents []string `json:"webhookEvents" bson:"webhookEvents"`
+}
+
+// DeliversTo reports whether events of eventType are delivered to the webhook identified by webhookID.
+// The webhook must still subscribe to the event type itself.
+func (n Notifications) DeliversTo(webhookID, eventType string) bool {
+	if !n.Webhooks {
+		return false
+	}
+	if len(n.WebhookIDs) > 0 && !slices.Contains(n.WebhookIDs, webhookID) {
+		return false
+	}
+	return len(n.WebhookEvents) == 0 || slices.Contains(n.WebhookEvents, eventType)
+}
+
+// Defaults returns the settings of a profile that never saved any: every feature enabled,
+// notifications sent to the server recipients and the server spam threshold
+func Defaults(profileID string) *Settings {
+	return &Settings{
+		ProfileID:        profileID,
+		ContactsEnabled:  true,
+		QuestionsEnabled: true,
+		Notifications: Notifications{
+			Emails:        []string{},
+			Webhooks:      true,
+			WebhookIDs:    []string{},
+			WebhookEvents: []string{},
+		},
+	}
+}
+
+// Request replaces the settings of a profile; omitted switches are enabled
+type Request struct {
+	ContactsEnabled  *bool                `json:"contactsEnabled"`
+	QuestionsEnabled *bool                `json:"questionsEnabled"`
+	Notifications    NotificationsRequest `json:"notifications"`
+	// SpamThreshold is the content score from which contacts are spam; 0 uses the server threshold
+	SpamThreshold int `json:"spamThreshold" validate:"min=0,max=100"`
+	// AutoReplyMessage replaces the greeting of the email confirming a contact to its sender
+	AutoReplyMessage string `json:"autoReplyMessage" validate:"max=2000"`
+}
+
+type NotificationsRequest struct {
+	Emails   []string `json:"emails" validate:"max=10,dive,email"`
+	Webhooks *bool    `json:"webhooks"`
+	// WebhookIDs and WebhookEvents select the webhooks and event types delivered; empty delivers all of them
+	WebhookIDs    []string `json:"webhookIds" validate:"max=10,dive,uuid"`
+	WebhookEvents []string `json:"webhookEvents" validate:"dive,oneof=contact.created question.created project.published"`
+}
@@ -0,0 +1,73 @@
+package settings
+
+import (
+	"context"
+	"time"
+
+	"github.com/mrthoabby/portfolio-api/internal/common/contracts"
+	"github.com/mrthoabby/portfolio-api/internal/common/types"
+)
+
+type Repository struct {
+	store contracts.Store
+}
+
+func NewRepository(dataSource contracts.DataSource) *Repository {
+	return &Repository{
+		store: dataSource.Store("profile_settings"),
+	}
+}
+
+// GetByProfileID returns the settings of a profile, or Defaults when it never saved any
+func (r *Repository) GetByProfileID(ctx context.Context, profileID string) (*Settings, error) {
+	var settings Settings
+	err := r.store.FindOne(ctx, map[string]interface{}{"_id": profileID}, &settings)
+	if err != nil {
+		if types.IsNotFoundError(err) {
+			return Defaults(profileID), nil
+		}
+		return nil, err
+	}
+	if settings.Notifications.Emails == nil {
+		settings.Notifications.Emails = []string{}
+	}
+	if settings.Notifications.WebhookIDs == nil {
+		settings.Notifications.WebhookIDs = []string{}
+	}
+	if settings.Notifications.WebhookEvents == nil {
+		settings.Notifications.WebhookEvents = []string{}
+	}
+	return &settings, nil
+}
+
+// DeleteByProfileID deletes the settings of a profile
+func (r *Repository) DeleteByProfileID(ctx context.Context, profileID string) error {
+	_, err := r.store.DeleteMany(ctx, map[string]interface{}{"_id": profileID})
+	return err
+}
+
+// Save replaces the settings of a profile, creating them on first save, and returns the stored result
+func (r *Repository) Save(ctx context.Context, settings *Settings) (*Settings, error) {
+	update := contracts.Update{
+		Set: map[string]interface{}{
+			"contactsEnabled":  settings.ContactsEnabled,
+			"questionsEnabled": settings.QuestionsEnabled,
+			"notifications":    settings.Notifications,
+			"spamThreshold":    settings.SpamThreshold,
+			"autoReplyMessage": settings.AutoReplyMessage,
+			"updatedAt":        time.Now(),
+		},
+	}
+
+	var saved Settings
+	err := r.store.FindOneAndUpdate(ctx,
+		map[string]interface{}{"_id": settings.ProfileID},
+		update,
+		contracts.FindOneAndUpdateOptions{Upsert: true},
+		&saved,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &saved, nil
+}
@@ -0,0 +1,77 @@
+package settings
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrthoabby/portfolio-api/internal/common/contracts"
+	"github.com/mrthoabby/portfolio-api/internal/common/types"
+)
+
+// settingsStore keeps at most one settings document, applying upserts the way the database does
+type settingsStore struct {
+	contracts.Store
+	saved *Settings
+}
+
+func (s *settingsStore) FindOne(_ context.Context, filter map[string]interface{}, result interface{}) error {
+	if s.saved == nil || s.saved.ProfileID != filter["_id"] {
+		return types.ErrNotFound{Message: "record not found"}
+	}
+	*result.(*Settings) = *s.saved
+	return nil
+}
+
+func (s *settingsStore) FindOneAndUpdate(_ context.Context, filter map[string]interface{}, update contracts.Update, opts contracts.FindOneAndUpdateOptions, result interface{}) error {
+	if !opts.Upsert {
+		return types.ErrNotFound{Message: "record not found"}
+	}
+	s.saved = &Settings{
+		ProfileID:        filter["_id"].(string),
+		ContactsEnabled:  update.Set["contactsEnabled"].(bool),
+		QuestionsEnabled: update.Set["questionsEnabled"].(bool),
+		Notifications:    update.Set["notifications"].(Notifications),
+		SpamThreshold:    update.Set["spamThreshold"].(int),
+		AutoReplyMessage: update.Set["autoReplyMessage"].(string),
+	}
+	*result.(*Settings) = *s.saved
+	return nil
+}
+
+func TestRepository_GetByProfileID_Defaults(t *testing.T) {
+	repo := &Repository{store: &settingsStore{}}
+
+	found, err := repo.GetByProfileID(context.Background(), "profile-1")
+	require.NoError(t, err)
+	assert.Equal(t, Defaults("profile-1"), found)
+	assert.True(t, found.ContactsEnabled)
+	assert.True(t, found.QuestionsEnabled)
+	assert.True(t, found.Notifications.Webhooks)
+}
+
+func TestRepository_Save(t *testing.T) {
+	store := &settingsStore{}
+	repo := &Repository{store: store}
+
+	settings := &Settings{
+		ProfileID:       "profile-1",
+		ContactsEnabled: true,
+		Notifications:   Notifications{Emails: []string{"owner@example.com"}},
+		SpamThreshold:   3,
+	}
+	saved, err := repo.Save(context.Background(), settings)
+	require.NoError(t, err)
+	assert.Equal(t, settings, saved)
+
+	found, err := repo.GetByProfileID(context.Background(), "profile-1")
+	require.NoError(t, err)
+	assert.False(t, found.QuestionsEnabled)
+	assert.Equal(t, 3, found.SpamThreshold)
+
+	other, err := repo.GetByProfileID(context.Background(), "profile-2")
+	require.NoError(t, err)
+	assert.Equal(t, Defaults("profile-2"), other)
+}
@@ -0,0 +1,89 @@
+package settings
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/mrthoabby/portfolio-api/internal/access"
+	"github.com/mrthoabby/portfolio-api/internal/application/profile"
+	"github.com/mrthoabby/portfolio-api/internal/common/types"
+)
+
+type Service struct {
+	repo           *Repository
+	profileService *profile.Service
+	authorizer     *access.Authorizer
+}
+
+func NewService(repo *Repository, profileService *profile.Service, authorizer *access.Authorizer) *Service {
+	return &Service{
+		repo:           repo,
+		profileService: profileService,
+		authorizer:     authorizer,
+	}
+}
+
+// GetByProfileID returns the settings of a profile to its members
+func (s *Service) GetByProfileID(ctx context.Context, profileID string) (*Settings, error) {
+	if err := s.authorize(ctx, profileID, access.ActionRead); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByProfileID(ctx, profileID)
+}
+
+// Update replaces the settings of a profile and returns the stored result
+func (s *Service) Update(ctx context.Context, profileID string, request *Request) (*Settings, error) {
+	if err := s.authorize(ctx, profileID, access.ActionManage); err != nil {
+		return nil, err
+	}
+
+	emails := make([]string, 0, len(request.Notifications.Emails))
+	for _, email := range request.Notifications.Emails {
+		emails = append(emails, strings.ToLower(strings.TrimSpace(email)))
+	}
+
+	return s.repo.Save(ctx, &Settings{
+		ProfileID:        profileID,
+		ContactsEnabled:  request.ContactsEnabled == nil || *request.ContactsEnabled,
+		QuestionsEnabled: request.QuestionsEnabled == nil || *request.QuestionsEnabled,
+		Notifications: Notifications{
+			Emails:        emails,
+			Webhooks:      request.Notifications.Webhooks == nil || *request.Notifications.Webhooks,
+			WebhookIDs:    distinct(request.Notifications.WebhookIDs),
+			WebhookEvents: distinct(request.Notifications.WebhookEvents),
+		},
+		SpamThreshold:    request.SpamThreshold,
+		AutoReplyMessage: strings.TrimSpace(request.AutoReplyMessage),
+	})
+}
+
+// ForProfile returns the settings the public features of a profile follow. It does not check
+// access, so it is only meant for the domains enforcing the settings.
+func (s *Service) ForProfile(ctx context.Context, profileID string) (*Settings, error) {
+	return s.repo.GetByProfileID(ctx, profileID)
+}
+
+// distinct returns values without duplicates, in their original order
+func distinct(values []string) []string {
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		if !slices.Contains(result, value) {
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+func (s *Service) authorize(ctx context.Context, profileID string, action access.Action) error {
+	// Verify profile exists
+	exists, err := s.profileService.Exists(ctx, profileID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return types.ErrNotFound{Message: "profile not found"}
+	}
+
+	return s.authorizer.Authorize(ctx, profileID, action)
+}
@@ -7,29 +7,33 @@ import (
 )
 
 type Handler struct {
-	service *Service
+	service   *Service
+	validator *common.Validator
 }
 
 func NewHandler(service *Service) *Handler {
-	return &Handler{service: service}
+	return &Handler{
+		service:   service,
+		validator: common.NewValidator(),
+	}
 }
 
 func (h *Handler) GetByProfileID(w http.ResponseWriter, r *http.Request) {
 	profileID := r.PathValue("id")
 	if profileID == "" {
-		common.RespondError(w, http.StatusBadRequest, "BAD_REQUEST", "Profile ID is required", nil)
+		common.RespondError(w, r, http.StatusBadRequest, "BAD_REQUEST", "Profile ID is required", nil)
 		return
 	}
 
 	// Validate UUID format
 	if !common.IsValidUUID(profileID) {
-		common.RespondError(w, http.StatusBadRequest, "BAD_REQUEST", "Invalid profile ID format", nil)
+		common.RespondError(w, r, http.StatusBadRequest, "BAD_REQUEST", "Invalid profile ID format", nil)
 		return
 	}
 
 	skills, err := h.service.GetByProfileID(r.Context(), profileID)
 	if err != nil {
-		common.RespondError(w, http.StatusNotFound, "NOT_FOUND", err.Error(), nil)
+		common.RespondServiceError(w, r, err)
 		return
 	}
 
@@ -37,3 +41,137 @@ func (h *Handler) GetByProfileID(w http.ResponseWriter, r *http.Request) {
 	common.RespondJSON(w, http.StatusOK, response)
 }
 
+// GetCategories handles GET /skills/categories, listing the categories accepted by this deployment
+func (h *Handler) GetCategories(w http.ResponseWriter, r *http.Request) {
+	common.RespondJSON(w, http.StatusOK, CategoriesResponse{Categories: h.service.Categories()})
+}
+
+// Create handles POST /admin/profiles/{id}/skills
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	profileID, ok := profileIDFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	skillReq, ok := h.decodeSkill(w, r)
+	if !ok {
+		return
+	}
+
+	skill, err := h.service.Create(r.Context(), profileID, skillReq)
+	if err != nil {
+		common.RespondServiceError(w, r, err)
+		return
+	}
+
+	common.RespondJSON(w, http.StatusCreated, skill)
+}
+
+// Update handles PUT /admin/profiles/{id}/skills/{skillId}
+func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
+	profileID, skillID, ok := skillIDsFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	skillReq, ok := h.decodeSkill(w, r)
+	if !ok {
+		return
+	}
+
+	skill, err := h.service.Update(r.Context(), profileID, skillID, skillReq)
+	if err != nil {
+		common.RespondServiceError(w, r, err)
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, skill)
+}
+
+// Delete handles DELETE /admin/profiles/{id}/skills/{skillId}
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
+	profileID, skillID, ok := skillIDsFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(r.Context(), profileID, skillID); err != nil {
+		common.RespondServiceError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Reorder handles PUT /admin/profiles/{id}/skills/order
+func (h *Handler) Reorder(w http.ResponseWriter, r *http.Request) {
+	profileID, ok := profileIDFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	var reorderReq ReorderRequest
+	if !h.decodeAndValidate(w, r, &reorderReq) {
+		return
+	}
+
+	skills, err := h.service.Reorder(r.Context(), profileID, reorderReq.IDs)
+	if err != nil {
+		common.RespondServiceError(w, r, err)
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, Response{Skills: skills})
+}
+
+func (h *Handler) decodeAndValidate(w http.ResponseWriter, r *http.Request, dst interface{}) bool {
+	if err := common.DecodeJSON(r, dst); err != nil {
+		common.RespondServiceError(w, r, err)
+		return false
+	}
+
+	if err := h.validator.Validate(r, dst); err != nil {
+		common.RespondServiceError(w, r, err)
+		return false
+	}
+	return true
+}
+
+// decodeSkill decodes a skill request, sanitizing it before validation so the rules apply to the stored values
+func (h *Handler) decodeSkill(w http.ResponseWriter, r *http.Request) (*Request, bool) {
+	var skillReq Request
+	if err := common.DecodeJSON(r, &skillReq); err != nil {
+		common.RespondServiceError(w, r, err)
+		return nil, false
+	}
+	sanitize(&skillReq)
+
+	if err := h.validator.Validate(r, &skillReq); err != nil {
+		common.RespondServiceError(w, r, err)
+		return nil, false
+	}
+	return &skillReq, true
+}
+
+func sanitize(skillReq *Request) {
+	skillReq.Name = common.SanitizeString(common.StripHTMLTags(skillReq.Name))
+}
+
+func profileIDFromPath(w http.ResponseWriter, r *http.Request) (string, bool) {
+	profileID := r.PathValue("id")
+	if !common.IsValidUUID(profileID) {
+		common.RespondError(w, r, http.StatusBadRequest, "BAD_REQUEST", "Invalid profile ID format", nil)
+		return "", false
+	}
+	return profileID, true
+}
+
+func skillIDsFromPath(w http.ResponseWriter, r *http.Request) (string, string, bool) {
+	profileID := r.PathValue("id")
+	skillID := r.PathValue("skillId")
+	if !common.IsValidUUID(profileID) || !common.IsValidUUID(skillID) {
+		common.RespondError(w, r, http.StatusBadRequest, "BAD_REQUEST", "Invalid profile or skill ID format", nil)
+		return "", "", false
+	}
+	return profileID, skillID, true
+}
@@ -2,11 +2,15 @@ package skills
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/mrthoabby/portfolio-api/internal/common"
 )
 
 func TestHandler_GetByProfileID_MissingID(t *testing.T) {
@@ -29,3 +33,66 @@ func TestNewHandler(t *testing.T) {
 	assert.Equal(t, service, handler.service)
 }
 
+
+func TestHandler_Create_RejectsUnknownProficiency(t *testing.T) {
+	handler := NewHandler(&Service{})
+
+	body := `{"name": "Go", "category": "backend", "proficiency": "expert"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/profiles/123e4567-e89b-12d3-a456-426614174000/skills", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.SetPathValue("id", "123e4567-e89b-12d3-a456-426614174000")
+	w := httptest.NewRecorder()
+
+	handler.Create(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var response struct {
+		Error struct {
+			Code    string              `json:"code"`
+			Details []common.FieldError `json:"details"`
+		} `json:"error"`
+	}
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Equal(t, "VALIDATION_ERROR", response.Error.Code)
+	assert.Equal(t, []common.FieldError{{Field: "proficiency", Rule: "oneof", Param: "advanced occasional past", Message: "proficiency must be one of [advanced occasional past]"}}, response.Error.Details)
+}
+
+func TestHandler_Create_RejectsMarkupOnlyName(t *testing.T) {
+	handler := NewHandler(&Service{})
+
+	body := `{"name": "<b></b>", "category": "backend", "proficiency": "advanced"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/profiles/123e4567-e89b-12d3-a456-426614174000/skills", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.SetPathValue("id", "123e4567-e89b-12d3-a456-426614174000")
+	w := httptest.NewRecorder()
+
+	handler.Create(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"field":"name"`)
+}
+
+func TestHandler_Update_InvalidSkillID(t *testing.T) {
+	handler := NewHandler(&Service{})
+
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/admin/profiles/123e4567-e89b-12d3-a456-426614174000/skills/bad", strings.NewReader(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.SetPathValue("id", "123e4567-e89b-12d3-a456-426614174000")
+	req.SetPathValue("skillId", "bad")
+	w := httptest.NewRecorder()
+
+	handler.Update(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestHandler_GetCategories(t *testing.T) {
+	handler := NewHandler(NewService(nil, nil, nil, nil, []string{"languages", "cloud"}))
+
+	w := httptest.NewRecorder()
+	handler.GetCategories(w, httptest.NewRequest(http.MethodGet, "/api/v1/skills/categories", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"categories": ["languages", "cloud"]}`, w.Body.String())
+}
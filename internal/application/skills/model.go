@@ -15,15 +15,37 @@ const (
 	ProficiencyPast       = "past"
 )
 
+// DefaultCategories are the skill categories used when a deployment does not configure its own
+var DefaultCategories = []string{CategoryBackend, CategoryFrontend, CategoryTools, CategorySoftSkills}
+
 type Skill struct {
 	ID          string `json:"id" bson:"_id,omitempty"`
 	ProfileID   string `json:"profileId" bson:"profileId"`
 	Name        string `json:"name" bson:"name"`
 	Category    string `json:"category" bson:"category"`
 	Proficiency string `json:"proficiency" bson:"proficiency"`
+	Order       int    `json:"order" bson:"order"`
 }
 
 type Response struct {
 	Skills []Skill `json:"skills"`
 }
 
+// Request is the payload for creating or replacing a skill.
+// Category is checked against the configured categories by the service.
+type Request struct {
+	Name        string `json:"name" validate:"required,min=1,max=100"`
+	Category    string `json:"category" validate:"required"`
+	Proficiency string `json:"proficiency" validate:"required,oneof=advanced occasional past"`
+	Order       *int   `json:"order,omitempty" validate:"omitempty,min=0"`
+}
+
+// ReorderRequest lists every skill ID of a profile in the desired order
+type ReorderRequest struct {
+	IDs []string `json:"ids" validate:"required,min=1,dive,uuid"`
+}
+
+// CategoriesResponse lists the skill categories accepted by the deployment
+type CategoriesResponse struct {
+	Categories []string `json:"categories"`
+}
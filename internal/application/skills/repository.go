@@ -3,7 +3,10 @@ package skills
 import (
 	"context"
 
+	"github.com/google/uuid"
+
 	"github.com/mrthoabby/portfolio-api/internal/common/contracts"
+	"github.com/mrthoabby/portfolio-api/internal/common/types"
 )
 
 type Repository struct {
@@ -19,7 +22,7 @@ func NewRepository(dataSource contracts.DataSource) *Repository {
 func (r *Repository) GetByProfileID(ctx context.Context, profileID string) ([]Skill, error) {
 	var skills []Skill
 	filter := map[string]interface{}{"profileId": profileID}
-	sortFields := []string{"category", "name", "proficiency"}
+	sortFields := []string{"category", "order", "name", "proficiency"}
 
 	err := r.store.FindMany(ctx, filter, sortFields, &skills)
 	if err != nil {
@@ -28,3 +31,99 @@ func (r *Repository) GetByProfileID(ctx context.Context, profileID string) ([]Sk
 
 	return skills, nil
 }
+
+func (r *Repository) GetByID(ctx context.Context, profileID, id string) (*Skill, error) {
+	var skill Skill
+	err := r.store.FindOne(ctx, map[string]interface{}{"_id": id, "profileId": profileID}, &skill)
+	if err != nil {
+		if types.IsNotFoundError(err) {
+			return nil, types.ErrNotFound{Message: "skill not found"}
+		}
+		return nil, err
+	}
+	return &skill, nil
+}
+
+// Create stores a new skill. Without an explicit order the skill goes after the existing ones.
+func (r *Repository) Create(ctx context.Context, profileID string, skill *Request) (*Skill, error) {
+	order, err := r.orderOrNext(ctx, profileID, skill.Order)
+	if err != nil {
+		return nil, err
+	}
+
+	newSkill := &Skill{
+		ID:          uuid.New().String(),
+		ProfileID:   profileID,
+		Name:        skill.Name,
+		Category:    skill.Category,
+		Proficiency: skill.Proficiency,
+		Order:       order,
+	}
+
+	if err := r.store.InsertOne(ctx, newSkill); err != nil {
+		return nil, err
+	}
+	return newSkill, nil
+}
+
+// Update replaces the writable fields of a skill. The order is kept unless the request sets it.
+func (r *Repository) Update(ctx context.Context, profileID, id string, skill *Request) error {
+	update := map[string]interface{}{
+		"name":        skill.Name,
+		"category":    skill.Category,
+		"proficiency": skill.Proficiency,
+	}
+	if skill.Order != nil {
+		update["order"] = *skill.Order
+	}
+	return r.update(ctx, profileID, id, update)
+}
+
+func (r *Repository) Delete(ctx context.Context, profileID, id string) error {
+	err := r.store.DeleteOne(ctx, map[string]interface{}{"_id": id, "profileId": profileID})
+	if err != nil {
+		if types.IsNotFoundError(err) {
+			return types.ErrNotFound{Message: "skill not found"}
+		}
+		return err
+	}
+	return nil
+}
+
+// DeleteByProfileID deletes every skill of a profile
+func (r *Repository) DeleteByProfileID(ctx context.Context, profileID string) error {
+	_, err := r.store.DeleteMany(ctx, map[string]interface{}{"profileId": profileID})
+	return err
+}
+
+// Reorder assigns each skill its position in ids
+func (r *Repository) Reorder(ctx context.Context, profileID string, ids []string) error {
+	for position, id := range ids {
+		if err := r.update(ctx, profileID, id, map[string]interface{}{"order": position}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Repository) update(ctx context.Context, profileID, id string, update map[string]interface{}) error {
+	err := r.store.UpdateOne(ctx, map[string]interface{}{"_id": id, "profileId": profileID}, update)
+	if err != nil {
+		if types.IsNotFoundError(err) {
+			return types.ErrNotFound{Message: "skill not found"}
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *Repository) orderOrNext(ctx context.Context, profileID string, order *int) (int, error) {
+	if order != nil {
+		return *order, nil
+	}
+	count, err := r.store.CountRecords(ctx, map[string]interface{}{"profileId": profileID})
+	if err != nil {
+		return 0, err
+	}
+	return int(count), nil
+}
@@ -2,20 +2,36 @@ package skills
 
 import (
 	"context"
-	"errors"
+	"slices"
+	"strings"
 
+	"github.com/mrthoabby/portfolio-api/internal/access"
 	"github.com/mrthoabby/portfolio-api/internal/application/profile"
+	"github.com/mrthoabby/portfolio-api/internal/common"
+	"github.com/mrthoabby/portfolio-api/internal/common/contracts"
+	"github.com/mrthoabby/portfolio-api/internal/common/types"
 )
 
 type Service struct {
 	repo           *Repository
 	profileService *profile.Service
+	authorizer     *access.Authorizer
+	transactor     contracts.Transactor
+	categories     []string
 }
 
-func NewService(repo *Repository, profileService *profile.Service) *Service {
+// NewService creates the skills service. categories lists the accepted skill categories;
+// when empty, DefaultCategories is used.
+func NewService(repo *Repository, profileService *profile.Service, authorizer *access.Authorizer, transactor contracts.Transactor, categories []string) *Service {
+	if len(categories) == 0 {
+		categories = DefaultCategories
+	}
 	return &Service{
 		repo:           repo,
 		profileService: profileService,
+		authorizer:     authorizer,
+		transactor:     transactor,
+		categories:     categories,
 	}
 }
 
@@ -26,13 +42,99 @@ func (s *Service) GetByProfileID(ctx context.Context, profileID string) ([]Skill
 		return nil, err
 	}
 	if !exists {
-		return nil, errors.New("profile not found")
+		return nil, types.ErrNotFound{Message: "profile not found"}
+	}
+
+	return s.ListByProfileID(ctx, profileID)
+}
+
+// ListByProfileID returns the skills of a profile without checking that the profile exists.
+// Callers that already validated the profile use it to skip the extra lookup.
+func (s *Service) ListByProfileID(ctx context.Context, profileID string) ([]Skill, error) {
+	return s.repo.GetByProfileID(ctx, profileID)
+}
+
+// Categories returns the skill categories accepted by this deployment
+func (s *Service) Categories() []string {
+	return s.categories
+}
+
+func (s *Service) Create(ctx context.Context, profileID string, skill *Request) (*Skill, error) {
+	if err := s.authorize(ctx, profileID); err != nil {
+		return nil, err
 	}
+	if err := s.validateCategory(skill.Category); err != nil {
+		return nil, err
+	}
+	return s.repo.Create(ctx, profileID, skill)
+}
 
-	skills, err := s.repo.GetByProfileID(ctx, profileID)
+// Update replaces a skill and returns the stored result
+func (s *Service) Update(ctx context.Context, profileID, id string, skill *Request) (*Skill, error) {
+	if err := s.authorize(ctx, profileID); err != nil {
+		return nil, err
+	}
+	if err := s.validateCategory(skill.Category); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(ctx, profileID, id, skill); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(ctx, profileID, id)
+}
+
+func (s *Service) Delete(ctx context.Context, profileID, id string) error {
+	if err := s.authorize(ctx, profileID); err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, profileID, id)
+}
+
+// Reorder sets the display order of a profile's skills. ids must list every skill exactly once.
+// The skills are reordered in a single transaction, so a failure leaves the previous order intact.
+func (s *Service) Reorder(ctx context.Context, profileID string, ids []string) ([]Skill, error) {
+	if err := s.authorize(ctx, profileID); err != nil {
+		return nil, err
+	}
+
+	err := s.transactor.WithTransaction(ctx, func(ctx context.Context) error {
+		existing, err := s.repo.GetByProfileID(ctx, profileID)
+		if err != nil {
+			return err
+		}
+		existingIDs := make([]string, 0, len(existing))
+		for _, skill := range existing {
+			existingIDs = append(existingIDs, skill.ID)
+		}
+		if !common.IsPermutation(ids, existingIDs) {
+			return common.FieldErrors{{Field: "ids", Rule: "permutation", Message: "ids must list every skill of the profile exactly once"}}
+		}
+
+		return s.repo.Reorder(ctx, profileID, ids)
+	})
 	if err != nil {
 		return nil, err
 	}
-	return skills, nil
+	return s.repo.GetByProfileID(ctx, profileID)
 }
 
+func (s *Service) validateCategory(category string) error {
+	if slices.Contains(s.categories, category) {
+		return nil
+	}
+	allowed := strings.Join(s.categories, " ")
+	return common.FieldErrors{{Field: "category", Rule: "oneof", Param: allowed, Message: "category must be one of [" + allowed + "]"}}
+}
+
+func (s *Service) authorize(ctx context.Context, profileID string) error {
+	// Verify profile exists
+	exists, err := s.profileService.Exists(ctx, profileID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return types.ErrNotFound{Message: "profile not found"}
+	}
+
+	return s.authorizer.Authorize(ctx, profileID, access.ActionEdit)
+}
@@ -0,0 +1,23 @@
+package skills
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mrthoabby/portfolio-api/internal/common"
+)
+
+func TestNewService_DefaultCategories(t *testing.T) {
+	service := NewService(nil, nil, nil, nil, nil)
+	assert.Equal(t, DefaultCategories, service.Categories())
+}
+
+func TestService_ValidateCategory(t *testing.T) {
+	service := NewService(nil, nil, nil, nil, []string{"languages", "cloud"})
+
+	assert.NoError(t, service.validateCategory("cloud"))
+
+	err := service.validateCategory(CategoryBackend)
+	assert.Equal(t, common.FieldErrors{{Field: "category", Rule: "oneof", Param: "languages cloud", Message: "category must be one of [languages cloud]"}}, err)
+}
@@ -0,0 +1,185 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/mrthoabby/portfolio-api/internal/application/settings"
+	"github.com/mrthoabby/portfolio-api/internal/common/logger"
+	"github.com/mrthoabby/portfolio-api/internal/events"
+)
+
+// DefaultDisableAfter is the number of failed deliveries in a row after which a webhook is disabled
+const DefaultDisableAfter = 15
+
+// Dispatcher delivers events to the webhooks subscribed to them. It is meant to run behind the
+// outbox: a failed delivery fails the event so it is retried later, and the webhooks that already
+// received the event are skipped on retries.
+type Dispatcher struct {
+	repo         *Repository
+	sender       *Sender
+	settings     SettingsReader
+	disableAfter int
+	logger       logger.Logger
+}
+
+var _ events.Handler = (*Dispatcher)(nil)
+
+// SettingsReader looks up the settings of the profile an event belongs to
+type SettingsReader interface {
+	ForProfile(ctx context.Context, profileID string) (*settings.Settings, error)
+}
+
+// NewDispatcher creates a dispatcher disabling webhooks after disableAfter failed deliveries in a row.
+// Events of profiles whose settings turned webhooks off are dropped, not held until they are turned on again.
+func NewDispatcher(repo *Repository, sender *Sender, settings SettingsReader, disableAfter int, appLogger logger.Logger) *Dispatcher {
+	if disableAfter <= 0 {
+		disableAfter = DefaultDisableAfter
+	}
+	return &Dispatcher{
+		repo:         repo,
+		sender:       sender,
+		settings:     settings,
+		disableAfter: disableAfter,
+		logger:       appLogger,
+	}
+}
+
+// Handle implements events.Handler, delivering the event to its webhooks concurrently
+func (d *Dispatcher) Handle(ctx context.Context, event events.Event) error {
+	// Returning nil marks the event handled, so it is discarded while webhooks are off
+	profileSettings, err := d.settings.ForProfile(ctx, event.ProfileID)
+	if err != nil || !profileSettings.Notifications.Webhooks {
+		return err
+	}
+
+	active, err := d.repo.GetActive(ctx, event.ProfileID, event.Type)
+	if err != nil {
+		return err
+	}
+	// The settings may route the event to some of the subscribed webhooks only
+	var webhooks []Webhook
+	for _, webhook := range active {
+		if profileSettings.Notifications.DeliversTo(webhook.ID, event.Type) {
+			webhooks = append(webhooks, webhook)
+		}
+	}
+	if len(webhooks) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(Payload{
+		Version:    PayloadVersion,
+		ID:         event.ID,
+		Type:       event.Type,
+		ProfileID:  event.ProfileID,
+		OccurredAt: event.OccurredAt,
+		Data:       event.Data,
+	})
+	if err != nil {
+		return err
+	}
+
+	var syncMutex sync.Mutex
+	var failures []error
+	var wg sync.WaitGroup
+	for i := range webhooks {
+		wg.Add(1)
+		go func(webhook *Webhook) {
+			defer wg.Done()
+			if err := d.deliverOnce(ctx, webhook, event, body); err != nil {
+				syncMutex.Lock()
+				failures = append(failures, fmt.Errorf("webhook %s: %w", webhook.ID, err))
+				syncMutex.Unlock()
+			}
+		}(&webhooks[i])
+	}
+	wg.Wait()
+
+	return errors.Join(failures...)
+}
+
+// Redeliver sends the payload of a logged delivery again, signed anew. The outcome is logged as
+// a redelivery and does not count towards disabling the webhook.
+func (d *Dispatcher) Redeliver(ctx context.Context, webhook *Webhook, previous *Delivery) (*Delivery, error) {
+	return d.deliver(ctx, webhook, previous.EventID, previous.EventType, []byte(previous.Payload), true)
+}
+
+// deliverOnce delivers the event unless a previous attempt already succeeded
+func (d *Dispatcher) deliverOnce(ctx context.Context, webhook *Webhook, event events.Event, body []byte) error {
+	delivered, err := d.repo.Delivered(ctx, webhook.ID, event.ID)
+	if err != nil || delivered {
+		return err
+	}
+
+	delivery, err := d.deliver(ctx, webhook, event.ID, event.Type, body, false)
+	if err != nil {
+		return err
+	}
+
+	if delivery.Success {
+		if webhook.ConsecutiveFailures > 0 {
+			return d.repo.RecordSuccess(ctx, webhook.ID)
+		}
+		return nil
+	}
+	sendErr := errors.New(delivery.Error)
+
+	disabled, err := d.repo.RecordFailure(ctx, webhook.ID, d.disableAfter)
+	if err != nil {
+		return errors.Join(sendErr, err)
+	}
+	if disabled {
+		d.logger.Warn("Webhook disabled after repeated failed deliveries",
+			logger.String("webhook_id", webhook.ID),
+			logger.String("profile_id", webhook.ProfileID),
+			logger.Int("failures", d.disableAfter),
+		)
+	}
+	return sendErr
+}
+
+// deliver sends the payload and logs the attempt, whether the endpoint accepted it or not.
+// It only fails when the attempt cannot be logged.
+func (d *Dispatcher) deliver(ctx context.Context, webhook *Webhook, eventID, eventType string, body []byte, redelivery bool) (*Delivery, error) {
+	delivery := &Delivery{
+		ID:         uuid.New().String(),
+		WebhookID:  webhook.ID,
+		ProfileID:  webhook.ProfileID,
+		EventID:    eventID,
+		EventType:  eventType,
+		Redelivery: redelivery,
+		Payload:    string(body),
+		CreatedAt:  time.Now(),
+	}
+
+	outcome := d.sender.send(ctx, request{
+		URL:        webhook.URL,
+		Secret:     webhook.Secret,
+		EventType:  eventType,
+		EventID:    eventID,
+		DeliveryID: delivery.ID,
+		Body:       body,
+	})
+	delivery.Success = outcome.Err == nil
+	delivery.StatusCode = outcome.StatusCode
+	delivery.ResponseBody = outcome.ResponseBody
+	delivery.DurationMs = outcome.Duration.Milliseconds()
+	if outcome.Err != nil {
+		delivery.Error = outcome.Err.Error()
+	}
+
+	// Log the attempt even when the event handling was cancelled meanwhile
+	saveCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), DefaultTimeout)
+	defer cancel()
+	if err := d.repo.InsertDelivery(saveCtx, delivery); err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
@@ -0,0 +1,300 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrthoabby/portfolio-api/internal/application/settings"
+	"github.com/mrthoabby/portfolio-api/internal/common/contracts"
+	"github.com/mrthoabby/portfolio-api/internal/common/logger"
+	"github.com/mrthoabby/portfolio-api/internal/events"
+)
+
+// webhookStore keeps webhooks in memory, understanding the few filters the repository uses
+type webhookStore struct {
+	contracts.Store
+	mu       sync.Mutex
+	webhooks []Webhook
+}
+
+func (s *webhookStore) FindMany(_ context.Context, filter map[string]interface{}, _ []string, results interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var found []Webhook
+	for _, webhook := range s.webhooks {
+		if webhook.ProfileID == filter["profileId"] && webhook.Active && webhook.Subscribes(filter["events"].(string)) {
+			found = append(found, webhook)
+		}
+	}
+	*results.(*[]Webhook) = found
+	return nil
+}
+
+func (s *webhookStore) FindOneAndUpdate(_ context.Context, filter map[string]interface{}, update contracts.Update, _ contracts.FindOneAndUpdateOptions, result interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	webhook := s.find(filter["_id"].(string))
+	webhook.ConsecutiveFailures += update.Inc["consecutiveFailures"].(int)
+	*result.(*Webhook) = *webhook
+	return nil
+}
+
+func (s *webhookStore) UpdateOneWith(_ context.Context, filter map[string]interface{}, update contracts.Update) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	webhook := s.find(filter["_id"].(string))
+	if failures, ok := update.Set["consecutiveFailures"]; ok {
+		webhook.ConsecutiveFailures = failures.(int)
+	}
+	if active, ok := update.Set["active"]; ok {
+		webhook.Active = active.(bool)
+		webhook.DisabledReason, _ = update.Set["disabledReason"].(string)
+	}
+	return nil
+}
+
+func (s *webhookStore) find(id string) *Webhook {
+	for i := range s.webhooks {
+		if s.webhooks[i].ID == id {
+			return &s.webhooks[i]
+		}
+	}
+	return nil
+}
+
+// deliveryStore records the delivery log
+type deliveryStore struct {
+	contracts.Store
+	mu         sync.Mutex
+	deliveries []Delivery
+}
+
+func (s *deliveryStore) InsertOne(_ context.Context, record interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.deliveries = append(s.deliveries, *record.(*Delivery))
+	return nil
+}
+
+func (s *deliveryStore) CountRecords(_ context.Context, filter map[string]interface{}) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var count int64
+	for _, delivery := range s.deliveries {
+		if delivery.WebhookID == filter["webhookId"] && delivery.EventID == filter["eventId"] && delivery.Success && !delivery.Redelivery {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (s *deliveryStore) forWebhook(webhookID string) []Delivery {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var found []Delivery
+	for _, delivery := range s.deliveries {
+		if delivery.WebhookID == webhookID {
+			found = append(found, delivery)
+		}
+	}
+	return found
+}
+
+type nopLogger struct{}
+
+func (nopLogger) Debug(string, ...logger.Field)               {}
+func (nopLogger) Info(string, ...logger.Field)                {}
+func (nopLogger) Warn(string, ...logger.Field)                {}
+func (nopLogger) Error(string, ...logger.Field)               {}
+func (l nopLogger) With(...logger.Field) logger.Logger        { return l }
+func (l nopLogger) WithContext(context.Context) logger.Logger { return l }
+
+// endpoint is a webhook receiver answering with a configurable status
+type endpoint struct {
+	*httptest.Server
+	status atomic.Int32
+	calls  atomic.Int32
+	body   atomic.Value
+}
+
+func newEndpoint(t *testing.T, status int) *endpoint {
+	e := &endpoint{}
+	e.status.Store(int32(status))
+	e.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		e.body.Store(body)
+		e.calls.Add(1)
+		w.WriteHeader(int(e.status.Load()))
+	}))
+	t.Cleanup(e.Close)
+	return e
+}
+
+// fakeSettings returns the stored settings of a profile, or the defaults
+type fakeSettings map[string]*settings.Settings
+
+func (f fakeSettings) ForProfile(_ context.Context, profileID string) (*settings.Settings, error) {
+	if profileSettings, ok := f[profileID]; ok {
+		return profileSettings, nil
+	}
+	return settings.Defaults(profileID), nil
+}
+
+func newTestDispatcher(disableAfter int, webhooks ...Webhook) (*Dispatcher, *webhookStore, *deliveryStore) {
+	store := &webhookStore{webhooks: webhooks}
+	deliveries := &deliveryStore{}
+	repo := &Repository{store: store, deliveries: deliveries}
+	return NewDispatcher(repo, NewSender(time.Second, true), fakeSettings{}, disableAfter, nopLogger{}), store, deliveries
+}
+
+func newTestEvent(t *testing.T, eventType string) events.Event {
+	event, err := events.New(eventType, "profile-1", events.ContactCreated{ContactID: "contact-1", Name: "Ada"})
+	require.NoError(t, err)
+	return event
+}
+
+func TestDispatcher_Handle(t *testing.T) {
+	subscribed := newEndpoint(t, http.StatusOK)
+	other := newEndpoint(t, http.StatusOK)
+	dispatcher, _, deliveries := newTestDispatcher(0,
+		Webhook{ID: "hook-1", ProfileID: "profile-1", URL: subscribed.URL, Secret: "whsec_1", Events: []string{events.TypeContactCreated}, Active: true},
+		Webhook{ID: "hook-2", ProfileID: "profile-1", URL: other.URL, Secret: "whsec_2", Events: []string{events.TypeQuestionCreated}, Active: true},
+	)
+	event := newTestEvent(t, events.TypeContactCreated)
+
+	require.NoError(t, dispatcher.Handle(context.Background(), event))
+
+	assert.EqualValues(t, 1, subscribed.calls.Load())
+	assert.Zero(t, other.calls.Load(), "webhooks only receive the events they subscribe to")
+
+	var payload Payload
+	require.NoError(t, json.Unmarshal(subscribed.body.Load().([]byte), &payload))
+	assert.Equal(t, PayloadVersion, payload.Version)
+	assert.Equal(t, event.ID, payload.ID)
+	assert.Equal(t, events.TypeContactCreated, payload.Type)
+	assert.Equal(t, "profile-1", payload.ProfileID)
+	assert.JSONEq(t, string(event.Data), string(payload.Data))
+
+	logged := deliveries.forWebhook("hook-1")
+	require.Len(t, logged, 1)
+	assert.True(t, logged[0].Success)
+	assert.Equal(t, http.StatusOK, logged[0].StatusCode)
+	assert.Equal(t, event.ID, logged[0].EventID)
+	assert.False(t, logged[0].Redelivery)
+}
+
+func TestDispatcher_Handle_DiscardedWhileOff(t *testing.T) {
+	receiver := newEndpoint(t, http.StatusOK)
+	dispatcher, _, deliveries := newTestDispatcher(0,
+		Webhook{ID: "hook-1", ProfileID: "profile-1", URL: receiver.URL, Events: []string{events.TypeContactCreated}, Active: true},
+	)
+	off := settings.Defaults("profile-1")
+	off.Notifications.Webhooks = false
+	dispatcher.settings = fakeSettings{"profile-1": off}
+
+	require.NoError(t, dispatcher.Handle(context.Background(), newTestEvent(t, events.TypeContactCreated)))
+
+	assert.Zero(t, receiver.calls.Load())
+	assert.Empty(t, deliveries.forWebhook("hook-1"))
+
+	// Turning webhooks back on only delivers the events raised from then on
+	dispatcher.settings = fakeSettings{}
+	require.NoError(t, dispatcher.Handle(context.Background(), newTestEvent(t, events.TypeContactCreated)))
+	assert.EqualValues(t, 1, receiver.calls.Load())
+}
+
+func TestDispatcher_Handle_RoutedBySettings(t *testing.T) {
+	selected := newEndpoint(t, http.StatusOK)
+	other := newEndpoint(t, http.StatusOK)
+	dispatcher, _, _ := newTestDispatcher(0,
+		Webhook{ID: "hook-1", ProfileID: "profile-1", URL: selected.URL, Events: []string{events.TypeContactCreated, events.TypeQuestionCreated}, Active: true},
+		Webhook{ID: "hook-2", ProfileID: "profile-1", URL: other.URL, Events: []string{events.TypeContactCreated, events.TypeQuestionCreated}, Active: true},
+	)
+	routed := settings.Defaults("profile-1")
+	routed.Notifications.WebhookIDs = []string{"hook-1"}
+	routed.Notifications.WebhookEvents = []string{events.TypeContactCreated}
+	dispatcher.settings = fakeSettings{"profile-1": routed}
+
+	require.NoError(t, dispatcher.Handle(context.Background(), newTestEvent(t, events.TypeContactCreated)))
+	assert.EqualValues(t, 1, selected.calls.Load())
+	assert.Zero(t, other.calls.Load(), "only the selected webhooks receive events")
+
+	require.NoError(t, dispatcher.Handle(context.Background(), newTestEvent(t, events.TypeQuestionCreated)))
+	assert.EqualValues(t, 1, selected.calls.Load(), "only the selected event types are delivered")
+}
+
+func TestDispatcher_Handle_RetriesOnlyFailedWebhooks(t *testing.T) {
+	healthy := newEndpoint(t, http.StatusOK)
+	failing := newEndpoint(t, http.StatusServiceUnavailable)
+	dispatcher, store, deliveries := newTestDispatcher(0,
+		Webhook{ID: "hook-1", ProfileID: "profile-1", URL: healthy.URL, Events: []string{events.TypeContactCreated}, Active: true},
+		Webhook{ID: "hook-2", ProfileID: "profile-1", URL: failing.URL, Events: []string{events.TypeContactCreated}, Active: true},
+	)
+	event := newTestEvent(t, events.TypeContactCreated)
+
+	err := dispatcher.Handle(context.Background(), event)
+	assert.ErrorContains(t, err, "webhook hook-2: webhook responded with status 503")
+	assert.Equal(t, 1, store.find("hook-2").ConsecutiveFailures)
+
+	// The outbox retries the event: only the webhook that failed receives it again
+	failing.status.Store(http.StatusNoContent)
+	require.NoError(t, dispatcher.Handle(context.Background(), event))
+
+	assert.EqualValues(t, 1, healthy.calls.Load())
+	assert.EqualValues(t, 2, failing.calls.Load())
+	assert.Zero(t, store.find("hook-2").ConsecutiveFailures, "a success resets the failure count")
+
+	logged := deliveries.forWebhook("hook-2")
+	require.Len(t, logged, 2)
+	assert.False(t, logged[0].Success)
+	assert.Equal(t, "webhook responded with status 503", logged[0].Error)
+	assert.True(t, logged[1].Success)
+}
+
+func TestDispatcher_Handle_DisablesFailingWebhook(t *testing.T) {
+	failing := newEndpoint(t, http.StatusInternalServerError)
+	dispatcher, store, _ := newTestDispatcher(3,
+		Webhook{ID: "hook-1", ProfileID: "profile-1", URL: failing.URL, Events: []string{events.TypeContactCreated}, Active: true},
+	)
+
+	for range 3 {
+		assert.Error(t, dispatcher.Handle(context.Background(), newTestEvent(t, events.TypeContactCreated)))
+	}
+	webhook := store.find("hook-1")
+	assert.False(t, webhook.Active)
+	assert.Equal(t, disabledReasonFailures, webhook.DisabledReason)
+
+	// Disabled webhooks no longer receive events
+	assert.NoError(t, dispatcher.Handle(context.Background(), newTestEvent(t, events.TypeContactCreated)))
+	assert.EqualValues(t, 3, failing.calls.Load())
+}
+
+func TestDispatcher_Redeliver(t *testing.T) {
+	receiver := newEndpoint(t, http.StatusOK)
+	webhook := Webhook{ID: "hook-1", ProfileID: "profile-1", URL: receiver.URL, Events: []string{events.TypeContactCreated}, Active: true}
+	dispatcher, _, deliveries := newTestDispatcher(0, webhook)
+	event := newTestEvent(t, events.TypeContactCreated)
+	require.NoError(t, dispatcher.Handle(context.Background(), event))
+	previous := deliveries.forWebhook("hook-1")[0]
+
+	delivery, err := dispatcher.Redeliver(context.Background(), &webhook, &previous)
+	require.NoError(t, err)
+
+	assert.True(t, delivery.Success)
+	assert.True(t, delivery.Redelivery)
+	assert.NotEqual(t, previous.ID, delivery.ID)
+	assert.Equal(t, previous.Payload, delivery.Payload)
+	assert.Equal(t, previous.Payload, string(receiver.body.Load().([]byte)))
+	assert.True(t, slices.ContainsFunc(deliveries.forWebhook("hook-1"), func(d Delivery) bool { return d.ID == delivery.ID }))
+}
@@ -0,0 +1,203 @@
+package webhooks
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/mrthoabby/portfolio-api/internal/common"
+)
+
+type Handler struct {
+	service   *Service
+	validator *common.Validator
+}
+
+func NewHandler(service *Service) *Handler {
+	return &Handler{
+		service:   service,
+		validator: common.NewValidator(),
+	}
+}
+
+// GetByProfileID handles GET /admin/profiles/{id}/webhooks
+func (h *Handler) GetByProfileID(w http.ResponseWriter, r *http.Request) {
+	profileID := r.PathValue("id")
+	if !common.IsValidUUID(profileID) {
+		common.RespondError(w, r, http.StatusBadRequest, "BAD_REQUEST", "Invalid profile ID format", nil)
+		return
+	}
+
+	webhooks, err := h.service.GetByProfileID(r.Context(), profileID)
+	if err != nil {
+		common.RespondServiceError(w, r, err)
+		return
+	}
+
+	response := Response{Webhooks: webhooks}
+	if response.Webhooks == nil {
+		response.Webhooks = []Webhook{}
+	}
+	common.RespondJSON(w, http.StatusOK, response)
+}
+
+// Create handles POST /admin/profiles/{id}/webhooks
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	profileID := r.PathValue("id")
+	if !common.IsValidUUID(profileID) {
+		common.RespondError(w, r, http.StatusBadRequest, "BAD_REQUEST", "Invalid profile ID format", nil)
+		return
+	}
+
+	var webhookReq Request
+	if !h.decodeAndValidate(w, r, &webhookReq) {
+		return
+	}
+
+	webhook, err := h.service.Create(r.Context(), profileID, &webhookReq)
+	if err != nil {
+		common.RespondServiceError(w, r, err)
+		return
+	}
+
+	common.RespondJSON(w, http.StatusCreated, webhook)
+}
+
+// GetByID handles GET /admin/profiles/{id}/webhooks/{webhookId}
+func (h *Handler) GetByID(w http.ResponseWriter, r *http.Request) {
+	profileID, webhookID, ok := webhookIDsFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	webhook, err := h.service.GetByID(r.Context(), profileID, webhookID)
+	if err != nil {
+		common.RespondServiceError(w, r, err)
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, webhook)
+}
+
+// Update handles PUT /admin/profiles/{id}/webhooks/{webhookId}
+func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
+	profileID, webhookID, ok := webhookIDsFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	var webhookReq Request
+	if !h.decodeAndValidate(w, r, &webhookReq) {
+		return
+	}
+
+	webhook, err := h.service.Update(r.Context(), profileID, webhookID, &webhookReq)
+	if err != nil {
+		common.RespondServiceError(w, r, err)
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, webhook)
+}
+
+// Delete handles DELETE /admin/profiles/{id}/webhooks/{webhookId}
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
+	profileID, webhookID, ok := webhookIDsFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(r.Context(), profileID, webhookID); err != nil {
+		common.RespondServiceError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetDeliveries handles GET /admin/profiles/{id}/webhooks/{webhookId}/deliveries.
+// Supported query parameters: cursor and limit.
+func (h *Handler) GetDeliveries(w http.ResponseWriter, r *http.Request) {
+	profileID, webhookID, ok := webhookIDsFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	query := r.URL.Query()
+	limit, err := common.ParsePageLimit(query)
+	if err != nil {
+		common.RespondError(w, r, http.StatusBadRequest, "BAD_REQUEST", err.Error(), nil)
+		return
+	}
+	var cursor *common.Cursor
+	if value := query.Get("cursor"); value != "" {
+		decoded, err := common.DecodeCursor(value)
+		if err != nil {
+			common.RespondError(w, r, http.StatusBadRequest, "BAD_REQUEST", err.Error(), nil)
+			return
+		}
+		cursor = &decoded
+	}
+
+	deliveries, hasMore, err := h.service.GetDeliveries(r.Context(), profileID, webhookID, limit, cursor)
+	if err != nil {
+		common.RespondServiceError(w, r, err)
+		return
+	}
+
+	response := DeliveryPageResponse{Deliveries: deliveries}
+	if response.Deliveries == nil {
+		response.Deliveries = []Delivery{}
+	}
+	if hasMore {
+		last := deliveries[len(deliveries)-1]
+		response.NextCursor = common.Cursor{CreatedAt: last.CreatedAt, ID: last.ID}.Encode()
+	}
+
+	common.RespondJSON(w, http.StatusOK, response)
+}
+
+// Redeliver handles POST /admin/profiles/{id}/webhooks/{webhookId}/deliveries/{deliveryId}/redeliver.
+// The delivery is sent before responding, and the response is the new delivery.
+func (h *Handler) Redeliver(w http.ResponseWriter, r *http.Request) {
+	profileID, webhookID, ok := webhookIDsFromPath(w, r)
+	if !ok {
+		return
+	}
+	deliveryID := r.PathValue("deliveryId")
+	if !common.IsValidUUID(deliveryID) {
+		common.RespondError(w, r, http.StatusBadRequest, "BAD_REQUEST", "Invalid delivery ID format", nil)
+		return
+	}
+
+	delivery, err := h.service.Redeliver(r.Context(), profileID, webhookID, deliveryID)
+	if err != nil {
+		common.RespondServiceError(w, r, err)
+		return
+	}
+
+	common.RespondJSON(w, http.StatusOK, delivery)
+}
+
+func (h *Handler) decodeAndValidate(w http.ResponseWriter, r *http.Request, webhookReq *Request) bool {
+	if err := common.DecodeJSON(r, webhookReq); err != nil {
+		common.RespondServiceError(w, r, err)
+		return false
+	}
+	webhookReq.URL = strings.TrimSpace(webhookReq.URL)
+
+	if err := h.validator.Validate(r, webhookReq); err != nil {
+		common.RespondServiceError(w, r, err)
+		return false
+	}
+	return true
+}
+
+func webhookIDsFromPath(w http.ResponseWriter, r *http.Request) (string, string, bool) {
+	profileID := r.PathValue("id")
+	webhookID := r.PathValue("webhookId")
+	if !common.IsValidUUID(profileID) || !common.IsValidUUID(webhookID) {
+		common.RespondError(w, r, http.StatusBadRequest, "BAD_REQUEST", "Invalid profile or webhook ID format", nil)
+		return "", "", false
+	}
+	return profileID, webhookID, true
+}
@@ -0,0 +1,115 @@
+package webhooks
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testProfileID = "550e8400-e29b-41d4-a716-446655440000"
+
+func TestHandler_InvalidIDs(t *testing.T) {
+	handler := NewHandler(&Service{})
+
+	tests := []struct {
+		name       string
+		handle     http.HandlerFunc
+		webhookID  string
+		deliveryID string
+	}{
+		{"get", handler.GetByID, "not-a-uuid", ""},
+		{"update", handler.Update, "not-a-uuid", ""},
+		{"delete", handler.Delete, "not-a-uuid", ""},
+		{"deliveries", handler.GetDeliveries, "not-a-uuid", ""},
+		{"redeliver webhook", handler.Redeliver, "not-a-uuid", testProfileID},
+		{"redeliver delivery", handler.Redeliver, testProfileID, "not-a-uuid"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/api/v1/admin/profiles/"+testProfileID+"/webhooks/x", nil)
+			req.SetPathValue("id", testProfileID)
+			req.SetPathValue("webhookId", tt.webhookID)
+			req.SetPathValue("deliveryId", tt.deliveryID)
+			w := httptest.NewRecorder()
+
+			tt.handle(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+}
+
+func TestHandler_Create_InvalidRequest(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"missing url", `{"events": ["contact.created"]}`},
+		{"invalid url", `{"url": "not a url", "events": ["contact.created"]}`},
+		{"no events", `{"url": "https://example.com/hook", "events": []}`},
+		{"unknown event", `{"url": "https://example.com/hook", "events": ["profile.deleted"]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewHandler(&Service{})
+
+			req := httptest.NewRequest("POST", "/api/v1/admin/profiles/"+testProfileID+"/webhooks", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			req.SetPathValue("id", testProfileID)
+			w := httptest.NewRecorder()
+
+			handler.Create(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Contains(t, w.Body.String(), "VALIDATION_ERROR")
+		})
+	}
+}
+
+func TestHandler_GetDeliveries_InvalidQuery(t *testing.T) {
+	handler := NewHandler(&Service{})
+
+	for _, query := range []string{"limit=0", "cursor=%25%25"} {
+		t.Run(query, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/api/v1/admin/profiles/"+testProfileID+"/webhooks/"+testProfileID+"/deliveries?"+query, nil)
+			req.SetPathValue("id", testProfileID)
+			req.SetPathValue("webhookId", testProfileID)
+			w := httptest.NewRecorder()
+
+			handler.GetDeliveries(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+}
+
+func TestService_ValidateURL(t *testing.T) {
+	tests := []struct {
+		url           string
+		allowInsecure bool
+		valid         bool
+	}{
+		{"https://example.com/hook", false, true},
+		{"http://example.com/hook", false, false},
+		{"http://localhost:8080/hook", true, true},
+		{"ftp://example.com/hook", true, false},
+		{"https:///hook", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			service := &Service{allowInsecure: tt.allowInsecure}
+			err := service.validateURL(tt.url)
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// PayloadVersion is the version of the delivered JSON document, raised on incompatible changes
+const PayloadVersion = 1
+
+// Delivery headers
+const (
+	// HeaderSignature carries "t=<unix seconds>,v1=<hex HMAC-SHA256 of "<t>.<body>">"
+	HeaderSignature = "X-Webhook-Signature"
+	// HeaderEvent carries the event type
+	HeaderEvent = "X-Webhook-Event"
+	// HeaderEventID identifies the event; retries and redeliveries of an event repeat it
+	HeaderEventID = "X-Webhook-Event-Id"
+	// HeaderDelivery identifies the delivery attempt
+	HeaderDelivery = "X-Webhook-Delivery"
+)
+
+// Webhook is a URL notified about the events of a profile
+type Webhook struct {
+	ID        string   `json:"id" bson:"_id"`
+	ProfileID string   `json:"profileId" bson:"profileId"`
+	URL       string   `json:"url" bson:"url"`
+	Events    []string `json:"events" bson:"events"`
+	// Secret signs the deliveries; it is only returned when the webhook is created
+	Secret string `json:"-" bson:"secret"`
+	Active bool   `json:"active" bson:"active"`
+	// ConsecutiveFailures counts the failed deliveries since the last successful one
+	ConsecutiveFailures int        `json:"consecutiveFailures" bson:"consecutiveFailures"`
+	DisabledAt          *time.Time `json:"disabledAt,omitempty" bson:"disabledAt,omitempty"`
+	DisabledReason      string     `json:"disabledReason,omitempty" bson:"disabledReason,omitempty"`
+	CreatedAt           time.Time  `json:"createdAt" bson:"createdAt"`
+	UpdatedAt           time.Time  `json:"updatedAt" bson:"updatedAt"`
+}
+
+// Subscribes reports whether the webhook is notified about the event type
+func (w *Webhook) Subscribes(eventType string) bool {
+	for _, subscribed := range w.Events {
+		if subscribed == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Request is the payload for creating or replacing a webhook.
+// Setting active on a disabled webhook enables it again.
+type Request struct {
+	URL    string   `json:"url" validate:"required,url,max=2048"`
+	Events []string `json:"events" validate:"required,min=1,dive,oneof=contact.created question.created project.published"`
+	Active *bool    `json:"active,omitempty"`
+}
+
+// CreatedResponse is a new webhook with its signing secret, which is not shown again
+type CreatedResponse struct {
+	Webhook
+	Secret string `json:"secret"`
+}
+
+type Response struct {
+	Webhooks []Webhook `json:"webhooks"`
+}
+
+// Delivery logs an attempt to deliver an event to a webhook
+type Delivery struct {
+	ID        string `json:"id" bson:"_id"`
+	WebhookID string `json:"webhookId" bson:"webhookId"`
+	ProfileID string `json:"profileId" bson:"profileId"`
+	EventID   string `json:"eventId" bson:"eventId"`
+	EventType string `json:"eventType" bson:"eventType"`
+	// Redelivery marks deliveries requested through the API rather than made by the dispatcher
+	Redelivery bool `json:"redelivery" bson:"redelivery"`
+	Success    bool `json:"success" bson:"success"`
+	// StatusCode is the response status, zero when no response was received
+	StatusCode int    `json:"statusCode,omitempty" bson:"statusCode,omitempty"`
+	Error      string `json:"error,omitempty" bson:"error,omitempty"`
+	// ResponseBody holds the start of the response body
+	ResponseBody string    `json:"responseBody,omitempty" bson:"responseBody,omitempty"`
+	DurationMs   int64     `json:"durationMs" bson:"durationMs"`
+	Payload      string    `json:"payload" bson:"payload"`
+	CreatedAt    time.Time `json:"createdAt" bson:"createdAt"`
+}
+
+// DeliveryPageResponse is one page of the delivery log, newest first
+type DeliveryPageResponse struct {
+	Deliveries []Delivery `json:"deliveries"`
+	NextCursor string     `json:"nextCursor,omitempty"`
+}
+
+// Payload is the JSON document delivered for an event
+type Payload struct {
+	Version    int             `json:"version"`
+	ID         string          `json:"id"`
+	Type       string          `json:"type"`
+	ProfileID  string          `json:"profileId"`
+	OccurredAt time.Time       `json:"occurredAt"`
+	Data       json.RawMessage `json:"data"`
+}
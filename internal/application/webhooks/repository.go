@@ -0,0 +1,220 @@
+package webhooks
+
+import (
+	"context"
+	"time"
+
+	"github.com/mrthoabby/portfolio-api/internal/common"
+	"github.com/mrthoabby/portfolio-api/internal/common/contracts"
+	"github.com/mrthoabby/portfolio-api/internal/common/types"
+)
+
+// deliveryRetention is how long delivery logs are kept
+const deliveryRetention = 30 * 24 * time.Hour
+
+// disabledReasonFailures explains why a webhook was disabled by the dispatcher
+const disabledReasonFailures = "too many consecutive failed deliveries"
+
+type Repository struct {
+	store      contracts.Store
+	deliveries contracts.Store
+}
+
+func NewRepository(dataSource contracts.DataSource) *Repository {
+	return &Repository{
+		store:      dataSource.Store("webhooks"),
+		deliveries: dataSource.Store("webhook_deliveries"),
+	}
+}
+
+// EnsureIndexes creates the indexes listing delivery logs and expiring old ones
+func (r *Repository) EnsureIndexes(ctx context.Context) error {
+	if err := r.deliveries.EnsureIndex(ctx, contracts.Index{Fields: []string{"webhookId", "-createdAt", "-_id"}}); err != nil {
+		return err
+	}
+	if err := r.deliveries.EnsureIndex(ctx, contracts.Index{Fields: []string{"webhookId", "eventId"}}); err != nil {
+		return err
+	}
+	retention := deliveryRetention
+	return r.deliveries.EnsureIndex(ctx, contracts.Index{Fields: []string{"createdAt"}, ExpireAfter: &retention})
+}
+
+func (r *Repository) Create(ctx context.Context, webhook *Webhook) error {
+	return r.store.InsertOne(ctx, webhook)
+}
+
+func (r *Repository) GetByProfileID(ctx context.Context, profileID string) ([]Webhook, error) {
+	var webhooks []Webhook
+	err := r.store.FindMany(ctx, map[string]interface{}{"profileId": profileID}, []string{"createdAt"}, &webhooks)
+	if err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+// GetActive returns the enabled webhooks of a profile subscribed to the event type
+func (r *Repository) GetActive(ctx context.Context, profileID, eventType string) ([]Webhook, error) {
+	var webhooks []Webhook
+	filter := map[string]interface{}{
+		"profileId": profileID,
+		"active":    true,
+		"events":    eventType,
+	}
+	if err := r.store.FindMany(ctx, filter, []string{"createdAt"}, &webhooks); err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+func (r *Repository) GetByID(ctx context.Context, profileID, id string) (*Webhook, error) {
+	var webhook Webhook
+	err := r.store.FindOne(ctx, map[string]interface{}{"_id": id, "profileId": profileID}, &webhook)
+	if err != nil {
+		if types.IsNotFoundError(err) {
+			return nil, types.ErrNotFound{Message: "webhook not found"}
+		}
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+// Update replaces the URL and events of a webhook. Enabling it clears the failures that disabled it.
+func (r *Repository) Update(ctx context.Context, profileID, id string, request *Request) error {
+	update := contracts.Update{
+		Set: map[string]interface{}{
+			"url":       request.URL,
+			"events":    request.Events,
+			"updatedAt": time.Now(),
+		},
+	}
+	if request.Active != nil {
+		update.Set["active"] = *request.Active
+		if *request.Active {
+			update.Set["consecutiveFailures"] = 0
+			update.Unset = []string{"disabledAt", "disabledReason"}
+		}
+	}
+
+	err := r.store.UpdateOneWith(ctx, map[string]interface{}{"_id": id, "profileId": profileID}, update)
+	if err != nil {
+		if types.IsNotFoundError(err) {
+			return types.ErrNotFound{Message: "webhook not found"}
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *Repository) Delete(ctx context.Context, profileID, id string) error {
+	err := r.store.DeleteOne(ctx, map[string]interface{}{"_id": id, "profileId": profileID})
+	if err != nil {
+		if types.IsNotFoundError(err) {
+			return types.ErrNotFound{Message: "webhook not found"}
+		}
+		return err
+	}
+	return nil
+}
+
+// DeleteByProfileID deletes every webhook of a profile along with their delivery logs
+func (r *Repository) DeleteByProfileID(ctx context.Context, profileID string) error {
+	filter := map[string]interface{}{"profileId": profileID}
+	if _, err := r.deliveries.DeleteMany(ctx, filter); err != nil {
+		return err
+	}
+	_, err := r.store.DeleteMany(ctx, filter)
+	return err
+}
+
+// RecordSuccess clears the failure count of a webhook
+func (r *Repository) RecordSuccess(ctx context.Context, id string) error {
+	return r.store.UpdateOneWith(ctx, map[string]interface{}{"_id": id}, contracts.Update{
+		Set: map[string]interface{}{"consecutiveFailures": 0},
+	})
+}
+
+// RecordFailure counts a failed delivery and disables the webhook once disableAfter
+// deliveries in a row failed. It reports whether the webhook was disabled.
+func (r *Repository) RecordFailure(ctx context.Context, id string, disableAfter int) (bool, error) {
+	var webhook Webhook
+	err := r.store.FindOneAndUpdate(ctx,
+		map[string]interface{}{"_id": id},
+		contracts.Update{Inc: map[string]interface{}{"consecutiveFailures": 1}},
+		contracts.FindOneAndUpdateOptions{},
+		&webhook,
+	)
+	if err != nil {
+		return false, err
+	}
+	if !webhook.Active || webhook.ConsecutiveFailures < disableAfter {
+		return false, nil
+	}
+
+	now := time.Now()
+	err = r.store.UpdateOneWith(ctx, map[string]interface{}{"_id": id, "active": true}, contracts.Update{
+		Set: map[string]interface{}{
+			"active":         false,
+			"disabledAt":     now,
+			"disabledReason": disabledReasonFailures,
+			"updatedAt":      now,
+		},
+	})
+	if err != nil {
+		if types.IsNotFoundError(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *Repository) InsertDelivery(ctx context.Context, delivery *Delivery) error {
+	return r.deliveries.InsertOne(ctx, delivery)
+}
+
+// Delivered reports whether the dispatcher already delivered the event to the webhook
+func (r *Repository) Delivered(ctx context.Context, webhookID, eventID string) (bool, error) {
+	count, err := r.deliveries.CountRecords(ctx, map[string]interface{}{
+		"webhookId":  webhookID,
+		"eventId":    eventID,
+		"success":    true,
+		"redelivery": false,
+	})
+	return count > 0, err
+}
+
+// GetDeliveries returns one page of the delivery log of a webhook, newest first.
+// It fetches one extra record to know whether another page follows.
+func (r *Repository) GetDeliveries(ctx context.Context, webhookID string, limit int, cursor *common.Cursor) ([]Delivery, bool, error) {
+	filter := map[string]interface{}{"webhookId": webhookID}
+	if cursor != nil {
+		filter["$or"] = common.AfterCursorFilter(*cursor)
+	}
+
+	var deliveries []Delivery
+	opts := contracts.FindOptions{
+		Sort:  []string{"-createdAt", "-_id"},
+		Limit: int64(limit) + 1,
+	}
+	if err := r.deliveries.FindManyWithOptions(ctx, filter, opts, &deliveries); err != nil {
+		return nil, false, err
+	}
+
+	hasMore := len(deliveries) > limit
+	if hasMore {
+		deliveries = deliveries[:limit]
+	}
+	return deliveries, hasMore, nil
+}
+
+func (r *Repository) GetDelivery(ctx context.Context, webhookID, id string) (*Delivery, error) {
+	var delivery Delivery
+	err := r.deliveries.FindOne(ctx, map[string]interface{}{"_id": id, "webhookId": webhookID}, &delivery)
+	if err != nil {
+		if types.IsNotFoundError(err) {
+			return nil, types.ErrNotFound{Message: "delivery not found"}
+		}
+		return nil, err
+	}
+	return &delivery, nil
+}
@@ -0,0 +1,192 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// Sender defaults
+const (
+	DefaultTimeout = 10 * time.Second
+	// responseBodyLimit caps the part of the response body kept in the delivery log
+	responseBodyLimit = 1024
+	userAgent         = "portfolio-api-webhooks/1"
+)
+
+var (
+	// ErrInvalidSignature is returned when a signature header does not match the body
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+
+	// ErrSignatureExpired is returned when a signature is older than the accepted tolerance
+	ErrSignatureExpired = errors.New("webhook signature expired")
+
+	// errPrivateTarget is returned when a webhook resolves to an address that is not publicly routable
+	errPrivateTarget = errors.New("webhook target is not a public address")
+)
+
+// Sign returns the signature header value for body sent at timestamp
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	unix := strconv.FormatInt(timestamp.Unix(), 10)
+	return "t=" + unix + ",v1=" + signature(secret, unix, body)
+}
+
+// VerifySignature checks a signature header produced by Sign. Receivers should reject signatures
+// older than a few minutes and ignore event IDs they already handled, so replays are harmless.
+func VerifySignature(secret, header string, body []byte, tolerance time.Duration, now time.Time) error {
+	var unix string
+	var signatures []string
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			unix = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+
+	seconds, err := strconv.ParseInt(unix, 10, 64)
+	if err != nil || len(signatures) == 0 {
+		return ErrInvalidSignature
+	}
+
+	expected := signature(secret, unix, body)
+	for _, candidate := range signatures {
+		if hmac.Equal([]byte(candidate), []byte(expected)) {
+			if age := now.Sub(time.Unix(seconds, 0)); age > tolerance || age < -tolerance {
+				return ErrSignatureExpired
+			}
+			return nil
+		}
+	}
+	return ErrInvalidSignature
+}
+
+func signature(secret, unix string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(unix))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// request is one signed delivery
+type request struct {
+	URL        string
+	Secret     string
+	EventType  string
+	EventID    string
+	DeliveryID string
+	Body       []byte
+}
+
+// result is the outcome of a delivery. Err is set unless the endpoint answered with a 2xx status
+type result struct {
+	StatusCode   int
+	ResponseBody string
+	Duration     time.Duration
+	Err          error
+}
+
+// Sender posts signed payloads to webhook URLs
+type Sender struct {
+	client *http.Client
+	now    func() time.Time
+}
+
+// NewSender creates a sender giving up on endpoints after timeout. Unless allowPrivate is set,
+// only publicly routable addresses are reached, so webhooks cannot probe the internal network.
+func NewSender(timeout time.Duration, allowPrivate bool) *Sender {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	dialer := &net.Dialer{Timeout: timeout}
+	if !allowPrivate {
+		dialer.Control = func(_, address string, _ syscall.RawConn) error {
+			return checkPublicAddress(address)
+		}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dialer.DialContext
+	transport.Proxy = nil
+
+	return &Sender{
+		client: &http.Client{
+			Transport: transport,
+			Timeout:   timeout,
+			// A redirect is reported as a failure rather than followed to a URL nobody registered
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		now: time.Now,
+	}
+}
+
+// send posts the request, signed at the current time
+func (s *Sender) send(ctx context.Context, req request) result {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, req.URL, bytes.NewReader(req.Body))
+	if err != nil {
+		return result{Err: err}
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("User-Agent", userAgent)
+	httpReq.Header.Set(HeaderSignature, Sign(req.Secret, s.now(), req.Body))
+	httpReq.Header.Set(HeaderEvent, req.EventType)
+	httpReq.Header.Set(HeaderEventID, req.EventID)
+	httpReq.Header.Set(HeaderDelivery, req.DeliveryID)
+
+	start := time.Now()
+	resp, err := s.client.Do(httpReq)
+	if err != nil {
+		return result{Duration: time.Since(start), Err: err}
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, responseBodyLimit))
+	outcome := result{
+		StatusCode:   resp.StatusCode,
+		ResponseBody: string(body),
+		Duration:     time.Since(start),
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		outcome.Err = fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return outcome
+}
+
+// checkPublicAddress rejects dialing loopback, private, link-local and other non-public addresses
+func checkPublicAddress(address string) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+	ip = ip.Unmap()
+
+	if !ip.IsGlobalUnicast() || ip.IsPrivate() || sharedAddressSpace.Contains(ip) {
+		return errPrivateTarget
+	}
+	return nil
+}
+
+// sharedAddressSpace is the carrier-grade NAT range, which IsPrivate does not cover
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
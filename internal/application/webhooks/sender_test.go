@@ -0,0 +1,138 @@
+package webhooks
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignature(t *testing.T) {
+	body := []byte(`{"version":1}`)
+	sentAt := time.Unix(1700000000, 0)
+	header := Sign("whsec_test", sentAt, body)
+
+	assert.Regexp(t, `^t=1700000000,v1=[0-9a-f]{64}$`, header)
+	assert.NoError(t, VerifySignature("whsec_test", header, body, 5*time.Minute, sentAt.Add(time.Minute)))
+
+	tests := []struct {
+		name     string
+		secret   string
+		header   string
+		body     []byte
+		now      time.Time
+		expected error
+	}{
+		{"tampered body", "whsec_test", header, []byte(`{"version":2}`), sentAt, ErrInvalidSignature},
+		{"other secret", "whsec_other", header, body, sentAt, ErrInvalidSignature},
+		{"replayed later", "whsec_test", header, body, sentAt.Add(10 * time.Minute), ErrSignatureExpired},
+		{"missing timestamp", "whsec_test", header[len("t=1700000000,"):], body, sentAt, ErrInvalidSignature},
+		{"empty", "whsec_test", "", body, sentAt, ErrInvalidSignature},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, VerifySignature(tt.secret, tt.header, tt.body, 5*time.Minute, tt.now), tt.expected)
+		})
+	}
+
+	// Receivers accept any listed signature, which lets the scheme evolve
+	rotated := "t=1700000000,v1=deadbeef," + header[len("t=1700000000,"):]
+	assert.NoError(t, VerifySignature("whsec_test", rotated, body, 5*time.Minute, sentAt))
+}
+
+func TestSender_Send(t *testing.T) {
+	var received *http.Request
+	var receivedBody []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		receivedBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte("thanks"))
+	}))
+	defer server.Close()
+
+	sender := NewSender(time.Second, true)
+	body := []byte(`{"version":1,"type":"contact.created"}`)
+	outcome := sender.send(context.Background(), request{
+		URL:        server.URL,
+		Secret:     "whsec_test",
+		EventType:  "contact.created",
+		EventID:    "event-1",
+		DeliveryID: "delivery-1",
+		Body:       body,
+	})
+
+	require.NoError(t, outcome.Err)
+	assert.Equal(t, http.StatusAccepted, outcome.StatusCode)
+	assert.Equal(t, "thanks", outcome.ResponseBody)
+
+	assert.Equal(t, body, receivedBody)
+	assert.Equal(t, "application/json", received.Header.Get("Content-Type"))
+	assert.Equal(t, "contact.created", received.Header.Get(HeaderEvent))
+	assert.Equal(t, "event-1", received.Header.Get(HeaderEventID))
+	assert.Equal(t, "delivery-1", received.Header.Get(HeaderDelivery))
+	assert.NoError(t, VerifySignature("whsec_test", received.Header.Get(HeaderSignature), receivedBody, time.Minute, time.Now()))
+}
+
+func TestSender_Send_Failures(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/redirect":
+			http.Redirect(w, r, "/ok", http.StatusFound)
+		case "/ok":
+			w.WriteHeader(http.StatusOK)
+		default:
+			http.Error(w, "boom", http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	sender := NewSender(time.Second, true)
+
+	outcome := sender.send(context.Background(), request{URL: server.URL + "/error"})
+	assert.EqualError(t, outcome.Err, "webhook responded with status 500")
+	assert.Equal(t, http.StatusInternalServerError, outcome.StatusCode)
+
+	outcome = sender.send(context.Background(), request{URL: server.URL + "/redirect"})
+	assert.Error(t, outcome.Err, "redirects are not followed")
+	assert.Equal(t, http.StatusFound, outcome.StatusCode)
+
+	// The test server listens on loopback, which is refused unless private targets are allowed
+	outcome = NewSender(time.Second, false).send(context.Background(), request{URL: server.URL + "/ok"})
+	assert.ErrorIs(t, outcome.Err, errPrivateTarget)
+	assert.Zero(t, outcome.StatusCode)
+}
+
+func TestCheckPublicAddress(t *testing.T) {
+	tests := []struct {
+		address string
+		public  bool
+	}{
+		{"93.184.216.34:443", true},
+		{"[2606:2800:220:1:248:1893:25c8:1946]:443", true},
+		{"127.0.0.1:80", false},
+		{"10.0.0.5:443", false},
+		{"192.168.1.1:443", false},
+		{"169.254.169.254:80", false},
+		{"100.64.0.1:443", false},
+		{"0.0.0.0:80", false},
+		{"[::1]:80", false},
+		{"[fd00::1]:443", false},
+		{"[::ffff:127.0.0.1]:80", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.address, func(t *testing.T) {
+			err := checkPublicAddress(tt.address)
+			if tt.public {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, errPrivateTarget)
+			}
+		})
+	}
+}
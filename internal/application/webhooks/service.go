@@ -0,0 +1,173 @@
+package webhooks
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/mrthoabby/portfolio-api/internal/access"
+	"github.com/mrthoabby/portfolio-api/internal/application/profile"
+	"github.com/mrthoabby/portfolio-api/internal/common"
+	"github.com/mrthoabby/portfolio-api/internal/common/types"
+)
+
+const (
+	// maxWebhooksPerProfile bounds the fan-out of every event
+	maxWebhooksPerProfile = 10
+	secretPrefix          = "whsec_"
+	secretBytes           = 32
+)
+
+// ErrTooManyWebhooks is returned when a profile already has the maximum number of webhooks
+var ErrTooManyWebhooks = types.ErrValidation{Message: "a profile can have at most 10 webhooks"}
+
+type Service struct {
+	repo           *Repository
+	profileService *profile.Service
+	authorizer     *access.Authorizer
+	dispatcher     *Dispatcher
+	allowInsecure  bool
+}
+
+// NewService creates the webhook service. Unless allowInsecure is set, webhook URLs must use https.
+func NewService(repo *Repository, profileService *profile.Service, authorizer *access.Authorizer, dispatcher *Dispatcher, allowInsecure bool) *Service {
+	return &Service{
+		repo:           repo,
+		profileService: profileService,
+		authorizer:     authorizer,
+		dispatcher:     dispatcher,
+		allowInsecure:  allowInsecure,
+	}
+}
+
+func (s *Service) GetByProfileID(ctx context.Context, profileID string) ([]Webhook, error) {
+	if err := s.authorize(ctx, profileID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByProfileID(ctx, profileID)
+}
+
+func (s *Service) GetByID(ctx context.Context, profileID, id string) (*Webhook, error) {
+	if err := s.authorize(ctx, profileID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(ctx, profileID, id)
+}
+
+// Create registers a webhook with a new signing secret, returned only this once
+func (s *Service) Create(ctx context.Context, profileID string, request *Request) (*CreatedResponse, error) {
+	if err := s.authorize(ctx, profileID); err != nil {
+		return nil, err
+	}
+	if err := s.validateURL(request.URL); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repo.GetByProfileID(ctx, profileID)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) >= maxWebhooksPerProfile {
+		return nil, ErrTooManyWebhooks
+	}
+
+	secret, err := generateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	webhook := Webhook{
+		ID:        uuid.New().String(),
+		ProfileID: profileID,
+		URL:       request.URL,
+		Events:    request.Events,
+		Secret:    secret,
+		Active:    request.Active == nil || *request.Active,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.repo.Create(ctx, &webhook); err != nil {
+		return nil, err
+	}
+	return &CreatedResponse{Webhook: webhook, Secret: secret}, nil
+}
+
+// Update replaces a webhook and returns the stored result
+func (s *Service) Update(ctx context.Context, profileID, id string, request *Request) (*Webhook, error) {
+	if err := s.authorize(ctx, profileID); err != nil {
+		return nil, err
+	}
+	if err := s.validateURL(request.URL); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(ctx, profileID, id, request); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(ctx, profileID, id)
+}
+
+func (s *Service) Delete(ctx context.Context, profileID, id string) error {
+	if err := s.authorize(ctx, profileID); err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, profileID, id)
+}
+
+// GetDeliveries returns a page of the delivery log of a webhook, newest first
+func (s *Service) GetDeliveries(ctx context.Context, profileID, id string, limit int, cursor *common.Cursor) ([]Delivery, bool, error) {
+	if _, err := s.GetByID(ctx, profileID, id); err != nil {
+		return nil, false, err
+	}
+	return s.repo.GetDeliveries(ctx, id, limit, cursor)
+}
+
+// Redeliver sends a logged delivery again and returns the new delivery. Disabled webhooks can be
+// redelivered to, for example to check an endpoint before enabling it again.
+func (s *Service) Redeliver(ctx context.Context, profileID, id, deliveryID string) (*Delivery, error) {
+	webhook, err := s.GetByID(ctx, profileID, id)
+	if err != nil {
+		return nil, err
+	}
+	previous, err := s.repo.GetDelivery(ctx, id, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	return s.dispatcher.Redeliver(ctx, webhook, previous)
+}
+
+// validateURL requires https, so payloads and signatures are not sent in the clear
+func (s *Service) validateURL(rawURL string) error {
+	parsed, err := url.Parse(rawURL)
+	if err == nil && (parsed.Scheme == "https" || (s.allowInsecure && parsed.Scheme == "http")) && parsed.Host != "" {
+		return nil
+	}
+	return common.FieldErrors{{Field: "url", Rule: "https", Message: "url must be an https URL"}}
+}
+
+// Webhooks expose the profile data to third parties, so only owners manage them
+func (s *Service) authorize(ctx context.Context, profileID string) error {
+	// Verify profile exists
+	exists, err := s.profileService.Exists(ctx, profileID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return types.ErrNotFound{Message: "profile not found"}
+	}
+
+	return s.authorizer.Authorize(ctx, profileID, access.ActionManage)
+}
+
+func generateSecret() (string, error) {
+	buf := make([]byte, secretBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return secretPrefix + base64.RawURLEncoding.EncodeToString(buf), nil
+}
@@ -0,0 +1,110 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mrthoabby/portfolio-api/internal/common"
+	"github.com/mrthoabby/portfolio-api/internal/common/contracts"
+	"github.com/mrthoabby/portfolio-api/internal/common/types"
+)
+
+const (
+	// APIKeyHeader is the request header carrying an API key
+	APIKeyHeader = "X-API-Key"
+
+	apiKeyPrefix = "pk_"
+	apiKeyBytes  = 32
+
+	// lastUsedInterval is how stale the recorded last usage of a key may get, so busy keys
+	// do not cost a write per request
+	lastUsedInterval = time.Minute
+)
+
+// APIKey is a stored API key. Only the SHA-256 hash of the key is persisted.
+type APIKey struct {
+	ID         string     `json:"id" bson:"_id,omitempty"`
+	Name       string     `json:"name" bson:"name"`
+	Subject    string     `json:"subject" bson:"subject"`
+	KeyHash    string     `json:"-" bson:"keyHash"`
+	CreatedAt  time.Time  `json:"createdAt" bson:"createdAt"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty" bson:"lastUsedAt,omitempty"`
+	RevokedAt  *time.Time `json:"revokedAt,omitempty" bson:"revokedAt,omitempty"`
+}
+
+// APIKeyAuthenticator authenticates requests carrying an X-API-Key header
+// against the hashed keys stored in the api_keys collection
+type APIKeyAuthenticator struct {
+	store contracts.Store
+}
+
+var _ Authenticator = (*APIKeyAuthenticator)(nil)
+
+func NewAPIKeyAuthenticator(dataSource contracts.DataSource) *APIKeyAuthenticator {
+	return &APIKeyAuthenticator{
+		store: dataSource.Store("api_keys"),
+	}
+}
+
+// Authenticate implements Authenticator
+func (a *APIKeyAuthenticator) Authenticate(r *http.Request) (*common.Principal, error) {
+	key := strings.TrimSpace(r.Header.Get(APIKeyHeader))
+	if key == "" {
+		return nil, ErrNoCredentials
+	}
+	if !strings.HasPrefix(key, apiKeyPrefix) {
+		return nil, ErrInvalidCredentials
+	}
+
+	var apiKey APIKey
+	filter := map[string]interface{}{
+		"keyHash":   HashAPIKey(key),
+		"revokedAt": nil,
+	}
+	if err := a.store.FindOne(r.Context(), filter, &apiKey); err != nil {
+		if types.IsNotFoundError(err) {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, err
+	}
+
+	a.touch(r.Context(), &apiKey)
+
+	return &common.Principal{
+		Subject: apiKey.Subject,
+		Method:  common.AuthMethodAPIKey,
+		KeyID:   apiKey.ID,
+	}, nil
+}
+
+// touch records the last usage of a key, at most once per lastUsedInterval.
+// Failures are ignored since they must not block the request.
+func (a *APIKeyAuthenticator) touch(ctx context.Context, apiKey *APIKey) {
+	now := time.Now()
+	if apiKey.LastUsedAt != nil && now.Sub(*apiKey.LastUsedAt) < lastUsedInterval {
+		return
+	}
+	_ = a.store.UpdateOne(ctx, map[string]interface{}{"_id": apiKey.ID}, map[string]interface{}{"lastUsedAt": now})
+}
+
+// GenerateAPIKey returns a new random API key and the hash to store for it
+func GenerateAPIKey() (key string, hash string, err error) {
+	buf := make([]byte, apiKeyBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+	key = apiKeyPrefix + base64.RawURLEncoding.EncodeToString(buf)
+	return key, HashAPIKey(key), nil
+}
+
+// HashAPIKey returns the hex encoded SHA-256 hash of an API key
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
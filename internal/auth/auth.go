@@ -0,0 +1,44 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/mrthoabby/portfolio-api/internal/common"
+)
+
+var (
+	// ErrNoCredentials is returned when a request carries no credentials for an authenticator
+	ErrNoCredentials = errors.New("no credentials provided")
+
+	// ErrInvalidCredentials is returned when credentials are present but cannot be verified
+	ErrInvalidCredentials = errors.New("invalid credentials")
+)
+
+// Authenticator resolves the principal behind a request.
+// Implementations return ErrNoCredentials when the request carries none of the
+// credentials they understand, so that several authenticators can be chained.
+type Authenticator interface {
+	Authenticate(r *http.Request) (*common.Principal, error)
+}
+
+// Chain tries each authenticator in order and returns the first principal found.
+// Invalid credentials stop the chain immediately.
+type Chain []Authenticator
+
+var _ Authenticator = Chain(nil)
+
+// Authenticate implements Authenticator
+func (c Chain) Authenticate(r *http.Request) (*common.Principal, error) {
+	for _, authenticator := range c {
+		principal, err := authenticator.Authenticate(r)
+		if errors.Is(err, ErrNoCredentials) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return principal, nil
+	}
+	return nil, ErrNoCredentials
+}
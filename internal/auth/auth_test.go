@@ -0,0 +1,117 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mrthoabby/portfolio-api/internal/common"
+	"github.com/mrthoabby/portfolio-api/internal/common/contracts"
+)
+
+type stubAuthenticator struct {
+	principal *common.Principal
+	err       error
+}
+
+func (s stubAuthenticator) Authenticate(r *http.Request) (*common.Principal, error) {
+	return s.principal, s.err
+}
+
+func TestChain_FirstMatchWins(t *testing.T) {
+	chain := Chain{
+		stubAuthenticator{err: ErrNoCredentials},
+		stubAuthenticator{principal: &common.Principal{Subject: "second"}},
+		stubAuthenticator{principal: &common.Principal{Subject: "third"}},
+	}
+
+	principal, err := chain.Authenticate(httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, "second", principal.Subject)
+}
+
+func TestChain_InvalidCredentialsStopChain(t *testing.T) {
+	chain := Chain{
+		stubAuthenticator{err: ErrInvalidCredentials},
+		stubAuthenticator{principal: &common.Principal{Subject: "never"}},
+	}
+
+	_, err := chain.Authenticate(httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+}
+
+func TestChain_NoCredentials(t *testing.T) {
+	chain := Chain{stubAuthenticator{err: ErrNoCredentials}}
+
+	_, err := chain.Authenticate(httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.True(t, errors.Is(err, ErrNoCredentials))
+}
+
+func TestGenerateAPIKey(t *testing.T) {
+	key, hash, err := GenerateAPIKey()
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(key, apiKeyPrefix))
+	assert.Equal(t, HashAPIKey(key), hash)
+	assert.Len(t, hash, 64)
+
+	other, _, err := GenerateAPIKey()
+	assert.NoError(t, err)
+	assert.NotEqual(t, key, other)
+}
+
+func TestAPIKeyAuthenticator_RejectsMalformedKeys(t *testing.T) {
+	authenticator := &APIKeyAuthenticator{}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	_, err := authenticator.Authenticate(req)
+	assert.ErrorIs(t, err, ErrNoCredentials)
+
+	req.Header.Set(APIKeyHeader, "not-a-portfolio-key")
+	_, err = authenticator.Authenticate(req)
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+}
+
+// apiKeyStore finds a single key and counts the writes recording its usage
+type apiKeyStore struct {
+	contracts.Store
+	apiKey  APIKey
+	touches int
+}
+
+func (s *apiKeyStore) FindOne(_ context.Context, _ map[string]interface{}, result interface{}) error {
+	*result.(*APIKey) = s.apiKey
+	return nil
+}
+
+func (s *apiKeyStore) UpdateOne(_ context.Context, _ map[string]interface{}, update map[string]interface{}) error {
+	lastUsedAt := update["lastUsedAt"].(time.Time)
+	s.apiKey.LastUsedAt = &lastUsedAt
+	s.touches++
+	return nil
+}
+
+func TestAPIKeyAuthenticator_RecordsUsageOncePerInterval(t *testing.T) {
+	store := &apiKeyStore{apiKey: APIKey{ID: "key-1", Subject: "user-123"}}
+	authenticator := &APIKeyAuthenticator{store: store}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(APIKeyHeader, "pk_valid")
+	for range 3 {
+		principal, err := authenticator.Authenticate(req)
+		assert.NoError(t, err)
+		assert.Equal(t, "key-1", principal.KeyID)
+	}
+	assert.Equal(t, 1, store.touches)
+
+	stale := time.Now().Add(-2 * lastUsedInterval)
+	store.apiKey.LastUsedAt = &stale
+	_, err := authenticator.Authenticate(req)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, store.touches)
+}
@@ -0,0 +1,106 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/mrthoabby/portfolio-api/internal/common"
+)
+
+// jwtLeeway tolerates small clock differences between the issuer and this server
+const jwtLeeway = 30 * time.Second
+
+// JWTConfig configures the accepted bearer tokens.
+// At least one of HMACSecret (HS256) or RSAPublicKeyPEM (RS256) must be set.
+type JWTConfig struct {
+	HMACSecret      string
+	RSAPublicKeyPEM []byte
+	Issuer          string
+	Audience        string
+}
+
+// JWTAuthenticator authenticates requests carrying an "Authorization: Bearer" JWT
+type JWTAuthenticator struct {
+	hmacSecret []byte
+	publicKey  *rsa.PublicKey
+	parser     *jwt.Parser
+}
+
+var _ Authenticator = (*JWTAuthenticator)(nil)
+
+func NewJWTAuthenticator(config JWTConfig) (*JWTAuthenticator, error) {
+	authenticator := &JWTAuthenticator{}
+	var methods []string
+
+	if config.HMACSecret != "" {
+		authenticator.hmacSecret = []byte(config.HMACSecret)
+		methods = append(methods, jwt.SigningMethodHS256.Alg())
+	}
+
+	if len(config.RSAPublicKeyPEM) > 0 {
+		publicKey, err := jwt.ParseRSAPublicKeyFromPEM(config.RSAPublicKeyPEM)
+		if err != nil {
+			return nil, fmt.Errorf("invalid RS256 public key: %w", err)
+		}
+		authenticator.publicKey = publicKey
+		methods = append(methods, jwt.SigningMethodRS256.Alg())
+	}
+
+	if len(methods) == 0 {
+		return nil, errors.New("JWT authentication requires an HS256 secret or an RS256 public key")
+	}
+
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods(methods),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(jwtLeeway),
+	}
+	if config.Issuer != "" {
+		options = append(options, jwt.WithIssuer(config.Issuer))
+	}
+	if config.Audience != "" {
+		options = append(options, jwt.WithAudience(config.Audience))
+	}
+	authenticator.parser = jwt.NewParser(options...)
+
+	return authenticator, nil
+}
+
+// Authenticate implements Authenticator
+func (a *JWTAuthenticator) Authenticate(r *http.Request) (*common.Principal, error) {
+	tokenString, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || strings.TrimSpace(tokenString) == "" {
+		return nil, ErrNoCredentials
+	}
+
+	var claims jwt.RegisteredClaims
+	if _, err := a.parser.ParseWithClaims(strings.TrimSpace(tokenString), &claims, a.keyFunc); err != nil {
+		return nil, ErrInvalidCredentials
+	}
+	if claims.Subject == "" {
+		return nil, ErrInvalidCredentials
+	}
+
+	return &common.Principal{
+		Subject: claims.Subject,
+		Method:  common.AuthMethodJWT,
+		KeyID:   claims.ID,
+	}, nil
+}
+
+func (a *JWTAuthenticator) keyFunc(token *jwt.Token) (interface{}, error) {
+	switch token.Method.Alg() {
+	case jwt.SigningMethodHS256.Alg():
+		return a.hmacSecret, nil
+	case jwt.SigningMethodRS256.Alg():
+		return a.publicKey, nil
+	default:
+		return nil, fmt.Errorf("unexpected signing method %q", token.Method.Alg())
+	}
+}
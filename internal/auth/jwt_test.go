@@ -0,0 +1,116 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrthoabby/portfolio-api/internal/common"
+)
+
+const testSecret = "test-secret-with-enough-entropy"
+
+func bearerRequest(token string) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/profiles", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	return req
+}
+
+func signHS256(t *testing.T, claims jwt.RegisteredClaims) string {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(testSecret))
+	require.NoError(t, err)
+	return token
+}
+
+func TestNewJWTAuthenticator_RequiresKey(t *testing.T) {
+	_, err := NewJWTAuthenticator(JWTConfig{})
+	assert.Error(t, err)
+}
+
+func TestJWTAuthenticator_HS256(t *testing.T) {
+	authenticator, err := NewJWTAuthenticator(JWTConfig{HMACSecret: testSecret, Issuer: "portfolio", Audience: "portfolio-api"})
+	require.NoError(t, err)
+
+	valid := jwt.RegisteredClaims{
+		Subject:   "user-123",
+		ID:        "token-1",
+		Issuer:    "portfolio",
+		Audience:  jwt.ClaimStrings{"portfolio-api"},
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+	}
+
+	principal, err := authenticator.Authenticate(bearerRequest(signHS256(t, valid)))
+	require.NoError(t, err)
+	assert.Equal(t, "user-123", principal.Subject)
+	assert.Equal(t, common.AuthMethodJWT, principal.Method)
+	assert.Equal(t, "token-1", principal.KeyID)
+
+	tests := []struct {
+		name   string
+		mutate func(claims *jwt.RegisteredClaims)
+	}{
+		{"expired", func(c *jwt.RegisteredClaims) { c.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Hour)) }},
+		{"missing expiry", func(c *jwt.RegisteredClaims) { c.ExpiresAt = nil }},
+		{"wrong issuer", func(c *jwt.RegisteredClaims) { c.Issuer = "someone-else" }},
+		{"wrong audience", func(c *jwt.RegisteredClaims) { c.Audience = jwt.ClaimStrings{"other-api"} }},
+		{"missing subject", func(c *jwt.RegisteredClaims) { c.Subject = "" }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := valid
+			tt.mutate(&claims)
+
+			_, err := authenticator.Authenticate(bearerRequest(signHS256(t, claims)))
+			assert.ErrorIs(t, err, ErrInvalidCredentials)
+		})
+	}
+}
+
+func TestJWTAuthenticator_RS256(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	publicKeyDER, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	require.NoError(t, err)
+	publicKeyPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKeyDER})
+
+	authenticator, err := NewJWTAuthenticator(JWTConfig{RSAPublicKeyPEM: publicKeyPEM})
+	require.NoError(t, err)
+
+	claims := jwt.RegisteredClaims{
+		Subject:   "user-456",
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodRS256, claims).SignedString(privateKey)
+	require.NoError(t, err)
+
+	principal, err := authenticator.Authenticate(bearerRequest(token))
+	require.NoError(t, err)
+	assert.Equal(t, "user-456", principal.Subject)
+
+	// HS256 tokens are rejected when only RS256 is configured
+	_, err = authenticator.Authenticate(bearerRequest(signHS256(t, claims)))
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+}
+
+func TestJWTAuthenticator_NoCredentials(t *testing.T) {
+	authenticator, err := NewJWTAuthenticator(JWTConfig{HMACSecret: testSecret})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	_, err = authenticator.Authenticate(req)
+	assert.ErrorIs(t, err, ErrNoCredentials)
+
+	req.Header.Set("Authorization", "Basic dXNlcjpwYXNz")
+	_, err = authenticator.Authenticate(req)
+	assert.ErrorIs(t, err, ErrNoCredentials)
+}
@@ -0,0 +1,61 @@
+// Package captcha verifies the challenge responses anonymous clients send with public forms.
+package captcha
+
+import (
+	"context"
+
+	"github.com/mrthoabby/portfolio-api/internal/common/types"
+)
+
+// TokenHeader is the request header carrying the challenge response token
+const TokenHeader = "X-Captcha-Token"
+
+// AllProfiles enables challenges for every profile when listed in a ProfileSet
+const AllProfiles = "*"
+
+var (
+	// ErrMissingToken is returned when a challenge is required but the request carries no token
+	ErrMissingToken = types.ErrValidation{Message: "captcha token is required"}
+
+	// ErrInvalidToken is returned when the provider rejects the token
+	ErrInvalidToken = types.ErrValidation{Message: "captcha verification failed"}
+)
+
+// Verifier checks a challenge response token with a CAPTCHA provider.
+// Implementations return ErrInvalidToken when the token is rejected and
+// types.ErrUnavailable when the provider cannot be reached.
+type Verifier interface {
+	Verify(ctx context.Context, token, remoteIP string) error
+}
+
+// ProfileSet lists the profiles whose public forms require a challenge
+type ProfileSet map[string]bool
+
+// NewProfileSet creates a set of profile IDs; AllProfiles enables every profile
+func NewProfileSet(profileIDs []string) ProfileSet {
+	set := make(ProfileSet, len(profileIDs))
+	for _, id := range profileIDs {
+		set[id] = true
+	}
+	return set
+}
+
+// Enabled reports whether the forms of a profile require a challenge
+func (s ProfileSet) Enabled(profileID string) bool {
+	return s[AllProfiles] || s[profileID]
+}
+
+// FakeVerifier accepts a single known token without calling any provider, for tests and local development
+type FakeVerifier struct {
+	Token string
+}
+
+var _ Verifier = FakeVerifier{}
+
+// Verify implements Verifier
+func (v FakeVerifier) Verify(_ context.Context, token, _ string) error {
+	if v.Token == "" || token != v.Token {
+		return ErrInvalidToken
+	}
+	return nil
+}
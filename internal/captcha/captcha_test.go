@@ -0,0 +1,90 @@
+package captcha
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mrthoabby/portfolio-api/internal/common/types"
+)
+
+func TestProfileSet_Enabled(t *testing.T) {
+	assert.True(t, NewProfileSet([]string{"p1"}).Enabled("p1"))
+	assert.False(t, NewProfileSet([]string{"p1"}).Enabled("p2"))
+	assert.True(t, NewProfileSet([]string{AllProfiles}).Enabled("p2"))
+	assert.False(t, NewProfileSet(nil).Enabled("p1"))
+}
+
+func TestFakeVerifier(t *testing.T) {
+	verifier := FakeVerifier{Token: "pass"}
+	assert.NoError(t, verifier.Verify(context.Background(), "pass", ""))
+	assert.ErrorIs(t, verifier.Verify(context.Background(), "fail", ""), ErrInvalidToken)
+	assert.ErrorIs(t, FakeVerifier{}.Verify(context.Background(), "", ""), ErrInvalidToken)
+}
+
+func newSiteverifyServer(t *testing.T, status int, body string) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.NoError(t, r.ParseForm())
+		assert.Equal(t, "secret", r.PostForm.Get("secret"))
+		assert.Equal(t, "token", r.PostForm.Get("response"))
+		assert.Equal(t, "203.0.113.7", r.PostForm.Get("remoteip"))
+
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestSiteVerifier_Verify(t *testing.T) {
+	constructors := map[string]func(string, ...Option) *SiteVerifier{
+		"turnstile": NewTurnstileVerifier,
+		"hcaptcha":  NewHCaptchaVerifier,
+		"recaptcha": NewRecaptchaVerifier,
+	}
+
+	tests := []struct {
+		name     string
+		status   int
+		body     string
+		opts     []Option
+		expected error
+	}{
+		{name: "solved", status: http.StatusOK, body: `{"success":true,"hostname":"example.com"}`},
+		{name: "rejected", status: http.StatusOK, body: `{"success":false,"error-codes":["invalid-input-response"]}`, expected: ErrInvalidToken},
+		{name: "score over the minimum", status: http.StatusOK, body: `{"success":true,"score":0.7}`, opts: []Option{WithMinScore(0.5)}},
+		{name: "score under the minimum", status: http.StatusOK, body: `{"success":true,"score":0.3}`, opts: []Option{WithMinScore(0.5)}, expected: ErrInvalidToken},
+		{name: "invalid secret", status: http.StatusOK, body: `{"success":false,"error-codes":["invalid-input-secret"]}`, expected: types.ErrUnavailable{}},
+		{name: "provider error", status: http.StatusInternalServerError, body: `oops`, expected: types.ErrUnavailable{}},
+		{name: "malformed response", status: http.StatusOK, body: `<html>`, expected: types.ErrUnavailable{}},
+	}
+
+	for provider, constructor := range constructors {
+		for _, tt := range tests {
+			t.Run(provider+"/"+tt.name, func(t *testing.T) {
+				server := newSiteverifyServer(t, tt.status, tt.body)
+				verifier := constructor("secret", append(tt.opts, WithEndpoint(server.URL))...)
+
+				err := verifier.Verify(context.Background(), "token", "203.0.113.7")
+				if tt.expected == nil {
+					assert.NoError(t, err)
+				} else {
+					assert.True(t, errors.Is(err, tt.expected), err)
+				}
+			})
+		}
+	}
+}
+
+func TestSiteVerifier_Unreachable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	err := NewTurnstileVerifier("secret", WithEndpoint(server.URL)).Verify(context.Background(), "token", "")
+	assert.ErrorIs(t, err, types.ErrUnavailable{})
+}
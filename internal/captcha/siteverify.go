@@ -0,0 +1,138 @@
+package captcha
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/mrthoabby/portfolio-api/internal/common/types"
+)
+
+// Siteverify endpoints of the supported providers
+const (
+	TurnstileEndpoint = "https://challenges.cloudflare.com/turnstile/v0/siteverify"
+	HCaptchaEndpoint  = "https://api.hcaptcha.com/siteverify"
+	RecaptchaEndpoint = "https://www.google.com/recaptcha/api/siteverify"
+)
+
+// verifyTimeout bounds a call to the provider
+const verifyTimeout = 5 * time.Second
+
+// SiteVerifier verifies tokens through the siteverify API that Cloudflare Turnstile, hCaptcha and
+// reCAPTCHA share: the secret, the token and the client IP are posted as a form, and the provider
+// answers whether the challenge was solved.
+type SiteVerifier struct {
+	provider string
+	endpoint string
+	secret   string
+	minScore float64
+	client   *http.Client
+}
+
+var _ Verifier = (*SiteVerifier)(nil)
+
+// Option customizes a SiteVerifier
+type Option func(*SiteVerifier)
+
+// WithEndpoint overrides the provider siteverify URL
+func WithEndpoint(endpoint string) Option {
+	return func(verifier *SiteVerifier) {
+		verifier.endpoint = endpoint
+	}
+}
+
+// WithHTTPClient sets the client used to call the provider
+func WithHTTPClient(client *http.Client) Option {
+	return func(verifier *SiteVerifier) {
+		verifier.client = client
+	}
+}
+
+// WithMinScore rejects tokens scored below minScore, for providers that score
+// their challenges such as reCAPTCHA v3; zero accepts any score
+func WithMinScore(minScore float64) Option {
+	return func(verifier *SiteVerifier) {
+		verifier.minScore = minScore
+	}
+}
+
+// NewTurnstileVerifier creates a verifier for Cloudflare Turnstile
+func NewTurnstileVerifier(secret string, opts ...Option) *SiteVerifier {
+	return newSiteVerifier("turnstile", TurnstileEndpoint, secret, opts)
+}
+
+// NewHCaptchaVerifier creates a verifier for hCaptcha
+func NewHCaptchaVerifier(secret string, opts ...Option) *SiteVerifier {
+	return newSiteVerifier("hcaptcha", HCaptchaEndpoint, secret, opts)
+}
+
+// NewRecaptchaVerifier creates a verifier for Google reCAPTCHA v2 or v3
+func NewRecaptchaVerifier(secret string, opts ...Option) *SiteVerifier {
+	return newSiteVerifier("recaptcha", RecaptchaEndpoint, secret, opts)
+}
+
+func newSiteVerifier(provider, endpoint, secret string, opts []Option) *SiteVerifier {
+	verifier := &SiteVerifier{
+		provider: provider,
+		endpoint: endpoint,
+		secret:   secret,
+		client:   &http.Client{Timeout: verifyTimeout},
+	}
+	for _, opt := range opts {
+		opt(verifier)
+	}
+	return verifier
+}
+
+type siteverifyResponse struct {
+	Success    bool     `json:"success"`
+	Score      *float64 `json:"score"`
+	ErrorCodes []string `json:"error-codes"`
+}
+
+// Verify implements Verifier
+func (v *SiteVerifier) Verify(ctx context.Context, token, remoteIP string) error {
+	form := url.Values{"secret": {v.secret}, "response": {token}}
+	if remoteIP != "" {
+		form.Set("remoteip", remoteIP)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, v.endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return types.ErrUnavailable{Message: v.provider + " is unreachable", Cause: err}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return types.ErrUnavailable{Message: fmt.Sprintf("%s answered with status %d", v.provider, resp.StatusCode)}
+	}
+
+	var result siteverifyResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return types.ErrUnavailable{Message: v.provider + " sent an invalid response", Cause: err}
+	}
+
+	if !result.Success {
+		for _, code := range result.ErrorCodes {
+			// A bad secret is our misconfiguration, not the client's fault
+			if code == "missing-input-secret" || code == "invalid-input-secret" {
+				return types.ErrUnavailable{Message: v.provider + " rejected the configured secret"}
+			}
+		}
+		return ErrInvalidToken
+	}
+	if v.minScore > 0 && result.Score != nil && *result.Score < v.minScore {
+		return ErrInvalidToken
+	}
+	return nil
+}
@@ -1,9 +1,32 @@
 package common
 
-import "context"
+import (
+	"context"
+
+	"github.com/mrthoabby/portfolio-api/internal/common/logger"
+)
 
 // ContextKey types for type-safe context values
 type clientIPKey struct{}
+type principalKey struct{}
+type requestIDKey struct{}
+type loggerKey struct{}
+
+// Authentication methods recorded on a Principal
+const (
+	AuthMethodAPIKey = "api_key"
+	AuthMethodJWT    = "jwt"
+)
+
+// Principal is the authenticated caller of a request
+type Principal struct {
+	// Subject uniquely identifies the caller (API key owner or JWT "sub" claim)
+	Subject string
+	// Method is the authentication method that produced this principal
+	Method string
+	// KeyID identifies the API key or JWT used, for auditing
+	KeyID string
+}
 
 // WithClientIP stores the client IP in the context
 func WithClientIP(ctx context.Context, ip string) context.Context {
@@ -18,3 +41,43 @@ func ClientIPFromContext(ctx context.Context) string {
 	return ""
 }
 
+// WithPrincipal stores the authenticated principal in the context
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFromContext retrieves the authenticated principal from context.
+// Returns nil if the request is anonymous.
+func PrincipalFromContext(ctx context.Context) *Principal {
+	if principal, ok := ctx.Value(principalKey{}).(*Principal); ok {
+		return principal
+	}
+	return nil
+}
+
+// WithRequestID stores the request ID in the context
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFromContext retrieves the request ID from context
+func RequestIDFromContext(ctx context.Context) string {
+	if id, ok := ctx.Value(requestIDKey{}).(string); ok {
+		return id
+	}
+	return ""
+}
+
+// WithLogger stores the application logger in the context
+func WithLogger(ctx context.Context, l logger.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, l)
+}
+
+// LoggerFromContext retrieves the application logger from context.
+// Returns nil if no logger was stored.
+func LoggerFromContext(ctx context.Context) logger.Logger {
+	if l, ok := ctx.Value(loggerKey{}).(logger.Logger); ok {
+		return l
+	}
+	return nil
+}
@@ -6,6 +6,8 @@ import "context"
 // Implementations provide access to named stores (collections, tables, etc.)
 // and handle connection lifecycle.
 type DataSource interface {
+	Transactor
+
 	// Store returns a Store for the given name (collection, table, etc.).
 	Store(name string) Store
 
@@ -15,3 +17,11 @@ type DataSource interface {
 	// Ping verifies the data source connection is alive.
 	Ping(ctx context.Context) error
 }
+
+// Transactor runs units of work atomically.
+type Transactor interface {
+	// WithTransaction runs fn in a transaction, committing when it returns nil and aborting otherwise.
+	// Store operations take part in the transaction when they are given the context passed to fn.
+	// fn may be called more than once when the transaction is retried after a transient error.
+	WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
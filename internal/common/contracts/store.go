@@ -1,6 +1,58 @@
 package contracts
 
-import "context"
+import (
+	"context"
+	"time"
+)
+
+// FindOptions configures FindManyWithOptions.
+type FindOptions struct {
+	// Sort is a slice of field names; prefix with "-" for descending order.
+	Sort []string
+
+	// Limit caps the number of records returned. Zero means no limit.
+	Limit int64
+}
+
+// Update describes the modifications applied by UpdateOneWith.
+type Update struct {
+	// Set assigns the given field values.
+	Set map[string]interface{}
+
+	// Push appends each value to the array field of the same name.
+	Push map[string]interface{}
+
+	// Unset removes the given fields.
+	Unset []string
+
+	// Inc adds each value to the numeric field of the same name, starting from zero.
+	Inc map[string]interface{}
+
+	// SetOnInsert assigns the given field values only when the update inserts a new record.
+	SetOnInsert map[string]interface{}
+}
+
+// FindOneAndUpdateOptions configures FindOneAndUpdate.
+type FindOneAndUpdateOptions struct {
+	// Upsert inserts a record built from the filter and the update when none matches.
+	Upsert bool
+
+	// Sort picks the first record in this order when several match; prefix with "-" for descending order.
+	Sort []string
+}
+
+// Index describes a secondary index created by EnsureIndex.
+type Index struct {
+	// Fields is a slice of field names; prefix with "-" for descending order.
+	Fields []string
+
+	// Unique rejects records that repeat the indexed values.
+	Unique bool
+
+	// ExpireAfter, when set, removes records once the date in the single indexed field is
+	// older than this duration. Zero expires them at that date.
+	ExpireAfter *time.Duration
+}
 
 // Store defines the contract for data storage operations.
 // This abstraction allows switching between different storage implementations
@@ -16,6 +68,9 @@ type Store interface {
 	// Example: []string{"category", "-createdAt"} sorts by category ASC, then createdAt DESC.
 	FindMany(ctx context.Context, filter map[string]interface{}, sortFields []string, results interface{}) error
 
+	// FindManyWithOptions finds the records matching the filter, applying the given sort and limit.
+	FindManyWithOptions(ctx context.Context, filter map[string]interface{}, opts FindOptions, results interface{}) error
+
 	// InsertOne inserts a single record into the store.
 	InsertOne(ctx context.Context, record interface{}) error
 
@@ -23,5 +78,25 @@ type Store interface {
 	CountRecords(ctx context.Context, filter map[string]interface{}) (int64, error)
 
 	// UpdateOne updates a single record matching the filter.
+	// Returns ErrNotFound if no record matches.
 	UpdateOne(ctx context.Context, filter map[string]interface{}, update map[string]interface{}) error
+
+	// UpdateOneWith applies a structured update to a single record matching the filter.
+	// Returns ErrNotFound if no record matches.
+	UpdateOneWith(ctx context.Context, filter map[string]interface{}, update Update) error
+
+	// FindOneAndUpdate atomically applies the update to a single record matching the filter
+	// and decodes the updated record into result.
+	// Returns ErrNotFound if no record matches and opts.Upsert is false.
+	FindOneAndUpdate(ctx context.Context, filter map[string]interface{}, update Update, opts FindOneAndUpdateOptions, result interface{}) error
+
+	// EnsureIndex creates the index if it does not exist yet.
+	EnsureIndex(ctx context.Context, index Index) error
+
+	// DeleteOne deletes a single record matching the filter.
+	// Returns ErrNotFound if no record matches.
+	DeleteOne(ctx context.Context, filter map[string]interface{}) error
+
+	// DeleteMany deletes every record matching the filter and returns how many were deleted.
+	DeleteMany(ctx context.Context, filter map[string]interface{}) (int64, error)
 }
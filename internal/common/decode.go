@@ -0,0 +1,140 @@
+package common
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// MergePatchContentType is the media type of JSON merge patch (RFC 7386) documents
+const MergePatchContentType = "application/merge-patch+json"
+
+// BodyError describes why a request body was rejected; RespondServiceError writes it with its own status
+type BodyError struct {
+	Status  int
+	Code    string
+	Message string
+	Err     error
+}
+
+func (e *BodyError) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *BodyError) Unwrap() error {
+	return e.Err
+}
+
+// DecodeJSON decodes a JSON request body into dst. The body must be sent as application/json
+// (or a +json media type), hold exactly one JSON value and only fields known to dst.
+// Failures are returned as *BodyError.
+func DecodeJSON(r *http.Request, dst interface{}) error {
+	if !isJSONContentType(r.Header.Get("Content-Type")) {
+		return unsupportedMediaType("application/json")
+	}
+	return decodeStrict(r.Body, dst)
+}
+
+// ReadMergePatch reads a JSON merge patch (RFC 7386) request body. Both application/merge-patch+json
+// and application/json are accepted; the body must be a single JSON object.
+func ReadMergePatch(r *http.Request) ([]byte, error) {
+	if !isJSONContentType(r.Header.Get("Content-Type")) {
+		return nil, unsupportedMediaType(MergePatchContentType)
+	}
+
+	var patch map[string]json.RawMessage
+	if err := decodeStrict(r.Body, &patch); err != nil {
+		return nil, err
+	}
+	if patch == nil {
+		return nil, &BodyError{Status: http.StatusBadRequest, Code: "BAD_REQUEST", Message: "Merge patch must be a JSON object"}
+	}
+	return json.Marshal(patch)
+}
+
+// UnmarshalStrict decodes a JSON document into dst with the same rules as DecodeJSON,
+// for documents built by the server such as the result of a merge patch
+func UnmarshalStrict(data []byte, dst interface{}) error {
+	return decodeStrict(bytes.NewReader(data), dst)
+}
+
+func decodeStrict(body io.Reader, dst interface{}) error {
+	if body == nil {
+		body = http.NoBody
+	}
+
+	decoder := json.NewDecoder(body)
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(dst); err != nil {
+		return decodeError(err)
+	}
+
+	// Anything but whitespace after the first value is rejected
+	if err := decoder.Decode(&struct{}{}); !errors.Is(err, io.EOF) {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return decodeError(err)
+		}
+		return badRequest("Request body must contain a single JSON value", err)
+	}
+	return nil
+}
+
+func decodeError(err error) error {
+	var maxBytesErr *http.MaxBytesError
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+
+	switch {
+	case errors.As(err, &maxBytesErr):
+		return &BodyError{
+			Status:  http.StatusRequestEntityTooLarge,
+			Code:    "PAYLOAD_TOO_LARGE",
+			Message: fmt.Sprintf("Request body must not exceed %d bytes", maxBytesErr.Limit),
+			Err:     err,
+		}
+	case errors.Is(err, io.EOF):
+		return badRequest("Request body is required", err)
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return badRequest("Request body contains malformed JSON", err)
+	case errors.As(err, &syntaxErr):
+		return badRequest(fmt.Sprintf("Request body contains malformed JSON at position %d", syntaxErr.Offset), err)
+	case errors.As(err, &typeErr) && typeErr.Field != "":
+		return badRequest(fmt.Sprintf("Field %q has an invalid type", typeErr.Field), err)
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		// encoding/json has no typed error for unknown fields
+		field := strings.TrimPrefix(err.Error(), "json: unknown field ")
+		return badRequest("Request body contains unknown field "+field, err)
+	default:
+		return badRequest("Invalid request body", err)
+	}
+}
+
+func isJSONContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" || (strings.HasPrefix(mediaType, "application/") && strings.HasSuffix(mediaType, "+json"))
+}
+
+func unsupportedMediaType(expected string) error {
+	return &BodyError{
+		Status:  http.StatusUnsupportedMediaType,
+		Code:    "UNSUPPORTED_MEDIA_TYPE",
+		Message: "Content-Type must be " + expected,
+	}
+}
+
+func badRequest(message string, err error) error {
+	return &BodyError{Status: http.StatusBadRequest, Code: "BAD_REQUEST", Message: message, Err: err}
+}
@@ -0,0 +1,169 @@
+package common
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type decodedRequest struct {
+	Name string `json:"name"`
+	Age  int    `json:"age"`
+}
+
+func newJSONRequest(contentType, body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	return req
+}
+
+func TestDecodeJSON_Valid(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+	}{
+		{"plain", "application/json", `{"name": "Ada", "age": 36}`},
+		{"charset", "application/json; charset=utf-8", `{"name": "Ada", "age": 36}`},
+		{"structured suffix", "application/vnd.portfolio+json", `{"name": "Ada", "age": 36}`},
+		{"trailing whitespace", "application/json", "{\"name\": \"Ada\", \"age\": 36}\n\t "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var dst decodedRequest
+			err := DecodeJSON(newJSONRequest(tt.contentType, tt.body), &dst)
+
+			assert.NoError(t, err)
+			assert.Equal(t, decodedRequest{Name: "Ada", Age: 36}, dst)
+		})
+	}
+}
+
+func TestDecodeJSON_Rejected(t *testing.T) {
+	tests := []struct {
+		name           string
+		contentType    string
+		body           string
+		expectedStatus int
+		expectedCode   string
+	}{
+		{"missing content type", "", `{"name": "Ada"}`, http.StatusUnsupportedMediaType, "UNSUPPORTED_MEDIA_TYPE"},
+		{"form content type", "application/x-www-form-urlencoded", "name=Ada", http.StatusUnsupportedMediaType, "UNSUPPORTED_MEDIA_TYPE"},
+		{"text content type", "text/plain", `{"name": "Ada"}`, http.StatusUnsupportedMediaType, "UNSUPPORTED_MEDIA_TYPE"},
+		{"empty body", "application/json", "", http.StatusBadRequest, "BAD_REQUEST"},
+		{"unknown field", "application/json", `{"name": "Ada", "admin": true}`, http.StatusBadRequest, "BAD_REQUEST"},
+		{"multiple values", "application/json", `{"name": "Ada"}{"name": "Bob"}`, http.StatusBadRequest, "BAD_REQUEST"},
+		{"trailing garbage", "application/json", `{"name": "Ada"} garbage`, http.StatusBadRequest, "BAD_REQUEST"},
+		{"malformed", "application/json", `{"name": }`, http.StatusBadRequest, "BAD_REQUEST"},
+		{"truncated", "application/json", `{"name": "Ada"`, http.StatusBadRequest, "BAD_REQUEST"},
+		{"wrong type", "application/json", `{"age": "old"}`, http.StatusBadRequest, "BAD_REQUEST"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var dst decodedRequest
+			err := DecodeJSON(newJSONRequest(tt.contentType, tt.body), &dst)
+
+			var bodyErr *BodyError
+			assert.True(t, errors.As(err, &bodyErr))
+			assert.Equal(t, tt.expectedStatus, bodyErr.Status)
+			assert.Equal(t, tt.expectedCode, bodyErr.Code)
+		})
+	}
+}
+
+func TestDecodeJSON_Messages(t *testing.T) {
+	var dst decodedRequest
+
+	err := DecodeJSON(newJSONRequest("application/json", `{"admin": true}`), &dst)
+	assert.Equal(t, `Request body contains unknown field "admin"`, err.(*BodyError).Message)
+
+	err = DecodeJSON(newJSONRequest("application/json", `{"age": "old"}`), &dst)
+	assert.Equal(t, `Field "age" has an invalid type`, err.(*BodyError).Message)
+}
+
+func TestDecodeJSON_TooLarge(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"first value", `{"name": "` + strings.Repeat("a", 64) + `"}`},
+		{"after first value", `{"name": "Ada"}` + strings.Repeat(" ", 64)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := newJSONRequest("application/json", tt.body)
+			req.Body = http.MaxBytesReader(httptest.NewRecorder(), req.Body, 20)
+
+			var dst decodedRequest
+			err := DecodeJSON(req, &dst)
+
+			var bodyErr *BodyError
+			assert.True(t, errors.As(err, &bodyErr))
+			assert.Equal(t, http.StatusRequestEntityTooLarge, bodyErr.Status)
+			assert.Equal(t, "PAYLOAD_TOO_LARGE", bodyErr.Code)
+
+			var maxBytesErr *http.MaxBytesError
+			assert.True(t, errors.As(err, &maxBytesErr))
+		})
+	}
+}
+
+func TestReadMergePatch(t *testing.T) {
+	patch, err := ReadMergePatch(newJSONRequest(MergePatchContentType, `{"visible": false}`))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"visible": false}`, string(patch))
+
+	patch, err = ReadMergePatch(newJSONRequest("application/json", `{"name": null}`))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"name": null}`, string(patch))
+
+	tests := []struct {
+		name           string
+		contentType    string
+		body           string
+		expectedStatus int
+	}{
+		{"text content type", "text/plain", `{}`, http.StatusUnsupportedMediaType},
+		{"array", MergePatchContentType, `[1, 2]`, http.StatusBadRequest},
+		{"null", MergePatchContentType, `null`, http.StatusBadRequest},
+		{"multiple values", MergePatchContentType, `{} {}`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReadMergePatch(newJSONRequest(tt.contentType, tt.body))
+
+			var bodyErr *BodyError
+			assert.True(t, errors.As(err, &bodyErr))
+			assert.Equal(t, tt.expectedStatus, bodyErr.Status)
+		})
+	}
+}
+
+func TestUnmarshalStrict(t *testing.T) {
+	var dst decodedRequest
+	assert.NoError(t, UnmarshalStrict([]byte(`{"name": "Ada"}`), &dst))
+	assert.Equal(t, "Ada", dst.Name)
+
+	err := UnmarshalStrict([]byte(`{"name": "Ada", "admin": true}`), &dst)
+	assert.Equal(t, http.StatusBadRequest, err.(*BodyError).Status)
+}
+
+func TestRespondServiceError_BodyError(t *testing.T) {
+	req := newJSONRequest("text/plain", `{}`)
+	w := httptest.NewRecorder()
+
+	RespondServiceError(w, req, DecodeJSON(req, &decodedRequest{}))
+
+	assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+	assert.JSONEq(t, `{"error": {"code": "UNSUPPORTED_MEDIA_TYPE", "message": "Content-Type must be application/json", "details": null}}`, w.Body.String())
+}
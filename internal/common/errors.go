@@ -0,0 +1,91 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/mrthoabby/portfolio-api/internal/common/logger"
+	"github.com/mrthoabby/portfolio-api/internal/common/types"
+)
+
+// RespondServiceError maps an error returned by a service to an HTTP status and error code.
+// The cause is logged with the request ID; server errors never expose it to the client.
+func RespondServiceError(w http.ResponseWriter, r *http.Request, err error) {
+	status, code, message, details := classifyError(err)
+
+	var rateLimited types.ErrRateLimited
+	if errors.As(err, &rateLimited) && rateLimited.RetryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(rateLimited.RetryAfter.Seconds()))))
+	}
+	if status == http.StatusUnauthorized {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="portfolio-api"`)
+	}
+
+	logServiceError(r, status, code, err)
+	RespondError(w, r, status, code, message, details)
+}
+
+// classifyError picks the status, code, client message and details for err
+func classifyError(err error) (int, string, string, interface{}) {
+	var fieldErrors FieldErrors
+	var validation types.ErrValidation
+	var conflict types.ErrConflict
+	var bodyErr *BodyError
+
+	switch {
+	case errors.As(err, &bodyErr):
+		return bodyErr.Status, bodyErr.Code, bodyErr.Message, nil
+	case errors.As(err, &fieldErrors):
+		return http.StatusBadRequest, "VALIDATION_ERROR", "Validation failed", fieldErrors
+	case errors.As(err, &validation):
+		return http.StatusBadRequest, "VALIDATION_ERROR", validation.Error(), validation.Details
+	case errors.Is(err, types.ErrNotFound{}):
+		return http.StatusNotFound, "NOT_FOUND", err.Error(), nil
+	case errors.As(err, &conflict):
+		code := conflict.Code
+		if code == "" {
+			code = "CONFLICT"
+		}
+		return http.StatusConflict, code, conflict.Error(), nil
+	case errors.Is(err, types.ErrUnauthenticated{}):
+		return http.StatusUnauthorized, "UNAUTHORIZED", "Authentication required", nil
+	case errors.Is(err, types.ErrForbidden{}):
+		return http.StatusForbidden, "FORBIDDEN", "You are not allowed to perform this operation", nil
+	case errors.Is(err, types.ErrRateLimited{}):
+		return http.StatusTooManyRequests, "RATE_LIMIT_EXCEEDED", "Too many requests. Please try again later.", nil
+	case errors.Is(err, types.ErrUnavailable{}), errors.Is(err, context.DeadlineExceeded):
+		return http.StatusServiceUnavailable, "SERVICE_UNAVAILABLE", "The service is temporarily unavailable", nil
+	default:
+		return http.StatusInternalServerError, "INTERNAL_ERROR", "An unexpected error occurred", nil
+	}
+}
+
+func logServiceError(r *http.Request, status int, code string, err error) {
+	requestID := RequestIDFromContext(r.Context())
+
+	l := LoggerFromContext(r.Context())
+	if l == nil {
+		if status >= http.StatusInternalServerError {
+			log.Printf("[%s] %s %s failed with %d %s: %v", requestID, r.Method, r.URL.Path, status, code, err)
+		}
+		return
+	}
+
+	fields := []logger.Field{
+		logger.String("request_id", requestID),
+		logger.String("method", r.Method),
+		logger.String("path", r.URL.Path),
+		logger.Int("status", status),
+		logger.String("code", code),
+		logger.Error(err),
+	}
+	if status >= http.StatusInternalServerError {
+		l.Error("Request failed", fields...)
+	} else {
+		l.Debug("Request rejected", fields...)
+	}
+}
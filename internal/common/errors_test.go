@@ -0,0 +1,67 @@
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mrthoabby/portfolio-api/internal/common/types"
+)
+
+func TestRespondServiceError_Status(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		code   string
+	}{
+		{"not found", types.ErrNotFound{Message: "profile not found"}, http.StatusNotFound, "NOT_FOUND"},
+		{"wrapped not found", fmt.Errorf("loading: %w", types.ErrNotFound{}), http.StatusNotFound, "NOT_FOUND"},
+		{"validation", types.ErrValidation{Message: "bad input"}, http.StatusBadRequest, "VALIDATION_ERROR"},
+		{"field errors", FieldErrors{{Field: "name", Message: "is required"}}, http.StatusBadRequest, "VALIDATION_ERROR"},
+		{"conflict", types.ErrConflict{Message: "taken"}, http.StatusConflict, "CONFLICT"},
+		{"conflict with code", types.ErrConflict{Code: "INVALID_TRANSITION"}, http.StatusConflict, "INVALID_TRANSITION"},
+		{"unauthenticated", types.ErrUnauthenticated{}, http.StatusUnauthorized, "UNAUTHORIZED"},
+		{"forbidden", types.ErrForbidden{}, http.StatusForbidden, "FORBIDDEN"},
+		{"rate limited", types.ErrRateLimited{}, http.StatusTooManyRequests, "RATE_LIMIT_EXCEEDED"},
+		{"unavailable", types.ErrUnavailable{Cause: errors.New("no reachable servers")}, http.StatusServiceUnavailable, "SERVICE_UNAVAILABLE"},
+		{"deadline", context.DeadlineExceeded, http.StatusServiceUnavailable, "SERVICE_UNAVAILABLE"},
+		{"unknown", errors.New("boom"), http.StatusInternalServerError, "INTERNAL_ERROR"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			RespondServiceError(w, httptest.NewRequest(http.MethodGet, "/", nil), tt.err)
+
+			var response ErrorResponse
+			assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+			assert.Equal(t, tt.status, w.Code)
+			assert.Equal(t, tt.code, response.Error.Code)
+		})
+	}
+}
+
+func TestRespondServiceError_HidesInternalCause(t *testing.T) {
+	w := httptest.NewRecorder()
+	err := types.ErrUnavailable{Message: "database unavailable", Cause: errors.New("dial tcp 10.0.0.5:27017")}
+
+	RespondServiceError(w, httptest.NewRequest(http.MethodGet, "/", nil), err)
+
+	assert.NotContains(t, w.Body.String(), "10.0.0.5")
+}
+
+func TestRespondServiceError_RetryAfter(t *testing.T) {
+	w := httptest.NewRecorder()
+
+	RespondServiceError(w, httptest.NewRequest(http.MethodGet, "/", nil), types.ErrRateLimited{RetryAfter: 1500 * time.Millisecond})
+
+	assert.Equal(t, "2", w.Header().Get("Retry-After"))
+}
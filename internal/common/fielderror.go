@@ -0,0 +1,34 @@
+package common
+
+import (
+	"strings"
+
+	"github.com/mrthoabby/portfolio-api/internal/common/types"
+)
+
+// FieldError describes why a single request field was rejected.
+// Field is the JSON path of the field, Rule the failed validation rule and Param its argument.
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+}
+
+// FieldErrors is returned when one or more request fields are invalid.
+// Handlers respond with it as the error details so clients can point at the offending field.
+type FieldErrors []FieldError
+
+func (e FieldErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, fieldErr := range e {
+		messages = append(messages, fieldErr.Field+": "+fieldErr.Message)
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Is makes FieldErrors match types.ErrValidation{} in errors.Is
+func (e FieldErrors) Is(target error) bool {
+	_, ok := target.(types.ErrValidation)
+	return ok
+}
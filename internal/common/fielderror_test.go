@@ -0,0 +1,19 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mrthoabby/portfolio-api/internal/common/types"
+)
+
+func TestFieldErrors_Error(t *testing.T) {
+	err := FieldErrors{{Field: "category", Message: "is invalid"}, {Field: "name", Message: "is required"}}
+	assert.Equal(t, "category: is invalid; name: is required", err.Error())
+}
+
+func TestFieldErrors_IsValidation(t *testing.T) {
+	var err error = FieldErrors{{Field: "name", Rule: "required"}}
+	assert.ErrorIs(t, err, types.ErrValidation{})
+}
@@ -0,0 +1,54 @@
+package common
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// ErrInvalidMergePatch is returned when a merge patch document is not a JSON object
+var ErrInvalidMergePatch = errors.New("merge patch must be a JSON object")
+
+// ApplyMergePatch applies a JSON Merge Patch (RFC 7386) to the original document
+// and returns the patched document. Null members in the patch remove the
+// corresponding member from the original; nested objects are merged recursively.
+func ApplyMergePatch(original, patch []byte) ([]byte, error) {
+	var patchDoc interface{}
+	if err := json.Unmarshal(patch, &patchDoc); err != nil {
+		return nil, err
+	}
+	patchObject, ok := patchDoc.(map[string]interface{})
+	if !ok {
+		return nil, ErrInvalidMergePatch
+	}
+
+	var originalDoc map[string]interface{}
+	if err := json.Unmarshal(original, &originalDoc); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(mergeObjects(originalDoc, patchObject))
+}
+
+func mergeObjects(target, patch map[string]interface{}) map[string]interface{} {
+	if target == nil {
+		target = make(map[string]interface{})
+	}
+
+	for key, patchValue := range patch {
+		if patchValue == nil {
+			delete(target, key)
+			continue
+		}
+
+		patchObject, isObject := patchValue.(map[string]interface{})
+		if !isObject {
+			target[key] = patchValue
+			continue
+		}
+
+		targetObject, _ := target[key].(map[string]interface{})
+		target[key] = mergeObjects(targetObject, patchObject)
+	}
+
+	return target
+}
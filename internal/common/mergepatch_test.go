@@ -0,0 +1,43 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyMergePatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		original string
+		patch    string
+		expected string
+	}{
+		{"replace member", `{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{"add member", `{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{"remove member", `{"a":"b"}`, `{"a":null}`, `{}`},
+		{"remove one of many", `{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{"replace array", `{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{"nested merge", `{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{"object over scalar", `{"a":"b"}`, `{"a":{"c":"d"}}`, `{"a":{"c":"d"}}`},
+		{"empty patch", `{"a":"b"}`, `{}`, `{"a":"b"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ApplyMergePatch([]byte(tt.original), []byte(tt.patch))
+			assert.NoError(t, err)
+			assert.JSONEq(t, tt.expected, string(result))
+		})
+	}
+}
+
+func TestApplyMergePatch_RejectsNonObjectPatch(t *testing.T) {
+	_, err := ApplyMergePatch([]byte(`{"a":"b"}`), []byte(`["a"]`))
+	assert.ErrorIs(t, err, ErrInvalidMergePatch)
+}
+
+func TestApplyMergePatch_InvalidJSON(t *testing.T) {
+	_, err := ApplyMergePatch([]byte(`{"a":"b"}`), []byte(`{invalid`))
+	assert.Error(t, err)
+}
@@ -0,0 +1,22 @@
+package common
+
+// IsPermutation reports whether ids contains every value of existing exactly once and nothing else.
+// Reorder endpoints use it to require the full list of records in their new order.
+func IsPermutation(ids, existing []string) bool {
+	if len(ids) != len(existing) {
+		return false
+	}
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			return false
+		}
+		seen[id] = true
+	}
+	for _, id := range existing {
+		if !seen[id] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,16 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsPermutation(t *testing.T) {
+	existing := []string{"a", "b"}
+
+	assert.True(t, IsPermutation([]string{"b", "a"}, existing))
+	assert.False(t, IsPermutation([]string{"a"}, existing))
+	assert.False(t, IsPermutation([]string{"a", "a"}, existing))
+	assert.False(t, IsPermutation([]string{"a", "c"}, existing))
+}
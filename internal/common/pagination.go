@@ -0,0 +1,106 @@
+package common
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Pagination defaults shared by list endpoints
+const (
+	DefaultPageLimit = 20
+	MaxPageLimit     = 100
+)
+
+// ErrInvalidCursor is returned when a pagination cursor cannot be decoded
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor identifies the last record of a page sorted by creation date, newest first.
+// The record ID breaks ties between records created at the same instant.
+type Cursor struct {
+	CreatedAt time.Time
+	ID        string
+}
+
+// Encode returns the opaque string form of the cursor
+func (c Cursor) Encode() string {
+	raw := strconv.FormatInt(c.CreatedAt.UnixNano(), 10) + "|" + c.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeCursor parses a cursor produced by Cursor.Encode
+func DecodeCursor(value string) (Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	nanos, id, found := strings.Cut(string(raw), "|")
+	if !found || id == "" {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	unixNano, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	return Cursor{CreatedAt: time.Unix(0, unixNano).UTC(), ID: id}, nil
+}
+
+// AfterCursorFilter returns the store filter selecting records that come after the
+// cursor when sorted by "-createdAt", "-_id"
+func AfterCursorFilter(cursor Cursor) []map[string]interface{} {
+	return []map[string]interface{}{
+		{"createdAt": map[string]interface{}{"$lt": cursor.CreatedAt}},
+		{"createdAt": cursor.CreatedAt, "_id": map[string]interface{}{"$lt": cursor.ID}},
+	}
+}
+
+// ParsePageLimit reads the "limit" query parameter, applying the default and maximum
+func ParsePageLimit(query url.Values) (int, error) {
+	value := query.Get("limit")
+	if value == "" {
+		return DefaultPageLimit, nil
+	}
+
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit < 1 {
+		return 0, errors.New("limit must be a positive integer")
+	}
+	if limit > MaxPageLimit {
+		limit = MaxPageLimit
+	}
+	return limit, nil
+}
+
+// ParseTimeParam reads an optional RFC 3339 timestamp query parameter
+func ParseTimeParam(query url.Values, name string) (*time.Time, error) {
+	value := query.Get(name)
+	if value == "" {
+		return nil, nil
+	}
+
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, errors.New(name + " must be an RFC 3339 timestamp")
+	}
+	return &parsed, nil
+}
+
+// ParseBoolParam reads an optional boolean query parameter
+func ParseBoolParam(query url.Values, name string) (*bool, error) {
+	value := query.Get(name)
+	if value == "" {
+		return nil, nil
+	}
+
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, errors.New(name + " must be true or false")
+	}
+	return &parsed, nil
+}
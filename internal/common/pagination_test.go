@@ -0,0 +1,90 @@
+package common
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCursor_RoundTrip(t *testing.T) {
+	cursor := Cursor{
+		CreatedAt: time.Date(2025, 3, 14, 15, 9, 26, 535897932, time.UTC),
+		ID:        "123e4567-e89b-12d3-a456-426614174000",
+	}
+
+	decoded, err := DecodeCursor(cursor.Encode())
+	assert.NoError(t, err)
+	assert.True(t, cursor.CreatedAt.Equal(decoded.CreatedAt))
+	assert.Equal(t, cursor.ID, decoded.ID)
+}
+
+func TestDecodeCursor_Invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"not base64", "%%%"},
+		{"missing separator", "MTIzNDU"},
+		{"missing id", "MTIzNDV8"},
+		{"invalid timestamp", "YWJjfGlk"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DecodeCursor(tt.input)
+			assert.ErrorIs(t, err, ErrInvalidCursor)
+		})
+	}
+}
+
+func TestParsePageLimit(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected int
+		hasError bool
+	}{
+		{"default", "", DefaultPageLimit, false},
+		{"explicit", "5", 5, false},
+		{"capped", "1000", MaxPageLimit, false},
+		{"zero", "0", 0, true},
+		{"negative", "-1", 0, true},
+		{"not a number", "ten", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limit, err := ParsePageLimit(url.Values{"limit": {tt.input}})
+			assert.Equal(t, tt.hasError, err != nil)
+			assert.Equal(t, tt.expected, limit)
+		})
+	}
+}
+
+func TestParseTimeParam(t *testing.T) {
+	value, err := ParseTimeParam(url.Values{}, "from")
+	assert.NoError(t, err)
+	assert.Nil(t, value)
+
+	value, err = ParseTimeParam(url.Values{"from": {"2025-01-02T03:04:05Z"}}, "from")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC), *value)
+
+	_, err = ParseTimeParam(url.Values{"from": {"yesterday"}}, "from")
+	assert.Error(t, err)
+}
+
+func TestParseBoolParam(t *testing.T) {
+	value, err := ParseBoolParam(url.Values{}, "contacted")
+	assert.NoError(t, err)
+	assert.Nil(t, value)
+
+	value, err = ParseBoolParam(url.Values{"contacted": {"false"}}, "contacted")
+	assert.NoError(t, err)
+	assert.False(t, *value)
+
+	_, err = ParseBoolParam(url.Values{"contacted": {"maybe"}}, "contacted")
+	assert.Error(t, err)
+}
@@ -3,8 +3,17 @@ package common
 import (
 	"encoding/json"
 	"net/http"
+	"strconv"
+	"strings"
 )
 
+// ProblemContentType is the media type of RFC 9457 problem details
+const ProblemContentType = "application/problem+json"
+
+// ProblemTypeBaseURI prefixes the problem type of every error code,
+// e.g. NOT_FOUND becomes "urn:portfolio-api:problem:not-found"
+const ProblemTypeBaseURI = "urn:portfolio-api:problem:"
+
 type ErrorResponse struct {
 	Error ErrorDetail `json:"error"`
 }
@@ -15,13 +24,45 @@ type ErrorDetail struct {
 	Details interface{} `json:"details"`
 }
 
+// ProblemDetails is the RFC 9457 representation of an error.
+// Code and Errors are extension members carrying the error code and the error details.
+type ProblemDetails struct {
+	Type     string      `json:"type"`
+	Title    string      `json:"title"`
+	Status   int         `json:"status"`
+	Detail   string      `json:"detail,omitempty"`
+	Instance string      `json:"instance,omitempty"`
+	Code     string      `json:"code"`
+	Errors   interface{} `json:"errors,omitempty"`
+}
+
 func RespondJSON(w http.ResponseWriter, status int, payload interface{}) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
 	json.NewEncoder(w).Encode(payload)
 }
 
-func RespondError(w http.ResponseWriter, status int, code, message string, details interface{}) {
+// RespondError writes an error response. Clients that prefer application/problem+json in their
+// Accept header get RFC 9457 problem details; everyone else gets the {"error": {...}} envelope.
+func RespondError(w http.ResponseWriter, r *http.Request, status int, code, message string, details interface{}) {
+	w.Header().Add("Vary", "Accept")
+
+	if r != nil && WantsProblemDetails(r) {
+		problem := ProblemDetails{
+			Type:     ProblemTypeBaseURI + strings.ToLower(strings.ReplaceAll(code, "_", "-")),
+			Title:    http.StatusText(status),
+			Status:   status,
+			Detail:   message,
+			Instance: RequestIDFromContext(r.Context()),
+			Code:     code,
+			Errors:   details,
+		}
+		w.Header().Set("Content-Type", ProblemContentType)
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(problem)
+		return
+	}
+
 	errorResponse := ErrorResponse{
 		Error: ErrorDetail{
 			Code:    code,
@@ -31,3 +72,41 @@ func RespondError(w http.ResponseWriter, status int, code, message string, detai
 	}
 	RespondJSON(w, status, errorResponse)
 }
+
+// WantsProblemDetails reports whether the Accept header of r prefers application/problem+json
+// over application/json. Wildcards do not select problem details, keeping the envelope the default.
+func WantsProblemDetails(r *http.Request) bool {
+	problemQuality, jsonQuality := -1.0, -1.0
+
+	for _, header := range r.Header.Values("Accept") {
+		for _, mediaRange := range strings.Split(header, ",") {
+			mediaType, quality := parseMediaRange(mediaRange)
+			switch mediaType {
+			case ProblemContentType:
+				problemQuality = max(problemQuality, quality)
+			case "application/json":
+				jsonQuality = max(jsonQuality, quality)
+			}
+		}
+	}
+
+	return problemQuality > 0 && problemQuality >= jsonQuality
+}
+
+// parseMediaRange returns the lowercased media type of an Accept entry and its q value
+func parseMediaRange(mediaRange string) (string, float64) {
+	parts := strings.Split(mediaRange, ";")
+	mediaType := strings.ToLower(strings.TrimSpace(parts[0]))
+	quality := 1.0
+
+	for _, param := range parts[1:] {
+		name, value, found := strings.Cut(strings.TrimSpace(param), "=")
+		if !found || strings.ToLower(strings.TrimSpace(name)) != "q" {
+			continue
+		}
+		if q, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+			quality = q
+		}
+	}
+	return mediaType, quality
+}
@@ -0,0 +1,69 @@
+package common
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWantsProblemDetails(t *testing.T) {
+	tests := []struct {
+		name     string
+		accept   string
+		expected bool
+	}{
+		{"no header", "", false},
+		{"json", "application/json", false},
+		{"wildcard", "*/*", false},
+		{"problem", "application/problem+json", true},
+		{"problem preferred", "application/json;q=0.5, application/problem+json", true},
+		{"json preferred", "application/problem+json;q=0.4, application/json", false},
+		{"problem refused", "application/problem+json;q=0", false},
+		{"case insensitive", "Application/Problem+JSON", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			assert.Equal(t, tt.expected, WantsProblemDetails(req))
+		})
+	}
+}
+
+func TestRespondError_DefaultEnvelope(t *testing.T) {
+	w := httptest.NewRecorder()
+	RespondError(w, httptest.NewRequest(http.MethodGet, "/", nil), http.StatusNotFound, "NOT_FOUND", "profile not found", nil)
+
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"error": {"code": "NOT_FOUND", "message": "profile not found", "details": null}}`, w.Body.String())
+}
+
+func TestRespondError_ProblemDetails(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/profiles", nil)
+	req.Header.Set("Accept", ProblemContentType)
+	req = req.WithContext(WithRequestID(req.Context(), "req-123"))
+	w := httptest.NewRecorder()
+
+	details := FieldErrors{{Field: "name", Rule: "required", Message: "name is a required field"}}
+	RespondError(w, req, http.StatusBadRequest, "VALIDATION_ERROR", "Validation failed", details)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, ProblemContentType, w.Header().Get("Content-Type"))
+	assert.Equal(t, "Accept", w.Header().Get("Vary"))
+
+	var problem map[string]interface{}
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&problem))
+	assert.Equal(t, "urn:portfolio-api:problem:validation-error", problem["type"])
+	assert.Equal(t, "Bad Request", problem["title"])
+	assert.Equal(t, float64(http.StatusBadRequest), problem["status"])
+	assert.Equal(t, "Validation failed", problem["detail"])
+	assert.Equal(t, "req-123", problem["instance"])
+	assert.Equal(t, "VALIDATION_ERROR", problem["code"])
+	assert.Equal(t, []interface{}{map[string]interface{}{"field": "name", "rule": "required", "message": "name is a required field"}}, problem["errors"])
+}
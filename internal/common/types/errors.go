@@ -1,5 +1,15 @@
 package types
 
+import (
+	"errors"
+	"time"
+)
+
+// The error types below classify failures so handlers can answer with the right status.
+// Each type supports errors.Is against its zero value to test the kind of error,
+// for example errors.Is(err, ErrNotFound{}), and against a populated value to test for
+// that specific error. Use errors.As to read the fields.
+
 // ErrNotFound is returned when a record is not found in the store.
 type ErrNotFound struct {
 	Message string
@@ -12,9 +22,130 @@ func (e ErrNotFound) Error() string {
 	return e.Message
 }
 
+func (e ErrNotFound) Is(target error) bool {
+	t, ok := target.(ErrNotFound)
+	return ok && (t.Message == "" || t.Message == e.Message)
+}
+
 // IsNotFoundError checks if the error is a not found error.
 func IsNotFoundError(err error) bool {
-	_, ok := err.(ErrNotFound)
-	return ok
+	return errors.Is(err, ErrNotFound{})
+}
+
+// ErrValidation is returned when the input of an operation is invalid.
+// Details, when set, is returned to the client as is.
+type ErrValidation struct {
+	Message string
+	Details interface{}
+}
+
+func (e ErrValidation) Error() string {
+	if e.Message == "" {
+		return "validation failed"
+	}
+	return e.Message
+}
+
+func (e ErrValidation) Is(target error) bool {
+	t, ok := target.(ErrValidation)
+	return ok && (t.Message == "" || t.Message == e.Message)
+}
+
+// ErrConflict is returned when an operation cannot be applied to the current state of a record.
+// Code, when set, is a machine readable reason such as "INVALID_TRANSITION".
+type ErrConflict struct {
+	Code    string
+	Message string
+}
+
+func (e ErrConflict) Error() string {
+	if e.Message == "" {
+		return "conflict with the current state"
+	}
+	return e.Message
+}
+
+func (e ErrConflict) Is(target error) bool {
+	t, ok := target.(ErrConflict)
+	return ok && (t.Message == "" || t.Message == e.Message) && (t.Code == "" || t.Code == e.Code)
 }
 
+// ErrUnavailable is returned when a dependency such as the database cannot be reached.
+// Cause keeps the underlying error for logging and is never shown to clients.
+type ErrUnavailable struct {
+	Message string
+	Cause   error
+}
+
+func (e ErrUnavailable) Error() string {
+	message := e.Message
+	if message == "" {
+		message = "service unavailable"
+	}
+	if e.Cause != nil {
+		return message + ": " + e.Cause.Error()
+	}
+	return message
+}
+
+func (e ErrUnavailable) Unwrap() error {
+	return e.Cause
+}
+
+func (e ErrUnavailable) Is(target error) bool {
+	t, ok := target.(ErrUnavailable)
+	return ok && (t.Message == "" || t.Message == e.Message)
+}
+
+// ErrUnauthenticated is returned when an operation requires an authenticated caller.
+type ErrUnauthenticated struct {
+	Message string
+}
+
+func (e ErrUnauthenticated) Error() string {
+	if e.Message == "" {
+		return "authentication required"
+	}
+	return e.Message
+}
+
+func (e ErrUnauthenticated) Is(target error) bool {
+	t, ok := target.(ErrUnauthenticated)
+	return ok && (t.Message == "" || t.Message == e.Message)
+}
+
+// ErrForbidden is returned when the caller is not allowed to perform an operation.
+type ErrForbidden struct {
+	Message string
+}
+
+func (e ErrForbidden) Error() string {
+	if e.Message == "" {
+		return "not allowed to perform this operation"
+	}
+	return e.Message
+}
+
+func (e ErrForbidden) Is(target error) bool {
+	t, ok := target.(ErrForbidden)
+	return ok && (t.Message == "" || t.Message == e.Message)
+}
+
+// ErrRateLimited is returned when the caller exceeded a rate limit.
+// RetryAfter, when set, tells the client how long to wait.
+type ErrRateLimited struct {
+	Message    string
+	RetryAfter time.Duration
+}
+
+func (e ErrRateLimited) Error() string {
+	if e.Message == "" {
+		return "rate limit exceeded"
+	}
+	return e.Message
+}
+
+func (e ErrRateLimited) Is(target error) bool {
+	t, ok := target.(ErrRateLimited)
+	return ok && (t.Message == "" || t.Message == e.Message)
+}
@@ -0,0 +1,65 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrors_IsMatchesKind(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		target error
+	}{
+		{"not found", ErrNotFound{Message: "profile not found"}, ErrNotFound{}},
+		{"validation", ErrValidation{Message: "invalid", Details: []string{"name"}}, ErrValidation{}},
+		{"conflict", ErrConflict{Code: "INVALID_TRANSITION", Message: "bad transition"}, ErrConflict{}},
+		{"unavailable", ErrUnavailable{Cause: errors.New("connection refused")}, ErrUnavailable{}},
+		{"unauthenticated", ErrUnauthenticated{}, ErrUnauthenticated{}},
+		{"forbidden", ErrForbidden{Message: "nope"}, ErrForbidden{}},
+		{"rate limited", ErrRateLimited{RetryAfter: time.Second}, ErrRateLimited{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wrapped := fmt.Errorf("loading profile: %w", tt.err)
+			assert.ErrorIs(t, wrapped, tt.target)
+			assert.NotErrorIs(t, wrapped, ErrNotFound{Message: "something else"})
+		})
+	}
+}
+
+func TestErrors_IsMatchesSpecificError(t *testing.T) {
+	errInvalidTransition := ErrConflict{Code: "INVALID_TRANSITION", Message: "invalid status transition"}
+	errLastOwner := ErrConflict{Message: "a profile must keep at least one owner"}
+
+	assert.ErrorIs(t, errInvalidTransition, errInvalidTransition)
+	assert.NotErrorIs(t, errInvalidTransition, errLastOwner)
+	assert.ErrorIs(t, errLastOwner, ErrConflict{})
+}
+
+func TestErrors_As(t *testing.T) {
+	err := fmt.Errorf("limit: %w", ErrRateLimited{RetryAfter: 30 * time.Second})
+
+	var rateLimited ErrRateLimited
+	assert.True(t, errors.As(err, &rateLimited))
+	assert.Equal(t, 30*time.Second, rateLimited.RetryAfter)
+}
+
+func TestErrUnavailable_UnwrapsCause(t *testing.T) {
+	cause := errors.New("server selection timeout")
+	err := ErrUnavailable{Message: "database unavailable", Cause: cause}
+
+	assert.ErrorIs(t, err, cause)
+	assert.Equal(t, "database unavailable: server selection timeout", err.Error())
+}
+
+func TestIsNotFoundError(t *testing.T) {
+	assert.True(t, IsNotFoundError(ErrNotFound{}))
+	assert.True(t, IsNotFoundError(fmt.Errorf("wrapped: %w", ErrNotFound{Message: "x"})))
+	assert.False(t, IsNotFoundError(errors.New("profile not found")))
+}
@@ -0,0 +1,156 @@
+package common
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/es"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	enTranslations "github.com/go-playground/validator/v10/translations/en"
+	esTranslations "github.com/go-playground/validator/v10/translations/es"
+)
+
+// DefaultLocale is used when the Accept-Language header names no supported language
+const DefaultLocale = "en"
+
+// fieldComparisonRules compare against another struct field; their param is reported by JSON name
+var fieldComparisonRules = []string{"eqfield", "nefield", "gtfield", "gtefield", "ltfield", "ltefield"}
+
+// Validator validates request structs and reports failures as FieldErrors
+// with messages in the client's language
+type Validator struct {
+	validate   *validator.Validate
+	translator *ut.UniversalTranslator
+}
+
+// NewValidator returns a validator that reports fields by their JSON names
+// and translates messages to English and Spanish
+func NewValidator() *Validator {
+	validate := validator.New()
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		if name == "" {
+			return field.Name
+		}
+		return name
+	})
+
+	english := en.New()
+	translator := ut.New(english, english, es.New())
+
+	enTranslator, _ := translator.GetTranslator("en")
+	esTranslator, _ := translator.GetTranslator("es")
+	// Registration only fails on malformed built-in translations
+	_ = enTranslations.RegisterDefaultTranslations(validate, enTranslator)
+	_ = esTranslations.RegisterDefaultTranslations(validate, esTranslator)
+
+	for _, trans := range []ut.Translator{enTranslator, esTranslator} {
+		for _, rule := range fieldComparisonRules {
+			_ = validate.RegisterTranslation(rule, trans, func(ut.Translator) error { return nil }, translateFieldComparison)
+		}
+	}
+
+	return &Validator{validate: validate, translator: translator}
+}
+
+// Validate checks s and returns FieldErrors in the language preferred by the request's
+// Accept-Language header, or nil when s is valid. r may be nil to use DefaultLocale.
+func (v *Validator) Validate(r *http.Request, s interface{}) error {
+	err := v.validate.Struct(s)
+	if err == nil {
+		return nil
+	}
+
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return err
+	}
+
+	var locales []string
+	if r != nil {
+		locales = acceptedLocales(r.Header.Get("Accept-Language"))
+	}
+	trans, _ := v.translator.FindTranslator(locales...)
+
+	fieldErrors := make(FieldErrors, 0, len(validationErrors))
+	for _, validationErr := range validationErrors {
+		fieldErrors = append(fieldErrors, FieldError{
+			Field:   fieldPath(validationErr.Namespace()),
+			Rule:    validationErr.Tag(),
+			Param:   ruleParam(validationErr),
+			Message: validationErr.Translate(trans),
+		})
+	}
+	return fieldErrors
+}
+
+// acceptedLocales lists the languages of an Accept-Language header by preference.
+// Regional tags are followed by their base language, "es-MX" also tries "es".
+func acceptedLocales(header string) []string {
+	type weighted struct {
+		tag     string
+		quality float64
+	}
+
+	var tags []weighted
+	for _, entry := range strings.Split(header, ",") {
+		tag, quality := parseMediaRange(entry)
+		if tag == "" || tag == "*" || quality <= 0 {
+			continue
+		}
+		tags = append(tags, weighted{tag: tag, quality: quality})
+	}
+	sort.SliceStable(tags, func(i, j int) bool { return tags[i].quality > tags[j].quality })
+
+	locales := make([]string, 0, len(tags)*2)
+	for _, tag := range tags {
+		locale := strings.ReplaceAll(tag.tag, "-", "_")
+		locales = append(locales, locale)
+		if base, _, found := strings.Cut(locale, "_"); found {
+			locales = append(locales, base)
+		}
+	}
+	return locales
+}
+
+// fieldPath drops the struct name from a validator namespace, "Request.category" becomes "category"
+func fieldPath(namespace string) string {
+	if _, path, found := strings.Cut(namespace, "."); found {
+		return path
+	}
+	return namespace
+}
+
+func ruleParam(err validator.FieldError) string {
+	for _, rule := range fieldComparisonRules {
+		if err.Tag() == rule {
+			return lowerFirst(err.Param())
+		}
+	}
+	return err.Param()
+}
+
+// translateFieldComparison renders field comparison messages with the JSON name of the other field
+func translateFieldComparison(trans ut.Translator, err validator.FieldError) string {
+	message, translateErr := trans.T(err.Tag(), err.Field(), lowerFirst(err.Param()))
+	if translateErr != nil {
+		return err.Error()
+	}
+	return message
+}
+
+// lowerFirst turns a struct field name such as IssuedAt into its JSON form issuedAt
+func lowerFirst(name string) string {
+	if name == "" {
+		return name
+	}
+	return strings.ToLower(name[:1]) + name[1:]
+}
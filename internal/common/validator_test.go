@@ -0,0 +1,97 @@
+package common
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mrthoabby/portfolio-api/internal/common/types"
+)
+
+type validatedRequest struct {
+	Name      string     `json:"name" validate:"required"`
+	Level     string     `json:"level" validate:"oneof=low high"`
+	Tags      []string   `json:"tags" validate:"max=1,dive,min=2"`
+	StartsAt  *time.Time `json:"startsAt"`
+	EndsAt    *time.Time `json:"endsAt,omitempty" validate:"omitempty,gtfield=StartsAt"`
+	Ignored   string     `json:"-"`
+	NoJSONTag string     `validate:"omitempty,email"`
+}
+
+func TestValidator_Validate_Valid(t *testing.T) {
+	err := NewValidator().Validate(nil, validatedRequest{Name: "Go", Level: "low"})
+	assert.NoError(t, err)
+}
+
+func TestValidator_Validate_FieldErrors(t *testing.T) {
+	start := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+	end := start.Add(-time.Hour)
+
+	err := NewValidator().Validate(nil, validatedRequest{
+		Level:     "medium",
+		Tags:      []string{"a"},
+		StartsAt:  &start,
+		EndsAt:    &end,
+		NoJSONTag: "not-an-email",
+	})
+
+	var fieldErrors FieldErrors
+	assert.True(t, errors.As(err, &fieldErrors))
+	assert.ErrorIs(t, err, types.ErrValidation{})
+	assert.Equal(t, FieldErrors{
+		{Field: "name", Rule: "required", Message: "name is a required field"},
+		{Field: "level", Rule: "oneof", Param: "low high", Message: "level must be one of [low high]"},
+		{Field: "tags[0]", Rule: "min", Param: "2", Message: "tags[0] must be at least 2 characters in length"},
+		{Field: "endsAt", Rule: "gtfield", Param: "startsAt", Message: "endsAt must be greater than startsAt"},
+		{Field: "NoJSONTag", Rule: "email", Message: "NoJSONTag must be a valid email address"},
+	}, fieldErrors)
+}
+
+func TestValidator_Validate_TranslatesByAcceptLanguage(t *testing.T) {
+	validator := NewValidator()
+
+	tests := []struct {
+		name           string
+		acceptLanguage string
+		expected       string
+	}{
+		{"default", "", "name is a required field"},
+		{"spanish", "es", "name es un campo requerido"},
+		{"regional spanish", "es-MX,en;q=0.5", "name es un campo requerido"},
+		{"english preferred", "es;q=0.3, en", "name is a required field"},
+		{"unsupported", "fr", "name is a required field"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			if tt.acceptLanguage != "" {
+				req.Header.Set("Accept-Language", tt.acceptLanguage)
+			}
+
+			err := validator.Validate(req, validatedRequest{Level: "low"})
+
+			var fieldErrors FieldErrors
+			assert.True(t, errors.As(err, &fieldErrors))
+			assert.Equal(t, tt.expected, fieldErrors[0].Message)
+		})
+	}
+}
+
+func TestValidator_Validate_NotAStruct(t *testing.T) {
+	err := NewValidator().Validate(nil, "just a string")
+
+	var fieldErrors FieldErrors
+	assert.Error(t, err)
+	assert.False(t, errors.As(err, &fieldErrors))
+}
+
+func TestAcceptedLocales(t *testing.T) {
+	assert.Equal(t, []string{"es_mx", "es", "en"}, acceptedLocales("en;q=0.5, es-MX"))
+	assert.Empty(t, acceptedLocales(""))
+	assert.Empty(t, acceptedLocales("*, de;q=0"))
+}
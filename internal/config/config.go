@@ -3,8 +3,12 @@ package config
 import (
 	"fmt"
 	"log"
+	"net/netip"
+	"net/url"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 
@@ -12,10 +16,79 @@ import (
 	"github.com/mrthoabby/portfolio-api/internal/common/scope"
 )
 
+// defaultIPv6Prefix groups IPv6 clients by /64 when rate limiting
+const defaultIPv6Prefix = 64
+
+// defaultAuthRateLimit and defaultAuthRateLimitWindow cap the requests carrying credentials per client IP
+const (
+	defaultAuthRateLimit       = 60
+	defaultAuthRateLimitWindow = "1m"
+)
+
+// CAPTCHA providers
+const (
+	CaptchaProviderTurnstile = "turnstile"
+	CaptchaProviderHCaptcha  = "hcaptcha"
+	CaptchaProviderRecaptcha = "recaptcha"
+	// CaptchaProviderFake accepts CAPTCHA_SECRET as the only valid token, outside production
+	CaptchaProviderFake = "fake"
+)
+
+// Contact form spam screening defaults
+const (
+	defaultSpamMinFillTime    = "3s"
+	defaultSpamFormTokenTTL   = "24h"
+	defaultSpamThreshold      = 5
+	defaultSpamFormTokenScore = 3
+)
+
+// defaultSMTPPort is the mail submission port
+const defaultSMTPPort = 587
+
+// Contact form email defaults
+const (
+	// defaultContactVerificationTTL is how long contact verification links can be followed
+	defaultContactVerificationTTL = "24h"
+	// defaultContactMailsPerAddress and defaultContactMailsPerIP cap the emails sent to contact senders per window
+	defaultContactMailsPerAddress = 3
+	defaultContactMailsPerIP      = 10
+	defaultContactMailLimitWindow = "1h"
+)
+
+// Webhook delivery defaults
+const (
+	defaultWebhookTimeout      = "10s"
+	defaultWebhookDisableAfter = 15
+)
+
+// Outbox dispatcher defaults
+const (
+	defaultOutboxPollInterval = "1s"
+	defaultOutboxMaxAttempts  = 10
+)
+
+const (
+	// RateLimitBackendMemory keeps rate limits per instance
+	RateLimitBackendMemory = "memory"
+	// RateLimitBackendDatastore shares rate limits between instances through the database
+	RateLimitBackendDatastore = "datastore"
+)
+
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	CORS     CORSConfig
+	Server    ServerConfig
+	Database  DatabaseConfig
+	CORS      CORSConfig
+	Auth      AuthConfig
+	Skills    SkillsConfig
+	Proxy     ProxyConfig
+	RateLimit RateLimitConfig
+	Spam      SpamConfig
+	Captcha   CaptchaConfig
+	SMTP      SMTPConfig
+	Notify    NotifyConfig
+	Contact   ContactConfig
+	Outbox    OutboxConfig
+	Webhook   WebhookConfig
 }
 
 type ServerConfig struct {
@@ -31,6 +104,132 @@ type CORSConfig struct {
 	AllowedOrigins []string
 }
 
+type AuthConfig struct {
+	// JWTSecret enables HS256 bearer tokens signed with this shared secret
+	JWTSecret string
+	// JWTPublicKeyFile enables RS256 bearer tokens verified with this PEM encoded public key
+	JWTPublicKeyFile string
+	// JWTIssuer and JWTAudience, when set, must match the token "iss" and "aud" claims
+	JWTIssuer   string
+	JWTAudience string
+	// SuperadminSubjects may manage every profile, including creating new ones
+	SuperadminSubjects []string
+}
+
+type SkillsConfig struct {
+	// Categories overrides the default skill categories when set
+	Categories []string
+}
+
+type ProxyConfig struct {
+	// TrustedProxies lists the load balancers and reverse proxies whose forwarding headers are honored
+	TrustedProxies []netip.Prefix
+}
+
+type RateLimitConfig struct {
+	// IPv6Prefix is the prefix length IPv6 clients are grouped by when rate limiting
+	IPv6Prefix int
+	// Backend is where rate limit state is kept: RateLimitBackendMemory or RateLimitBackendDatastore
+	Backend string
+	// Policies are evaluated in order; a request is rejected by the first one it exceeds
+	Policies []RateLimitPolicy
+	// Auth caps the requests carrying credentials per client IP, before they are checked
+	Auth RateLimitQuota
+}
+
+type SpamConfig struct {
+	// FormSecret signs contact form tokens; every instance must share it
+	FormSecret string
+	// MinFillTime rejects submissions made sooner after the form was rendered
+	MinFillTime time.Duration
+	// FormTokenTTL is how long a rendered form may be submitted
+	FormTokenTTL time.Duration
+	// Threshold is the content score from which a submission is stored as spam
+	Threshold int
+	// FormTokenScore is added to the score of submissions without a valid form token; 0 ignores the token
+	FormTokenScore int
+	// Phrases overrides the default spam phrases when set
+	Phrases []string
+}
+
+type CaptchaConfig struct {
+	// Provider verifies challenge responses; empty disables challenges
+	Provider string
+	// Secret is the provider secret key, or the accepted token for CaptchaProviderFake
+	Secret string
+	// Profiles lists the profiles whose public forms require a challenge; "*" enables every profile
+	Profiles []string
+	// MinScore rejects scored challenges, such as reCAPTCHA v3, below this score
+	MinScore float64
+}
+
+type SMTPConfig struct {
+	// Host is the mail server; empty disables email notifications
+	Host     string
+	Port     int
+	Username string
+	Password string
+	// From is the sender address, optionally with a display name
+	From string
+	// StartTLS upgrades the connection before authenticating and refuses servers that cannot
+	StartTLS bool
+}
+
+type NotifyConfig struct {
+	// EmailTo lists the addresses notified about new contacts and questions of the profiles
+	// whose settings do not name their own
+	EmailTo []string
+}
+
+type ContactConfig struct {
+	// AutoReply emails contact senders to confirm their message was received
+	AutoReply bool
+	// Verification holds contacts until their sender follows the link emailed to them
+	Verification bool
+	// VerificationSecret signs verification links; every instance must share it
+	VerificationSecret string
+	// VerificationTTL is how long a verification link can be followed
+	VerificationTTL time.Duration
+	// VerifyURL is the public address of GET /api/v1/contacts/verify the links point to
+	VerifyURL string
+	// MailsPerAddress and MailsPerIP cap the auto-replies and verification emails sent per
+	// MailLimitWindow to an address, and requested from a client IP
+	MailsPerAddress int
+	MailsPerIP      int
+	MailLimitWindow time.Duration
+}
+
+type OutboxConfig struct {
+	// PollInterval is how often pending events are looked for
+	PollInterval time.Duration
+	// MaxAttempts is the number of failed deliveries after which an event is dead-lettered
+	MaxAttempts int
+}
+
+type WebhookConfig struct {
+	// Timeout bounds each delivery
+	Timeout time.Duration
+	// DisableAfter is the number of failed deliveries in a row after which a webhook is disabled
+	DisableAfter int
+	// AllowInsecure accepts http URLs and private network targets, for local development only
+	AllowInsecure bool
+}
+
+// Enabled reports whether a mail server is configured
+func (c SMTPConfig) Enabled() bool {
+	return c.Host != ""
+}
+
+// Enabled reports whether challenges are configured
+func (c CaptchaConfig) Enabled() bool {
+	return c.Provider != ""
+}
+
+// JWTEnabled reports whether bearer token authentication is configured
+func (c AuthConfig) JWTEnabled() bool {
+	return c.JWTSecret != "" || c.JWTPublicKeyFile != ""
+}
+
 // Load loads configuration from environment variables.
 // Required variables: DATABASE_URL, DATABASE_NAME, ALLOWED_ORIGINS, PORT
 // This function uses the standard log package for backward compatibility.
@@ -41,6 +240,15 @@ func Load() (*Config, error) {
 // LoadWithLogger loads configuration from environment variables using the provided logger.
 // If logger is nil, it falls back to the standard log package.
 // Required variables: DATABASE_URL, DATABASE_NAME, ALLOWED_ORIGINS, PORT
+// Optional variables: AUTH_JWT_SECRET, AUTH_JWT_PUBLIC_KEY_FILE, AUTH_JWT_ISSUER, AUTH_JWT_AUDIENCE,
+// AUTH_SUPERADMIN_SUBJECTS, SKILL_CATEGORIES, TRUSTED_PROXIES, RATE_LIMIT_IPV6_PREFIX, RATE_LIMIT_BACKEND,
+// RATE_LIMIT_POLICIES, RATE_LIMIT_POLICIES_FILE, RATE_LIMIT_AUTH, RATE_LIMIT_AUTH_WINDOW,
+// SPAM_FORM_SECRET, SPAM_MIN_FILL_TIME, SPAM_FORM_TOKEN_TTL, SPAM_SCORE_THRESHOLD, SPAM_FORM_TOKEN_SCORE,
+// SPAM_PHRASES, CAPTCHA_PROVIDER, CAPTCHA_SECRET, CAPTCHA_MIN_SCORE, CAPTCHA_PROFILES,
+// SMTP_HOST, SMTP_PORT, SMTP_USERNAME, SMTP_PASSWORD, SMTP_FROM, SMTP_STARTTLS, NOTIFY_EMAIL_TO,
+// CONTACT_AUTO_REPLY, CONTACT_VERIFICATION, CONTACT_VERIFICATION_SECRET, CONTACT_VERIFICATION_TTL,
+// CONTACT_VERIFY_URL, CONTACT_MAIL_LIMIT_PER_ADDRESS, CONTACT_MAIL_LIMIT_PER_IP, CONTACT_MAIL_LIMIT_WINDOW,
+// OUTBOX_POLL_INTERVAL, OUTBOX_MAX_ATTEMPTS, WEBHOOK_TIMEOUT, WEBHOOK_DISABLE_AFTER, WEBHOOK_ALLOW_INSECURE
 func LoadWithLogger(appLogger logger.Logger) (*Config, error) {
 	if err := godotenv.Load(); err != nil {
 		if appLogger != nil {
@@ -109,6 +317,86 @@ func LoadWithLogger(appLogger logger.Logger) (*Config, error) {
 		return nil, fmt.Errorf("missing required environment variables: %s", strings.Join(missingVars, ", "))
 	}
 
+	trustedProxies, err := parsePrefixes(os.Getenv("TRUSTED_PROXIES"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid TRUSTED_PROXIES: %w", err)
+	}
+
+	ipv6Prefix, err := strconv.Atoi(getEnvOrDefault("RATE_LIMIT_IPV6_PREFIX", strconv.Itoa(defaultIPv6Prefix)))
+	if err != nil || ipv6Prefix < 1 || ipv6Prefix > 128 {
+		return nil, fmt.Errorf("invalid RATE_LIMIT_IPV6_PREFIX: must be a number between 1 and 128")
+	}
+
+	rateLimitBackend := getEnvOrDefault("RATE_LIMIT_BACKEND", RateLimitBackendMemory)
+	if rateLimitBackend != RateLimitBackendMemory && rateLimitBackend != RateLimitBackendDatastore {
+		return nil, fmt.Errorf("invalid RATE_LIMIT_BACKEND: must be %q or %q", RateLimitBackendMemory, RateLimitBackendDatastore)
+	}
+
+	rateLimitPolicies, err := loadRateLimitPolicies()
+	if err != nil {
+		return nil, err
+	}
+
+	authRateLimit, err := strconv.Atoi(getEnvOrDefault("RATE_LIMIT_AUTH", strconv.Itoa(defaultAuthRateLimit)))
+	if err != nil || authRateLimit < 1 {
+		return nil, fmt.Errorf("invalid RATE_LIMIT_AUTH: must be a positive number")
+	}
+
+	authRateLimitWindow, err := time.ParseDuration(getEnvOrDefault("RATE_LIMIT_AUTH_WINDOW", defaultAuthRateLimitWindow))
+	if err != nil || authRateLimitWindow <= 0 {
+		return nil, fmt.Errorf("invalid RATE_LIMIT_AUTH_WINDOW: must be a positive duration such as 1m")
+	}
+
+	spamMinFillTime, err := time.ParseDuration(getEnvOrDefault("SPAM_MIN_FILL_TIME", defaultSpamMinFillTime))
+	if err != nil || spamMinFillTime <= 0 {
+		return nil, fmt.Errorf("invalid SPAM_MIN_FILL_TIME: must be a positive duration such as 3s")
+	}
+
+	spamFormTokenTTL, err := time.ParseDuration(getEnvOrDefault("SPAM_FORM_TOKEN_TTL", defaultSpamFormTokenTTL))
+	if err != nil || spamFormTokenTTL <= 0 {
+		return nil, fmt.Errorf("invalid SPAM_FORM_TOKEN_TTL: must be a positive duration such as 24h")
+	}
+
+	spamThreshold, err := strconv.Atoi(getEnvOrDefault("SPAM_SCORE_THRESHOLD", strconv.Itoa(defaultSpamThreshold)))
+	if err != nil || spamThreshold < 1 {
+		return nil, fmt.Errorf("invalid SPAM_SCORE_THRESHOLD: must be a positive number")
+	}
+
+	spamFormTokenScore, err := strconv.Atoi(getEnvOrDefault("SPAM_FORM_TOKEN_SCORE", strconv.Itoa(defaultSpamFormTokenScore)))
+	if err != nil || spamFormTokenScore < 0 {
+		return nil, fmt.Errorf("invalid SPAM_FORM_TOKEN_SCORE: must be zero or a positive number")
+	}
+
+	captchaConfig, err := loadCaptchaConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	smtpConfig, err := loadSMTPConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	contactConfig, err := loadContactConfig(smtpConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	outboxPollInterval, err := time.ParseDuration(getEnvOrDefault("OUTBOX_POLL_INTERVAL", defaultOutboxPollInterval))
+	if err != nil || outboxPollInterval <= 0 {
+		return nil, fmt.Errorf("invalid OUTBOX_POLL_INTERVAL: must be a positive duration such as 1s")
+	}
+
+	outboxMaxAttempts, err := strconv.Atoi(getEnvOrDefault("OUTBOX_MAX_ATTEMPTS", strconv.Itoa(defaultOutboxMaxAttempts)))
+	if err != nil || outboxMaxAttempts < 1 {
+		return nil, fmt.Errorf("invalid OUTBOX_MAX_ATTEMPTS: must be a positive number")
+	}
+
+	webhookConfig, err := loadWebhookConfig()
+	if err != nil {
+		return nil, err
+	}
+
 	config := &Config{
 		Server: ServerConfig{
 			Port: port,
@@ -120,6 +408,44 @@ func LoadWithLogger(appLogger logger.Logger) (*Config, error) {
 		CORS: CORSConfig{
 			AllowedOrigins: parseOrigins(allowedOrigins),
 		},
+		Auth: AuthConfig{
+			JWTSecret:          os.Getenv("AUTH_JWT_SECRET"),
+			JWTPublicKeyFile:   os.Getenv("AUTH_JWT_PUBLIC_KEY_FILE"),
+			JWTIssuer:          os.Getenv("AUTH_JWT_ISSUER"),
+			JWTAudience:        os.Getenv("AUTH_JWT_AUDIENCE"),
+			SuperadminSubjects: parseList(os.Getenv("AUTH_SUPERADMIN_SUBJECTS")),
+		},
+		Skills: SkillsConfig{
+			Categories: parseList(os.Getenv("SKILL_CATEGORIES")),
+		},
+		Proxy: ProxyConfig{
+			TrustedProxies: trustedProxies,
+		},
+		RateLimit: RateLimitConfig{
+			IPv6Prefix: ipv6Prefix,
+			Backend:    rateLimitBackend,
+			Policies:   rateLimitPolicies,
+			Auth:       RateLimitQuota{Limit: authRateLimit, Window: authRateLimitWindow},
+		},
+		Spam: SpamConfig{
+			FormSecret:     os.Getenv("SPAM_FORM_SECRET"),
+			MinFillTime:    spamMinFillTime,
+			FormTokenTTL:   spamFormTokenTTL,
+			Threshold:      spamThreshold,
+			FormTokenScore: spamFormTokenScore,
+			Phrases:        parseList(os.Getenv("SPAM_PHRASES")),
+		},
+		Captcha: captchaConfig,
+		SMTP:    smtpConfig,
+		Notify: NotifyConfig{
+			EmailTo: parseList(os.Getenv("NOTIFY_EMAIL_TO")),
+		},
+		Contact: contactConfig,
+		Outbox: OutboxConfig{
+			PollInterval: outboxPollInterval,
+			MaxAttempts:  outboxMaxAttempts,
+		},
+		Webhook: webhookConfig,
 	}
 
 	if appLogger != nil {
@@ -134,6 +460,148 @@ func LoadWithLogger(appLogger logger.Logger) (*Config, error) {
 	return config, nil
 }
 
+func loadCaptchaConfig() (CaptchaConfig, error) {
+	captcha := CaptchaConfig{
+		Provider: strings.ToLower(os.Getenv("CAPTCHA_PROVIDER")),
+		Secret:   os.Getenv("CAPTCHA_SECRET"),
+		Profiles: parseList(getEnvOrDefault("CAPTCHA_PROFILES", "*")),
+	}
+
+	switch captcha.Provider {
+	case "":
+		return captcha, nil
+	case CaptchaProviderTurnstile, CaptchaProviderHCaptcha, CaptchaProviderRecaptcha:
+	case CaptchaProviderFake:
+		if scope.IsProduction() {
+			return captcha, fmt.Errorf("invalid CAPTCHA_PROVIDER: the fake provider cannot be used in production")
+		}
+	default:
+		return captcha, fmt.Errorf("invalid CAPTCHA_PROVIDER: must be %q, %q, %q or %q",
+			CaptchaProviderTurnstile, CaptchaProviderHCaptcha, CaptchaProviderRecaptcha, CaptchaProviderFake)
+	}
+
+	if captcha.Secret == "" {
+		return captcha, fmt.Errorf("missing CAPTCHA_SECRET for CAPTCHA_PROVIDER %q", captcha.Provider)
+	}
+
+	minScore, err := strconv.ParseFloat(getEnvOrDefault("CAPTCHA_MIN_SCORE", "0"), 64)
+	if err != nil || minScore < 0 || minScore > 1 {
+		return captcha, fmt.Errorf("invalid CAPTCHA_MIN_SCORE: must be a number between 0 and 1")
+	}
+	captcha.MinScore = minScore
+
+	return captcha, nil
+}
+
+func loadSMTPConfig() (SMTPConfig, error) {
+	smtp := SMTPConfig{
+		Host:     os.Getenv("SMTP_HOST"),
+		Username: os.Getenv("SMTP_USERNAME"),
+		Password: os.Getenv("SMTP_PASSWORD"),
+		From:     os.Getenv("SMTP_FROM"),
+	}
+	if !smtp.Enabled() {
+		return smtp, nil
+	}
+
+	port, err := strconv.Atoi(getEnvOrDefault("SMTP_PORT", strconv.Itoa(defaultSMTPPort)))
+	if err != nil || port < 1 || port > 65535 {
+		return smtp, fmt.Errorf("invalid SMTP_PORT: must be a port number")
+	}
+	smtp.Port = port
+
+	startTLS, err := strconv.ParseBool(getEnvOrDefault("SMTP_STARTTLS", "true"))
+	if err != nil {
+		return smtp, fmt.Errorf("invalid SMTP_STARTTLS: must be true or false")
+	}
+	smtp.StartTLS = startTLS
+
+	if smtp.From == "" {
+		return smtp, fmt.Errorf("missing SMTP_FROM for SMTP_HOST")
+	}
+
+	return smtp, nil
+}
+
+// loadContactConfig reads the contact sender emails, which need a mail server
+func loadContactConfig(smtp SMTPConfig) (ContactConfig, error) {
+	contact := ContactConfig{
+		VerificationSecret: os.Getenv("CONTACT_VERIFICATION_SECRET"),
+		VerifyURL:          os.Getenv("CONTACT_VERIFY_URL"),
+	}
+
+	autoReply, err := strconv.ParseBool(getEnvOrDefault("CONTACT_AUTO_REPLY", "false"))
+	if err != nil {
+		return contact, fmt.Errorf("invalid CONTACT_AUTO_REPLY: must be true or false")
+	}
+	contact.AutoReply = autoReply && smtp.Enabled()
+
+	contact.MailsPerAddress, err = strconv.Atoi(getEnvOrDefault("CONTACT_MAIL_LIMIT_PER_ADDRESS", strconv.Itoa(defaultContactMailsPerAddress)))
+	if err != nil || contact.MailsPerAddress < 1 {
+		return contact, fmt.Errorf("invalid CONTACT_MAIL_LIMIT_PER_ADDRESS: must be a positive number")
+	}
+	contact.MailsPerIP, err = strconv.Atoi(getEnvOrDefault("CONTACT_MAIL_LIMIT_PER_IP", strconv.Itoa(defaultContactMailsPerIP)))
+	if err != nil || contact.MailsPerIP < 1 {
+		return contact, fmt.Errorf("invalid CONTACT_MAIL_LIMIT_PER_IP: must be a positive number")
+	}
+	contact.MailLimitWindow, err = time.ParseDuration(getEnvOrDefault("CONTACT_MAIL_LIMIT_WINDOW", defaultContactMailLimitWindow))
+	if err != nil || contact.MailLimitWindow <= 0 {
+		return contact, fmt.Errorf("invalid CONTACT_MAIL_LIMIT_WINDOW: must be a positive duration such as 1h")
+	}
+
+	verification, err := strconv.ParseBool(getEnvOrDefault("CONTACT_VERIFICATION", "false"))
+	if err != nil {
+		return contact, fmt.Errorf("invalid CONTACT_VERIFICATION: must be true or false")
+	}
+	if !verification {
+		return contact, nil
+	}
+	contact.Verification = true
+
+	if !smtp.Enabled() {
+		return contact, fmt.Errorf("missing SMTP_HOST for CONTACT_VERIFICATION")
+	}
+	verifyURL, err := url.Parse(contact.VerifyURL)
+	if err != nil || (verifyURL.Scheme != "https" && verifyURL.Scheme != "http") || verifyURL.Host == "" {
+		return contact, fmt.Errorf("invalid CONTACT_VERIFY_URL: must be the absolute URL of /api/v1/contacts/verify")
+	}
+
+	ttl, err := time.ParseDuration(getEnvOrDefault("CONTACT_VERIFICATION_TTL", defaultContactVerificationTTL))
+	if err != nil || ttl <= 0 {
+		return contact, fmt.Errorf("invalid CONTACT_VERIFICATION_TTL: must be a positive duration such as 24h")
+	}
+	contact.VerificationTTL = ttl
+
+	return contact, nil
+}
+
+func loadWebhookConfig() (WebhookConfig, error) {
+	var webhook WebhookConfig
+
+	timeout, err := time.ParseDuration(getEnvOrDefault("WEBHOOK_TIMEOUT", defaultWebhookTimeout))
+	if err != nil || timeout <= 0 {
+		return webhook, fmt.Errorf("invalid WEBHOOK_TIMEOUT: must be a positive duration such as 10s")
+	}
+	webhook.Timeout = timeout
+
+	disableAfter, err := strconv.Atoi(getEnvOrDefault("WEBHOOK_DISABLE_AFTER", strconv.Itoa(defaultWebhookDisableAfter)))
+	if err != nil || disableAfter < 1 {
+		return webhook, fmt.Errorf("invalid WEBHOOK_DISABLE_AFTER: must be a positive number")
+	}
+	webhook.DisableAfter = disableAfter
+
+	allowInsecure, err := strconv.ParseBool(getEnvOrDefault("WEBHOOK_ALLOW_INSECURE", "false"))
+	if err != nil {
+		return webhook, fmt.Errorf("invalid WEBHOOK_ALLOW_INSECURE: must be true or false")
+	}
+	if allowInsecure && scope.IsProduction() {
+		return webhook, fmt.Errorf("invalid WEBHOOK_ALLOW_INSECURE: insecure webhooks cannot be used in production")
+	}
+	webhook.AllowInsecure = allowInsecure
+
+	return webhook, nil
+}
+
 func getEnvOrDefault(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -141,6 +609,39 @@ func getEnvOrDefault(key, defaultValue string) string {
 	return defaultValue
 }
 
+// parseList splits a comma separated value, trimming whitespace and dropping empty items
+func parseList(value string) []string {
+	items := []string{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parsePrefixes parses a comma separated list of CIDRs; bare addresses are read as single-host prefixes
+func parsePrefixes(value string) ([]netip.Prefix, error) {
+	prefixes := []netip.Prefix{}
+	for _, item := range parseList(value) {
+		if !strings.Contains(item, "/") {
+			addr, err := netip.ParseAddr(item)
+			if err != nil {
+				return nil, err
+			}
+			prefixes = append(prefixes, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+			continue
+		}
+
+		prefix, err := netip.ParsePrefix(item)
+		if err != nil {
+			return nil, err
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
+}
+
 func parseOrigins(origins string) []string {
 	if origins == "" {
 		return []string{}
@@ -1,8 +1,10 @@
 package config
 
 import (
+	"net/netip"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -127,6 +129,32 @@ func TestParseOrigins(t *testing.T) {
 	}
 }
 
+func TestParseList(t *testing.T) {
+	assert.Equal(t, []string{"a", "b"}, parseList(" a , ,b,"))
+	assert.Equal(t, []string{}, parseList(""))
+}
+
+func TestParsePrefixes(t *testing.T) {
+	prefixes, err := parsePrefixes("10.0.0.0/8, 192.168.1.7, 2001:db8::/32, 172.16.5.9/12")
+	assert.NoError(t, err)
+	assert.Equal(t, []netip.Prefix{
+		netip.MustParsePrefix("10.0.0.0/8"),
+		netip.MustParsePrefix("192.168.1.7/32"),
+		netip.MustParsePrefix("2001:db8::/32"),
+		netip.MustParsePrefix("172.16.0.0/12"),
+	}, prefixes)
+
+	prefixes, err = parsePrefixes("")
+	assert.NoError(t, err)
+	assert.Empty(t, prefixes)
+
+	_, err = parsePrefixes("10.0.0.0/8, proxy.internal")
+	assert.Error(t, err)
+
+	_, err = parsePrefixes("10.0.0.0/33")
+	assert.Error(t, err)
+}
+
 func TestGetEnvOrDefault(t *testing.T) {
 	os.Setenv("TEST_KEY", "test-value")
 	defer os.Unsetenv("TEST_KEY")
@@ -367,3 +395,242 @@ func TestLoad_WithProdVariation(t *testing.T) {
 	assert.NotNil(t, config)
 	assert.True(t, scope.IsProduction())
 }
+
+func TestLoadCaptchaConfig(t *testing.T) {
+	t.Setenv("CAPTCHA_PROVIDER", "")
+	t.Setenv("CAPTCHA_SECRET", "")
+	t.Setenv("CAPTCHA_PROFILES", "")
+	t.Setenv("CAPTCHA_MIN_SCORE", "")
+
+	captcha, err := loadCaptchaConfig()
+	assert.NoError(t, err)
+	assert.False(t, captcha.Enabled())
+
+	t.Setenv("CAPTCHA_PROVIDER", "Turnstile")
+	_, err = loadCaptchaConfig()
+	assert.Error(t, err, "secret required")
+
+	t.Setenv("CAPTCHA_SECRET", "secret")
+	captcha, err = loadCaptchaConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, CaptchaConfig{Provider: CaptchaProviderTurnstile, Secret: "secret", Profiles: []string{"*"}}, captcha)
+
+	t.Setenv("CAPTCHA_PROVIDER", CaptchaProviderRecaptcha)
+	t.Setenv("CAPTCHA_PROFILES", "p1, p2")
+	t.Setenv("CAPTCHA_MIN_SCORE", "0.5")
+	captcha, err = loadCaptchaConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"p1", "p2"}, captcha.Profiles)
+	assert.Equal(t, 0.5, captcha.MinScore)
+
+	t.Setenv("CAPTCHA_MIN_SCORE", "2")
+	_, err = loadCaptchaConfig()
+	assert.Error(t, err)
+
+	t.Setenv("CAPTCHA_MIN_SCORE", "")
+	t.Setenv("CAPTCHA_PROVIDER", "friendlycaptcha")
+	_, err = loadCaptchaConfig()
+	assert.Error(t, err)
+}
+
+func TestLoadCaptchaConfig_FakeProvider(t *testing.T) {
+	t.Setenv("CAPTCHA_PROVIDER", CaptchaProviderFake)
+	t.Setenv("CAPTCHA_SECRET", "test-token")
+
+	t.Setenv("ENV", "test")
+	scope.Initialize()
+	_, err := loadCaptchaConfig()
+	assert.NoError(t, err)
+
+	t.Setenv("ENV", "production")
+	scope.Initialize()
+	defer func() {
+		os.Unsetenv("ENV")
+		scope.Initialize()
+	}()
+	_, err = loadCaptchaConfig()
+	assert.Error(t, err)
+}
+
+func TestLoadSMTPConfig(t *testing.T) {
+	t.Setenv("SMTP_HOST", "")
+	t.Setenv("SMTP_PORT", "")
+	t.Setenv("SMTP_USERNAME", "")
+	t.Setenv("SMTP_PASSWORD", "")
+	t.Setenv("SMTP_FROM", "")
+	t.Setenv("SMTP_STARTTLS", "")
+
+	smtp, err := loadSMTPConfig()
+	assert.NoError(t, err)
+	assert.False(t, smtp.Enabled())
+
+	t.Setenv("SMTP_HOST", "smtp.example.com")
+	_, err = loadSMTPConfig()
+	assert.Error(t, err, "sender required")
+
+	t.Setenv("SMTP_FROM", "Portfolio <noreply@example.com>")
+	smtp, err = loadSMTPConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, SMTPConfig{Host: "smtp.example.com", Port: 587, From: "Portfolio <noreply@example.com>", StartTLS: true}, smtp)
+
+	t.Setenv("SMTP_PORT", "2525")
+	t.Setenv("SMTP_STARTTLS", "false")
+	smtp, err = loadSMTPConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, 2525, smtp.Port)
+	assert.False(t, smtp.StartTLS)
+
+	t.Setenv("SMTP_PORT", "70000")
+	_, err = loadSMTPConfig()
+	assert.Error(t, err)
+
+	t.Setenv("SMTP_PORT", "")
+	t.Setenv("SMTP_STARTTLS", "maybe")
+	_, err = loadSMTPConfig()
+	assert.Error(t, err)
+}
+
+func TestLoad_OutboxConfig(t *testing.T) {
+	os.Clearenv()
+	os.Setenv("PORT", "8080")
+	os.Setenv("DATABASE_URL", "mongodb://localhost:27017")
+	os.Setenv("DATABASE_NAME", "test_db")
+	os.Setenv("ALLOWED_ORIGINS", "http://localhost:3000")
+	defer os.Clearenv()
+
+	config, err := Load()
+	assert.NoError(t, err)
+	assert.Equal(t, OutboxConfig{PollInterval: time.Second, MaxAttempts: 10}, config.Outbox)
+
+	os.Setenv("OUTBOX_POLL_INTERVAL", "250ms")
+	os.Setenv("OUTBOX_MAX_ATTEMPTS", "3")
+	config, err = Load()
+	assert.NoError(t, err)
+	assert.Equal(t, OutboxConfig{PollInterval: 250 * time.Millisecond, MaxAttempts: 3}, config.Outbox)
+
+	os.Setenv("OUTBOX_MAX_ATTEMPTS", "0")
+	_, err = Load()
+	assert.Error(t, err)
+
+	os.Setenv("OUTBOX_MAX_ATTEMPTS", "3")
+	os.Setenv("OUTBOX_POLL_INTERVAL", "0s")
+	_, err = Load()
+	assert.Error(t, err)
+}
+
+func TestLoad_AuthRateLimit(t *testing.T) {
+	os.Clearenv()
+	os.Setenv("PORT", "8080")
+	os.Setenv("DATABASE_URL", "mongodb://localhost:27017")
+	os.Setenv("DATABASE_NAME", "test_db")
+	os.Setenv("ALLOWED_ORIGINS", "http://localhost:3000")
+	defer os.Clearenv()
+
+	config, err := Load()
+	assert.NoError(t, err)
+	assert.Equal(t, RateLimitQuota{Limit: 60, Window: time.Minute}, config.RateLimit.Auth)
+
+	os.Setenv("RATE_LIMIT_AUTH", "20")
+	os.Setenv("RATE_LIMIT_AUTH_WINDOW", "10s")
+	config, err = Load()
+	assert.NoError(t, err)
+	assert.Equal(t, RateLimitQuota{Limit: 20, Window: 10 * time.Second}, config.RateLimit.Auth)
+
+	os.Setenv("RATE_LIMIT_AUTH", "0")
+	_, err = Load()
+	assert.Error(t, err)
+
+	os.Setenv("RATE_LIMIT_AUTH", "20")
+	os.Setenv("RATE_LIMIT_AUTH_WINDOW", "soon")
+	_, err = Load()
+	assert.Error(t, err)
+}
+
+func TestLoadWebhookConfig(t *testing.T) {
+	t.Setenv("WEBHOOK_TIMEOUT", "")
+	t.Setenv("WEBHOOK_DISABLE_AFTER", "")
+	t.Setenv("WEBHOOK_ALLOW_INSECURE", "")
+
+	webhook, err := loadWebhookConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, WebhookConfig{Timeout: 10 * time.Second, DisableAfter: 15}, webhook)
+
+	t.Setenv("WEBHOOK_TIMEOUT", "5s")
+	t.Setenv("WEBHOOK_DISABLE_AFTER", "3")
+	t.Setenv("WEBHOOK_ALLOW_INSECURE", "true")
+	t.Setenv("ENV", "local")
+	scope.Initialize()
+	defer func() {
+		os.Unsetenv("ENV")
+		scope.Initialize()
+	}()
+	webhook, err = loadWebhookConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, WebhookConfig{Timeout: 5 * time.Second, DisableAfter: 3, AllowInsecure: true}, webhook)
+
+	t.Setenv("ENV", "production")
+	scope.Initialize()
+	_, err = loadWebhookConfig()
+	assert.Error(t, err, "insecure webhooks are refused in production")
+
+	t.Setenv("WEBHOOK_ALLOW_INSECURE", "false")
+	t.Setenv("WEBHOOK_DISABLE_AFTER", "0")
+	_, err = loadWebhookConfig()
+	assert.Error(t, err)
+}
+
+func TestLoadContactConfig(t *testing.T) {
+	t.Setenv("CONTACT_AUTO_REPLY", "")
+	t.Setenv("CONTACT_VERIFICATION", "")
+	t.Setenv("CONTACT_VERIFICATION_SECRET", "")
+	t.Setenv("CONTACT_VERIFICATION_TTL", "")
+	t.Setenv("CONTACT_VERIFY_URL", "")
+	t.Setenv("CONTACT_MAIL_LIMIT_PER_ADDRESS", "")
+	t.Setenv("CONTACT_MAIL_LIMIT_PER_IP", "")
+	t.Setenv("CONTACT_MAIL_LIMIT_WINDOW", "")
+	mailServer := SMTPConfig{Host: "smtp.example.com"}
+	mailLimits := ContactConfig{MailsPerAddress: 3, MailsPerIP: 10, MailLimitWindow: time.Hour}
+
+	contact, err := loadContactConfig(mailServer)
+	assert.NoError(t, err)
+	assert.Equal(t, mailLimits, contact, "auto-replies are opt-in")
+
+	t.Setenv("CONTACT_AUTO_REPLY", "true")
+	contact, err = loadContactConfig(SMTPConfig{})
+	assert.NoError(t, err)
+	assert.False(t, contact.AutoReply, "auto-replies need a mail server")
+
+	contact, err = loadContactConfig(mailServer)
+	assert.NoError(t, err)
+	assert.True(t, contact.AutoReply)
+
+	t.Setenv("CONTACT_MAIL_LIMIT_PER_ADDRESS", "0")
+	_, err = loadContactConfig(mailServer)
+	assert.Error(t, err)
+	t.Setenv("CONTACT_MAIL_LIMIT_PER_ADDRESS", "")
+
+	t.Setenv("CONTACT_VERIFICATION", "true")
+	_, err = loadContactConfig(SMTPConfig{})
+	assert.Error(t, err, "verification needs a mail server")
+	_, err = loadContactConfig(mailServer)
+	assert.Error(t, err, "verification needs the verify URL")
+
+	t.Setenv("CONTACT_VERIFY_URL", "https://api.example.com/api/v1/contacts/verify")
+	t.Setenv("CONTACT_VERIFICATION_SECRET", "secret")
+	t.Setenv("CONTACT_AUTO_REPLY", "false")
+	contact, err = loadContactConfig(mailServer)
+	assert.NoError(t, err)
+	assert.Equal(t, ContactConfig{
+		Verification:       true,
+		VerificationSecret: "secret",
+		VerificationTTL:    24 * time.Hour,
+		VerifyURL:          "https://api.example.com/api/v1/contacts/verify",
+		MailsPerAddress:    3,
+		MailsPerIP:         10,
+		MailLimitWindow:    time.Hour,
+	}, contact)
+
+	t.Setenv("CONTACT_VERIFICATION_TTL", "-1h")
+	_, err = loadContactConfig(mailServer)
+	assert.Error(t, err)
+}
@@ -0,0 +1,180 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+// RateLimitPolicy limits the requests matching Route, counted per Key
+type RateLimitPolicy struct {
+	// Name identifies the policy; requests are counted separately per policy
+	Name string
+	// Route is "*" for every request, or an optional method followed by a path pattern where {param}
+	// matches one segment and a trailing /* any remaining ones, e.g. "POST /api/v1/profiles/{id}/contacts"
+	Route string
+	// Key is what requests are counted by: "ip", "apiKey", "profile" or "email"
+	Key string
+	RateLimitQuota
+	// Authenticated, when set, replaces the quota for authenticated callers
+	Authenticated *RateLimitQuota
+}
+
+// RateLimitQuota allows Limit requests per Window plus Burst requests on top
+type RateLimitQuota struct {
+	Limit  int
+	Window time.Duration
+	Burst  int
+}
+
+// DefaultRateLimitPolicies apply when neither RATE_LIMIT_POLICIES nor RATE_LIMIT_POLICIES_FILE is set
+func DefaultRateLimitPolicies() []RateLimitPolicy {
+	return []RateLimitPolicy{
+		{
+			Name:           "global",
+			Route:          "*",
+			Key:            "ip",
+			RateLimitQuota: RateLimitQuota{Limit: 100, Window: time.Minute},
+		},
+		{
+			Name:           "contacts",
+			Route:          "POST /api/v1/profiles/{id}/contacts",
+			Key:            "ip",
+			RateLimitQuota: RateLimitQuota{Limit: 5, Window: time.Minute},
+		},
+		{
+			Name:           "questions",
+			Route:          "POST /api/v1/profiles/{id}/questions",
+			Key:            "ip",
+			RateLimitQuota: RateLimitQuota{Limit: 10, Window: time.Minute},
+		},
+	}
+}
+
+type rateLimitQuotaJSON struct {
+	Limit  int    `json:"limit"`
+	Window string `json:"window"`
+	Burst  int    `json:"burst"`
+}
+
+type rateLimitPolicyJSON struct {
+	Name  string `json:"name"`
+	Route string `json:"route"`
+	Key   string `json:"key"`
+	rateLimitQuotaJSON
+	Authenticated *rateLimitQuotaJSON `json:"authenticated"`
+}
+
+func (q rateLimitQuotaJSON) quota() (RateLimitQuota, error) {
+	window, err := time.ParseDuration(q.Window)
+	if err != nil {
+		return RateLimitQuota{}, fmt.Errorf("invalid window %q", q.Window)
+	}
+	if q.Limit < 1 || window <= 0 || q.Burst < 0 {
+		return RateLimitQuota{}, fmt.Errorf("limit and window must be positive and burst not negative")
+	}
+	return RateLimitQuota{Limit: q.Limit, Window: window, Burst: q.Burst}, nil
+}
+
+// loadRateLimitPolicies reads the policies from RATE_LIMIT_POLICIES or the file named by
+// RATE_LIMIT_POLICIES_FILE, falling back to DefaultRateLimitPolicies
+func loadRateLimitPolicies() ([]RateLimitPolicy, error) {
+	inline := os.Getenv("RATE_LIMIT_POLICIES")
+	file := os.Getenv("RATE_LIMIT_POLICIES_FILE")
+
+	switch {
+	case inline != "" && file != "":
+		return nil, fmt.Errorf("RATE_LIMIT_POLICIES and RATE_LIMIT_POLICIES_FILE are mutually exclusive")
+	case inline != "":
+		policies, err := parseRateLimitPolicies([]byte(inline))
+		if err != nil {
+			return nil, fmt.Errorf("invalid RATE_LIMIT_POLICIES: %w", err)
+		}
+		return policies, nil
+	case file != "":
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read RATE_LIMIT_POLICIES_FILE: %w", err)
+		}
+		policies, err := parseRateLimitPolicies(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid RATE_LIMIT_POLICIES_FILE: %w", err)
+		}
+		return policies, nil
+	default:
+		return DefaultRateLimitPolicies(), nil
+	}
+}
+
+// parseRateLimitPolicies parses a JSON array of policies, e.g.
+// [{"name":"contacts","route":"POST /api/v1/profiles/{id}/contacts","key":"ip","limit":5,"window":"1m","burst":2}]
+func parseRateLimitPolicies(data []byte) ([]RateLimitPolicy, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	var raw []rateLimitPolicyJSON
+	if err := decoder.Decode(&raw); err != nil {
+		return nil, err
+	}
+
+	policies := make([]RateLimitPolicy, 0, len(raw))
+	names := map[string]bool{}
+	for i, item := range raw {
+		if item.Name == "" {
+			return nil, fmt.Errorf("policy %d: name is required", i)
+		}
+		if names[item.Name] {
+			return nil, fmt.Errorf("policy %q: duplicate name", item.Name)
+		}
+		names[item.Name] = true
+
+		if item.Route == "" {
+			return nil, fmt.Errorf("policy %q: route is required", item.Name)
+		}
+		switch item.Key {
+		case "ip", "apiKey", "profile", "email":
+		default:
+			return nil, fmt.Errorf("policy %q: key must be one of ip, apiKey, profile or email", item.Name)
+		}
+		if err := validateRoute(item.Route, item.Key); err != nil {
+			return nil, fmt.Errorf("policy %q: %w", item.Name, err)
+		}
+
+		quota, err := item.rateLimitQuotaJSON.quota()
+		if err != nil {
+			return nil, fmt.Errorf("policy %q: %w", item.Name, err)
+		}
+		policy := RateLimitPolicy{Name: item.Name, Route: item.Route, Key: item.Key, RateLimitQuota: quota}
+
+		if item.Authenticated != nil {
+			authenticated, err := item.Authenticated.quota()
+			if err != nil {
+				return nil, fmt.Errorf("policy %q: authenticated: %w", item.Name, err)
+			}
+			policy.Authenticated = &authenticated
+		}
+
+		policies = append(policies, policy)
+	}
+	return policies, nil
+}
+
+// validateRoute checks that route is "*" or an optional method followed by a path, and that
+// policies counting requests per profile find it in an {id} route parameter
+func validateRoute(route, key string) error {
+	path := strings.TrimSpace(route)
+	if _, afterMethod, found := strings.Cut(path, " "); found {
+		path = strings.TrimSpace(afterMethod)
+	}
+	if path != "*" && !strings.HasPrefix(path, "/") {
+		return fmt.Errorf("route must be \"*\" or start with /")
+	}
+	if key == "profile" && !slices.Contains(strings.Split(path, "/"), "{id}") {
+		return fmt.Errorf("key profile requires an {id} route parameter")
+	}
+	return nil
+}
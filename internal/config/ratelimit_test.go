@@ -0,0 +1,85 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRateLimitPolicies(t *testing.T) {
+	policies, err := parseRateLimitPolicies([]byte(`[
+		{"name":"global","route":"*","key":"ip","limit":100,"window":"1m"},
+		{"name":"contacts","route":"POST /api/v1/profiles/{id}/contacts","key":"email","limit":3,"window":"1h","burst":2,
+		 "authenticated":{"limit":50,"window":"1m"}}
+	]`))
+	assert.NoError(t, err)
+	assert.Equal(t, []RateLimitPolicy{
+		{Name: "global", Route: "*", Key: "ip", RateLimitQuota: RateLimitQuota{Limit: 100, Window: time.Minute}},
+		{
+			Name:           "contacts",
+			Route:          "POST /api/v1/profiles/{id}/contacts",
+			Key:            "email",
+			RateLimitQuota: RateLimitQuota{Limit: 3, Window: time.Hour, Burst: 2},
+			Authenticated:  &RateLimitQuota{Limit: 50, Window: time.Minute},
+		},
+	}, policies)
+}
+
+func TestParseRateLimitPolicies_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+	}{
+		{name: "not an array", json: `{"name":"global"}`},
+		{name: "unknown field", json: `[{"name":"global","route":"*","key":"ip","limit":1,"window":"1m","rate":5}]`},
+		{name: "missing name", json: `[{"route":"*","key":"ip","limit":1,"window":"1m"}]`},
+		{name: "duplicate name", json: `[{"name":"a","route":"*","key":"ip","limit":1,"window":"1m"},{"name":"a","route":"*","key":"ip","limit":1,"window":"1m"}]`},
+		{name: "missing route", json: `[{"name":"a","key":"ip","limit":1,"window":"1m"}]`},
+		{name: "unknown key", json: `[{"name":"a","route":"*","key":"user","limit":1,"window":"1m"}]`},
+		{name: "relative route", json: `[{"name":"a","route":"api/v1","key":"ip","limit":1,"window":"1m"}]`},
+		{name: "relative route after method", json: `[{"name":"a","route":"GET api/v1","key":"ip","limit":1,"window":"1m"}]`},
+		{name: "profile key without id", json: `[{"name":"a","route":"*","key":"profile","limit":1,"window":"1m"}]`},
+		{name: "invalid window", json: `[{"name":"a","route":"*","key":"ip","limit":1,"window":"60"}]`},
+		{name: "zero limit", json: `[{"name":"a","route":"*","key":"ip","limit":0,"window":"1m"}]`},
+		{name: "negative burst", json: `[{"name":"a","route":"*","key":"ip","limit":1,"window":"1m","burst":-1}]`},
+		{name: "invalid authenticated quota", json: `[{"name":"a","route":"*","key":"ip","limit":1,"window":"1m","authenticated":{"limit":5}}]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseRateLimitPolicies([]byte(tt.json))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestLoadRateLimitPolicies(t *testing.T) {
+	os.Unsetenv("RATE_LIMIT_POLICIES")
+	os.Unsetenv("RATE_LIMIT_POLICIES_FILE")
+
+	policies, err := loadRateLimitPolicies()
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultRateLimitPolicies(), policies)
+
+	t.Setenv("RATE_LIMIT_POLICIES", `[{"name":"global","route":"*","key":"ip","limit":10,"window":"30s"}]`)
+	policies, err = loadRateLimitPolicies()
+	assert.NoError(t, err)
+	assert.Equal(t, []RateLimitPolicy{
+		{Name: "global", Route: "*", Key: "ip", RateLimitQuota: RateLimitQuota{Limit: 10, Window: 30 * time.Second}},
+	}, policies)
+
+	file := filepath.Join(t.TempDir(), "policies.json")
+	assert.NoError(t, os.WriteFile(file, []byte(`[{"name":"keys","route":"/api/v1/*","key":"apiKey","limit":1000,"window":"1m"}]`), 0o600))
+	t.Setenv("RATE_LIMIT_POLICIES_FILE", file)
+	_, err = loadRateLimitPolicies()
+	assert.Error(t, err, "both variables set")
+
+	t.Setenv("RATE_LIMIT_POLICIES", "")
+	policies, err = loadRateLimitPolicies()
+	assert.NoError(t, err)
+	assert.Equal(t, "keys", policies[0].Name)
+	assert.Equal(t, "apiKey", policies[0].Key)
+}
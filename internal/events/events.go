@@ -0,0 +1,103 @@
+// Package events describes what happens in the domains so that side effects such as
+// notifications can react to it without the domains knowing about them.
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Event types
+const (
+	TypeContactCreated               = "contact.created"
+	TypeContactVerificationRequested = "contact.verification_requested"
+	TypeQuestionCreated              = "question.created"
+	TypeProjectPublished             = "project.published"
+)
+
+// Types lists every event type
+var Types = []string{TypeContactCreated, TypeContactVerificationRequested, TypeQuestionCreated, TypeProjectPublished}
+
+// Event is something that happened to a profile. Data holds the JSON payload of the event type.
+type Event struct {
+	ID         string          `json:"id" bson:"_id"`
+	Type       string          `json:"type" bson:"type"`
+	ProfileID  string          `json:"profileId" bson:"profileId"`
+	OccurredAt time.Time       `json:"occurredAt" bson:"occurredAt"`
+	Data       json.RawMessage `json:"data" bson:"data"`
+}
+
+// ContactCreated is the payload of TypeContactCreated. When the sender had to confirm their
+// email address, it is published once they did, with Verified set.
+type ContactCreated struct {
+	ContactID string    `json:"contactId"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	Message   string    `json:"message"`
+	Spam      bool      `json:"spam"`
+	Verified  bool      `json:"verified"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// ContactVerificationRequested is the payload of TypeContactVerificationRequested, published when
+// a contact is held until its sender follows the verification link carrying Token
+type ContactVerificationRequested struct {
+	ContactID string    `json:"contactId"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	Message   string    `json:"message"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expiresAt"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// QuestionCreated is the payload of TypeQuestionCreated
+type QuestionCreated struct {
+	QuestionID string    `json:"questionId"`
+	Message    string    `json:"message"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// ProjectPublished is the payload of TypeProjectPublished, published when a project becomes visible
+type ProjectPublished struct {
+	ProjectID   string    `json:"projectId"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	TechStack   []string  `json:"techStack"`
+	GitHubURL   *string   `json:"githubUrl,omitempty"`
+	LiveURL     *string   `json:"liveUrl,omitempty"`
+	PublishedAt time.Time `json:"publishedAt"`
+}
+
+// New creates an event of the given type carrying payload
+func New(eventType, profileID string, payload interface{}) (Event, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return Event{}, err
+	}
+	return Event{
+		ID:         uuid.New().String(),
+		Type:       eventType,
+		ProfileID:  profileID,
+		OccurredAt: time.Now().UTC(),
+		Data:       data,
+	}, nil
+}
+
+// Decode reads the payload of the event into dst
+func (e Event) Decode(dst interface{}) error {
+	return json.Unmarshal(e.Data, dst)
+}
+
+// Publisher records that an event happened
+type Publisher interface {
+	Publish(ctx context.Context, event Event) error
+}
+
+// Handler reacts to events
+type Handler interface {
+	Handle(ctx context.Context, event Event) error
+}
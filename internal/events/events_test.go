@@ -0,0 +1,20 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvent_Decode(t *testing.T) {
+	event, err := New(TypeQuestionCreated, "profile-1", QuestionCreated{QuestionID: "q1", Message: "Hello?"})
+	require.NoError(t, err)
+	assert.NotEmpty(t, event.ID)
+	assert.Equal(t, "profile-1", event.ProfileID)
+
+	var payload QuestionCreated
+	require.NoError(t, event.Decode(&payload))
+	assert.Equal(t, "q1", payload.QuestionID)
+	assert.Equal(t, "Hello?", payload.Message)
+}
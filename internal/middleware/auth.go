@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/mrthoabby/portfolio-api/internal/auth"
+	"github.com/mrthoabby/portfolio-api/internal/common"
+	"github.com/mrthoabby/portfolio-api/internal/common/types"
+)
+
+// Authenticate returns a middleware that resolves the request principal and saves it to context.
+// Anonymous requests pass through untouched; requests with invalid credentials are rejected.
+// Credentials that cannot be checked, e.g. while the database is down, fail as any service error.
+func Authenticate(authenticator auth.Authenticator) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, err := authenticator.Authenticate(r)
+			if errors.Is(err, auth.ErrNoCredentials) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if errors.Is(err, auth.ErrInvalidCredentials) {
+				respondUnauthorized(w, r)
+				return
+			}
+			if err != nil {
+				common.RespondServiceError(w, r, err)
+				return
+			}
+
+			ctx := common.WithPrincipal(r.Context(), principal)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// LimitAuthentication returns a middleware that caps the requests carrying credentials per client IP.
+// It runs before Authenticate, so clients guessing API keys are throttled before any of them is
+// looked up. Anonymous requests are left to the rate limit policies.
+func LimitAuthentication(limiter *RateLimiter, quota Quota) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get(auth.APIKeyHeader) == "" && r.Header.Get("Authorization") == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ip := common.ClientIPFromContext(r.Context())
+			if ip == "" {
+				ip = getClientIP(r, nil)
+			}
+			result := limiter.Allow(r.Context(), "auth:"+rateLimitKey(ip, limiter.ipv6Prefix), quota)
+			if !result.Allowed {
+				common.RespondServiceError(w, r, types.ErrRateLimited{
+					Message:    "too many authenticated requests",
+					RetryAfter: result.RetryAfter,
+				})
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireAuth rejects requests that were not authenticated by Authenticate
+func RequireAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if common.PrincipalFromContext(r.Context()) == nil {
+			respondUnauthorized(w, r)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func respondUnauthorized(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("WWW-Authenticate", `Bearer realm="portfolio-api"`)
+	common.RespondError(w, r, http.StatusUnauthorized, "UNAUTHORIZED", "Missing or invalid credentials", nil)
+}
@@ -0,0 +1,113 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mrthoabby/portfolio-api/internal/auth"
+	"github.com/mrthoabby/portfolio-api/internal/common"
+	"github.com/mrthoabby/portfolio-api/internal/common/types"
+)
+
+type stubAuthenticator struct {
+	principal *common.Principal
+	err       error
+}
+
+func (s stubAuthenticator) Authenticate(r *http.Request) (*common.Principal, error) {
+	return s.principal, s.err
+}
+
+func TestAuthenticate_StoresPrincipal(t *testing.T) {
+	expected := &common.Principal{Subject: "user-123", Method: common.AuthMethodAPIKey}
+
+	handler := Authenticate(stubAuthenticator{principal: expected})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, expected, common.PrincipalFromContext(r.Context()))
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/test", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestAuthenticate_AnonymousPassesThrough(t *testing.T) {
+	handler := Authenticate(stubAuthenticator{err: auth.ErrNoCredentials})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Nil(t, common.PrincipalFromContext(r.Context()))
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/test", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestAuthenticate_InvalidCredentials(t *testing.T) {
+	handler := Authenticate(stubAuthenticator{err: auth.ErrInvalidCredentials})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("handler should not be called")
+	}))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/test", nil))
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	assert.NotEmpty(t, rr.Header().Get("WWW-Authenticate"))
+}
+
+func TestRequireAuth(t *testing.T) {
+	handler := RequireAuth(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/test", nil))
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req = req.WithContext(common.WithPrincipal(req.Context(), &common.Principal{Subject: "user-123"}))
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestAuthenticate_UncheckedCredentials(t *testing.T) {
+	handler := Authenticate(stubAuthenticator{err: types.ErrUnavailable{Message: "database unavailable"}})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("handler should not be called")
+	}))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/test", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+	assert.Empty(t, rr.Header().Get("WWW-Authenticate"))
+}
+
+func TestLimitAuthentication(t *testing.T) {
+	limiter := NewPolicyRateLimiter(nil)
+	defer limiter.Stop()
+
+	handler := LimitAuthentication(limiter, Quota{Limit: 1, Window: time.Minute})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	request := func(header, value string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/test", nil)
+		req.RemoteAddr = "203.0.113.1:1234"
+		if header != "" {
+			req.Header.Set(header, value)
+		}
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+
+	assert.Equal(t, http.StatusOK, request(auth.APIKeyHeader, "pk_first").Code)
+
+	rr := request(auth.APIKeyHeader, "pk_second")
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code, "every attempt counts, whatever the key")
+	assert.NotEmpty(t, rr.Header().Get("Retry-After"))
+	assert.Equal(t, http.StatusTooManyRequests, request("Authorization", "Bearer token").Code)
+
+	assert.Equal(t, http.StatusOK, request("", "").Code, "anonymous requests are not counted")
+}
@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/mrthoabby/portfolio-api/internal/captcha"
+	"github.com/mrthoabby/portfolio-api/internal/common"
+)
+
+// RequireCaptcha returns a middleware that verifies the X-Captcha-Token header of requests to the
+// profiles in profiles, taken from the {id} route parameter. A nil verifier disables the check.
+func RequireCaptcha(verifier captcha.Verifier, profiles captcha.ProfileSet) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if verifier == nil {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !profiles.Enabled(r.PathValue("id")) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			token := strings.TrimSpace(r.Header.Get(captcha.TokenHeader))
+			if token == "" {
+				common.RespondServiceError(w, r, captcha.ErrMissingToken)
+				return
+			}
+
+			if err := verifier.Verify(r.Context(), token, common.ClientIPFromContext(r.Context())); err != nil {
+				common.RespondServiceError(w, r, err)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mrthoabby/portfolio-api/internal/captcha"
+	"github.com/mrthoabby/portfolio-api/internal/common/types"
+)
+
+type unavailableVerifier struct{}
+
+func (unavailableVerifier) Verify(context.Context, string, string) error {
+	return types.ErrUnavailable{Message: "provider down", Cause: errors.New("dial tcp")}
+}
+
+func TestRequireCaptcha(t *testing.T) {
+	verifier := captcha.FakeVerifier{Token: "pass"}
+
+	tests := []struct {
+		name           string
+		verifier       captcha.Verifier
+		profiles       []string
+		profileID      string
+		token          string
+		expectedStatus int
+		expectedCode   string
+	}{
+		{name: "valid token", verifier: verifier, profiles: []string{"p1"}, profileID: "p1", token: "pass", expectedStatus: http.StatusOK},
+		{name: "missing token", verifier: verifier, profiles: []string{"p1"}, profileID: "p1", expectedStatus: http.StatusBadRequest, expectedCode: "VALIDATION_ERROR"},
+		{name: "invalid token", verifier: verifier, profiles: []string{"p1"}, profileID: "p1", token: "fail", expectedStatus: http.StatusBadRequest, expectedCode: "VALIDATION_ERROR"},
+		{name: "profile not enabled", verifier: verifier, profiles: []string{"p1"}, profileID: "p2", expectedStatus: http.StatusOK},
+		{name: "every profile", verifier: verifier, profiles: []string{captcha.AllProfiles}, profileID: "p2", expectedStatus: http.StatusBadRequest, expectedCode: "VALIDATION_ERROR"},
+		{name: "disabled", verifier: nil, profiles: []string{captcha.AllProfiles}, profileID: "p1", expectedStatus: http.StatusOK},
+		{name: "provider unavailable", verifier: unavailableVerifier{}, profiles: []string{"p1"}, profileID: "p1", token: "pass", expectedStatus: http.StatusServiceUnavailable, expectedCode: "SERVICE_UNAVAILABLE"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := RequireCaptcha(tt.verifier, captcha.NewProfileSet(tt.profiles))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/profiles/"+tt.profileID+"/contacts", nil)
+			req.SetPathValue("id", tt.profileID)
+			if tt.token != "" {
+				req.Header.Set(captcha.TokenHeader, tt.token)
+			}
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			assert.Contains(t, rr.Body.String(), tt.expectedCode)
+		})
+	}
+}
@@ -1,30 +1,135 @@
 package middleware
 
 import (
+	"net"
 	"net/http"
+	"net/netip"
+	"strings"
 
 	"github.com/mrthoabby/portfolio-api/internal/common"
 )
 
-// getClientIP extracts the client IP from the request headers or RemoteAddr
-func getClientIP(r *http.Request) string {
-	// Check X-Forwarded-For header (for proxies/load balancers)
-	if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
-		return xff
+// DefaultIPv6RateLimitPrefix groups IPv6 clients by /64, the smallest block usually assigned to a single subscriber
+const DefaultIPv6RateLimitPrefix = 64
+
+// getClientIP resolves the address of the client that sent the request.
+// Forwarding headers are only honored when the direct peer is a trusted proxy; the
+// Forwarded (RFC 7239) or X-Forwarded-For chain is then walked right to left and the
+// first address that is not a trusted proxy is the client.
+func getClientIP(r *http.Request, trustedProxies []netip.Prefix) string {
+	remote, ok := parseIP(r.RemoteAddr)
+	if !ok {
+		return r.RemoteAddr
+	}
+	if !isTrusted(remote, trustedProxies) {
+		return remote.String()
+	}
+
+	chain := forwardedFor(r.Header)
+	if len(chain) == 0 {
+		if realIP, ok := parseIP(r.Header.Get("X-Real-IP")); ok {
+			return realIP.String()
+		}
+		return remote.String()
 	}
-	// Check X-Real-IP header
-	if xri := r.Header.Get("X-Real-IP"); xri != "" {
-		return xri
+
+	client := remote
+	for i := len(chain) - 1; i >= 0; i-- {
+		hop, ok := parseIP(chain[i])
+		if !ok {
+			// An unparseable hop was not written by a proxy we trust; stop at the last known address
+			break
+		}
+		client = hop
+		if !isTrusted(hop, trustedProxies) {
+			break
+		}
 	}
-	// Fall back to RemoteAddr
-	return r.RemoteAddr
+	return client.String()
 }
 
-// ClientIP middleware extracts the client IP and saves it to context
-func ClientIP(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		ip := getClientIP(r)
-		ctx := common.WithClientIP(r.Context(), ip)
-		next.ServeHTTP(w, r.WithContext(ctx))
-	})
+// forwardedFor returns the client addresses of the Forwarded header, falling back to X-Forwarded-For.
+// Multiple header lines are joined in order, as proxies may append a new line instead of extending one.
+func forwardedFor(header http.Header) []string {
+	var chain []string
+
+	if forwarded := header.Values("Forwarded"); len(forwarded) > 0 {
+		for _, element := range strings.Split(strings.Join(forwarded, ","), ",") {
+			chain = append(chain, forwardedParam(element, "for"))
+		}
+		return chain
+	}
+
+	for _, hop := range strings.Split(strings.Join(header.Values("X-Forwarded-For"), ","), ",") {
+		if hop = strings.TrimSpace(hop); hop != "" {
+			chain = append(chain, hop)
+		}
+	}
+	return chain
+}
+
+// forwardedParam returns the value of a parameter of a Forwarded element, e.g. for="[2001:db8::1]:4711"
+func forwardedParam(element, name string) string {
+	for _, pair := range strings.Split(element, ";") {
+		key, value, found := strings.Cut(strings.TrimSpace(pair), "=")
+		if found && strings.EqualFold(key, name) {
+			return strings.Trim(value, `"`)
+		}
+	}
+	return ""
+}
+
+// parseIP parses an address with an optional port, e.g. "203.0.113.7:443" or "[2001:db8::1]:443",
+// mapping IPv4-mapped IPv6 addresses back to IPv4 and dropping IPv6 zones
+func parseIP(value string) (netip.Addr, bool) {
+	value = strings.TrimSpace(value)
+	if host, _, err := net.SplitHostPort(value); err == nil {
+		value = host
+	}
+	value = strings.TrimSuffix(strings.TrimPrefix(value, "["), "]")
+
+	addr, err := netip.ParseAddr(value)
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	return addr.Unmap().WithZone(""), true
+}
+
+func isTrusted(addr netip.Addr, trustedProxies []netip.Prefix) bool {
+	for _, prefix := range trustedProxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// rateLimitKey groups IPv6 clients by their network prefix so that rotating through the
+// addresses of a single allocation does not reset the limit; IPv4 addresses are used as is
+func rateLimitKey(ip string, ipv6Prefix int) string {
+	addr, ok := parseIP(ip)
+	if !ok {
+		return ip
+	}
+	if !addr.Is6() || ipv6Prefix <= 0 || ipv6Prefix >= 128 {
+		return addr.String()
+	}
+
+	prefix, err := addr.Prefix(ipv6Prefix)
+	if err != nil {
+		return addr.String()
+	}
+	return prefix.String()
+}
+
+// ClientIP returns a middleware that resolves the client IP and saves it to the context.
+// trustedProxies lists the networks of the load balancers and reverse proxies in front of the API.
+func ClientIP(trustedProxies []netip.Prefix) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ip := getClientIP(r, trustedProxies)
+			ctx := common.WithClientIP(r.Context(), ip)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
 }
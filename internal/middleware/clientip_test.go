@@ -3,6 +3,7 @@ package middleware
 import (
 	"net/http"
 	"net/http/httptest"
+	"net/netip"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -10,45 +11,126 @@ import (
 	"github.com/mrthoabby/portfolio-api/internal/common"
 )
 
-func Test_getClientIP_XForwardedFor(t *testing.T) {
+var testTrustedProxies = []netip.Prefix{
+	netip.MustParsePrefix("10.0.0.0/8"),
+	netip.MustParsePrefix("2001:db8:ffff::/48"),
+}
+
+func Test_getClientIP_RemoteAddr(t *testing.T) {
 	req := httptest.NewRequest(http.MethodGet, "/test", nil)
-	req.Header.Set("X-Forwarded-For", "10.0.0.1")
 	req.RemoteAddr = "192.168.1.1:1234"
 
-	ip := getClientIP(req)
-	assert.Equal(t, "10.0.0.1", ip)
+	ip := getClientIP(req, nil)
+	assert.Equal(t, "192.168.1.1", ip)
+}
+
+func Test_getClientIP_IgnoresHeadersFromUntrustedPeer(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set("X-Forwarded-For", "1.2.3.4")
+	req.Header.Set("X-Real-IP", "1.2.3.5")
+	req.Header.Set("Forwarded", "for=1.2.3.6")
+	req.RemoteAddr = "192.168.1.1:1234"
+
+	ip := getClientIP(req, testTrustedProxies)
+	assert.Equal(t, "192.168.1.1", ip)
+}
+
+func Test_getClientIP_XForwardedFor(t *testing.T) {
+	tests := []struct {
+		name     string
+		xff      []string
+		expected string
+	}{
+		{"single hop", []string{"203.0.113.7"}, "203.0.113.7"},
+		{"spoofed prefix", []string{"1.1.1.1, 203.0.113.7"}, "203.0.113.7"},
+		{"chained trusted proxies", []string{"1.1.1.1, 203.0.113.7, 10.0.0.2"}, "203.0.113.7"},
+		{"multiple header lines", []string{"1.1.1.1", "203.0.113.7, 10.0.0.2"}, "203.0.113.7"},
+		{"with port", []string{"203.0.113.7:5555"}, "203.0.113.7"},
+		{"garbage hop", []string{"203.0.113.7, not-an-ip, 10.0.0.2"}, "10.0.0.2"},
+		{"only trusted hops", []string{"10.0.0.3, 10.0.0.2"}, "10.0.0.3"},
+		{"ipv6", []string{"2001:db8::1"}, "2001:db8::1"},
+		{"ipv4-mapped ipv6", []string{"::ffff:203.0.113.7"}, "203.0.113.7"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			for _, value := range tt.xff {
+				req.Header.Add("X-Forwarded-For", value)
+			}
+			req.RemoteAddr = "10.0.0.1:1234"
+
+			ip := getClientIP(req, testTrustedProxies)
+			assert.Equal(t, tt.expected, ip)
+		})
+	}
+}
+
+func Test_getClientIP_Forwarded(t *testing.T) {
+	tests := []struct {
+		name      string
+		forwarded string
+		expected  string
+	}{
+		{"single element", "for=203.0.113.7;proto=https", "203.0.113.7"},
+		{"quoted ipv6 with port", `for="[2001:db8:cafe::17]:4711"`, "2001:db8:cafe::17"},
+		{"case insensitive", "For=203.0.113.7", "203.0.113.7"},
+		{"chain", `for=1.1.1.1, for=203.0.113.7;by=10.0.0.1, for="[2001:db8:ffff::2]"`, "203.0.113.7"},
+		{"obfuscated", "for=_hidden, for=10.0.0.2", "10.0.0.2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			req.Header.Set("Forwarded", tt.forwarded)
+			req.Header.Set("X-Forwarded-For", "198.51.100.1")
+			req.RemoteAddr = "10.0.0.1:1234"
+
+			ip := getClientIP(req, testTrustedProxies)
+			assert.Equal(t, tt.expected, ip)
+		})
+	}
 }
 
 func Test_getClientIP_XRealIP(t *testing.T) {
 	req := httptest.NewRequest(http.MethodGet, "/test", nil)
 	req.Header.Set("X-Real-IP", "10.0.0.2")
-	req.RemoteAddr = "192.168.1.1:1234"
+	req.RemoteAddr = "10.0.0.1:1234"
 
-	ip := getClientIP(req)
+	ip := getClientIP(req, testTrustedProxies)
 	assert.Equal(t, "10.0.0.2", ip)
 }
 
-func Test_getClientIP_RemoteAddr(t *testing.T) {
+func Test_getClientIP_NormalizesIPv6RemoteAddr(t *testing.T) {
 	req := httptest.NewRequest(http.MethodGet, "/test", nil)
-	req.RemoteAddr = "192.168.1.1:1234"
+	req.RemoteAddr = "[2001:DB8:0:0::1%eth0]:443"
+
+	ip := getClientIP(req, nil)
+	assert.Equal(t, "2001:db8::1", ip)
+}
 
-	ip := getClientIP(req)
-	assert.Equal(t, "192.168.1.1:1234", ip)
+func Test_rateLimitKey(t *testing.T) {
+	assert.Equal(t, "203.0.113.7", rateLimitKey("203.0.113.7", 64))
+	assert.Equal(t, "2001:db8:1:2::/64", rateLimitKey("2001:db8:1:2:aaaa::1", 64))
+	assert.Equal(t, "2001:db8:1:2::/64", rateLimitKey("2001:db8:1:2:bbbb::9", 64))
+	assert.Equal(t, "2001:db8:1::/48", rateLimitKey("2001:db8:1:2::1", 48))
+	assert.Equal(t, "2001:db8:1:2::1", rateLimitKey("2001:db8:1:2::1", 128))
+	assert.Equal(t, "unknown", rateLimitKey("unknown", 64))
 }
 
 func TestClientIP_Middleware(t *testing.T) {
-	handler := ClientIP(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	handler := ClientIP(testTrustedProxies)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Verify IP is in context using common package
 		ip := common.ClientIPFromContext(r.Context())
-		assert.Equal(t, "192.168.1.1:1234", ip)
+		assert.Equal(t, "203.0.113.7", ip)
 		w.WriteHeader(http.StatusOK)
 	}))
 
 	req := httptest.NewRequest(http.MethodGet, "/test", nil)
-	req.RemoteAddr = "192.168.1.1:1234"
+	req.Header.Set("X-Forwarded-For", "203.0.113.7")
+	req.RemoteAddr = "10.0.0.1:1234"
 	rr := httptest.NewRecorder()
 
 	handler.ServeHTTP(rr, req)
 	assert.Equal(t, http.StatusOK, rr.Code)
 }
-
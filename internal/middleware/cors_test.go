@@ -3,7 +3,9 @@ package middleware
 import (
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -50,3 +52,61 @@ func TestNewCORS_EmptyOrigins(t *testing.T) {
 	assert.NotNil(t, corsHandler)
 }
 
+func TestNewCORS_PreflightAllowsAPIKey(t *testing.T) {
+	corsHandler := NewCORS([]string{"http://localhost:3000"})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	req := httptest.NewRequest("OPTIONS", "/api/v1/admin/profiles", nil)
+	req.Header.Set("Origin", "http://localhost:3000")
+	req.Header.Set("Access-Control-Request-Method", "GET")
+	req.Header.Set("Access-Control-Request-Headers", "X-API-Key")
+	w := httptest.NewRecorder()
+
+	corsHandler(handler).ServeHTTP(w, req)
+
+	assert.Equal(t, "http://localhost:3000", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Contains(t, strings.ToLower(w.Header().Get("Access-Control-Allow-Headers")), "x-api-key")
+}
+
+func TestNewCORS_ExposesRateLimitHeaders(t *testing.T) {
+	corsHandler := NewCORS([]string{"http://localhost:3000"})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	req := httptest.NewRequest("GET", "/api/v1/profiles", nil)
+	req.Header.Set("Origin", "http://localhost:3000")
+	w := httptest.NewRecorder()
+
+	corsHandler(handler).ServeHTTP(w, req)
+
+	exposed := w.Header().Get("Access-Control-Expose-Headers")
+	for _, header := range []string{"X-Request-Id", "Ratelimit-Limit", "Ratelimit-Remaining", "Ratelimit-Reset", "Ratelimit-Policy", "Retry-After"} {
+		assert.Contains(t, exposed, header)
+	}
+}
+
+func TestNewCORS_RateLimitedResponse(t *testing.T) {
+	limiter := NewRateLimiter(1, time.Minute)
+	defer limiter.Stop()
+	// CORS runs before the limiter, as in the router, so rejections are readable by browsers
+	handler := NewCORS([]string{"http://localhost:3000"})(limiter.Limit(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})))
+
+	var w *httptest.ResponseRecorder
+	for range 2 {
+		req := httptest.NewRequest("POST", "/api/v1/profiles/1/contacts", nil)
+		req.RemoteAddr = "203.0.113.1:1234"
+		req.Header.Set("Origin", "http://localhost:3000")
+		w = httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+	}
+
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "http://localhost:3000", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+	assert.Contains(t, w.Header().Get("Access-Control-Expose-Headers"), "Retry-After")
+}
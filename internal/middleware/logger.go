@@ -33,11 +33,20 @@ func WithLogger(l logger.Logger) func(http.Handler) http.Handler {
 			start := time.Now()
 			wrapped := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
 
+			// Make the logger available to handlers, e.g. for common.RespondServiceError
+			if l != nil {
+				r = r.WithContext(common.WithLogger(r.Context(), l))
+			}
+
 			next.ServeHTTP(wrapped, r)
 
 			duration := time.Since(start)
 			requestID := GetRequestID(r.Context())
 			clientIP := common.ClientIPFromContext(r.Context())
+			subject := ""
+			if principal := common.PrincipalFromContext(r.Context()); principal != nil {
+				subject = principal.Subject
+			}
 
 			if l != nil {
 				// Structured logging with fields
@@ -48,17 +57,19 @@ func WithLogger(l logger.Logger) func(http.Handler) http.Handler {
 					logger.Int("status", wrapped.statusCode),
 					logger.Duration("duration", duration),
 					logger.String("client_ip", clientIP),
+					logger.String("principal", subject),
 				)
 			} else {
 				// Fallback to standard log format
 				log.Printf(
-					"[%s] %s %s %d %v %s",
+					"[%s] %s %s %d %v %s %s",
 					requestID,
 					r.Method,
 					r.URL.Path,
 					wrapped.statusCode,
 					duration,
 					clientIP,
+					subject,
 				)
 			}
 		})
@@ -1,96 +1,212 @@
 package middleware
 
 import (
+	"context"
+	"math"
 	"net/http"
-	"sync"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/mrthoabby/portfolio-api/internal/common"
+	"github.com/mrthoabby/portfolio-api/internal/common/logger"
+	"github.com/mrthoabby/portfolio-api/internal/common/types"
 )
 
-// RateLimiter implements a simple in-memory rate limiter per IP
+// RateLimitBackend keeps the rate limit state of every client
+type RateLimitBackend interface {
+	// Allow records a request for key and reports whether it fits the quota
+	Allow(ctx context.Context, key string, quota Quota) (RateLimitResult, error)
+}
+
+// RateLimitResult is the outcome of a rate limit check
+type RateLimitResult struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	Reset      time.Duration // until the quota is fully restored
+	RetryAfter time.Duration // until the next request is allowed, zero when allowed
+}
+
+// RateLimiter limits requests according to a list of policies. State lives in a RateLimitBackend, in memory
+// by default; when a shared backend fails, requests are limited by the in-memory backend of this instance.
 type RateLimiter struct {
-	requests  map[string][]time.Time
-	syncMutex sync.RWMutex
-	limit     int           // max requests
-	window    time.Duration // time window
+	policies   []compiledPolicy
+	ipv6Prefix int // IPv6 clients share a limit per network of this size
+	maxKeys    int
+	backend    RateLimitBackend
+	memory     *MemoryRateLimitBackend
 }
 
-// NewRateLimiter creates a new rate limiter
-// limit: maximum number of requests allowed
-// window: time window for the limit
-func NewRateLimiter(limit int, window time.Duration) *RateLimiter {
-	rateLimiter := &RateLimiter{
-		requests: make(map[string][]time.Time),
-		limit:    limit,
-		window:   window,
+// RateLimiterOption customizes a RateLimiter
+type RateLimiterOption func(*RateLimiter)
+
+// WithIPv6Prefix sets the prefix length IPv6 clients are grouped by; 128 limits every address on its own
+func WithIPv6Prefix(bits int) RateLimiterOption {
+	return func(rateLimiter *RateLimiter) {
+		rateLimiter.ipv6Prefix = bits
 	}
+}
 
-	// Cleanup old entries every minute
-	go rateLimiter.cleanup()
+// WithMaxKeys caps the number of clients tracked in memory; the least recently seen are forgotten first
+func WithMaxKeys(maxKeys int) RateLimiterOption {
+	return func(rateLimiter *RateLimiter) {
+		rateLimiter.maxKeys = maxKeys
+	}
+}
 
-	return rateLimiter
+// WithBackend keeps the rate limit state in backend, e.g. a StoreRateLimitBackend shared by every instance
+func WithBackend(backend RateLimitBackend) RateLimiterOption {
+	return func(rateLimiter *RateLimiter) {
+		rateLimiter.backend = backend
+	}
 }
 
-func (instance *RateLimiter) cleanup() {
-	ticker := time.NewTicker(time.Minute)
-	for range ticker.C {
-		instance.syncMutex.Lock()
-		now := time.Now()
-		for ip, times := range instance.requests {
-			var valid []time.Time
-			for _, t := range times {
-				if now.Sub(t) <= instance.window {
-					valid = append(valid, t)
-				}
-			}
-			if len(valid) == 0 {
-				delete(instance.requests, ip)
-			} else {
-				instance.requests[ip] = valid
-			}
-		}
-		instance.syncMutex.Unlock()
+// NewRateLimiter creates a rate limiter allowing limit requests per window and client IP on every route.
+// Stop must be called to release the in-memory backend.
+func NewRateLimiter(limit int, window time.Duration, opts ...RateLimiterOption) *RateLimiter {
+	if limit < 1 {
+		limit = 1
 	}
+
+	return NewPolicyRateLimiter([]RateLimitPolicy{{
+		Name:  "default",
+		Route: "*",
+		Key:   RateLimitKeyIP,
+		Quota: Quota{Limit: limit, Window: window},
+	}}, opts...)
 }
 
-func (instance *RateLimiter) isAllowed(ip string) bool {
-	instance.syncMutex.Lock()
-	defer instance.syncMutex.Unlock()
+// NewPolicyRateLimiter creates a rate limiter applying every policy whose route matches a request.
+// The policies must be valid, as the configuration ensures when loading them.
+// Stop must be called to release the in-memory backend.
+func NewPolicyRateLimiter(policies []RateLimitPolicy, opts ...RateLimiterOption) *RateLimiter {
+	rateLimiter := &RateLimiter{
+		ipv6Prefix: DefaultIPv6RateLimitPrefix,
+		maxKeys:    DefaultRateLimiterMaxKeys,
+	}
+	for _, opt := range opts {
+		opt(rateLimiter)
+	}
 
-	now := time.Now()
-	windowStart := now.Add(-instance.window)
+	for _, policy := range policies {
+		rateLimiter.policies = append(rateLimiter.policies, compilePolicy(policy))
+	}
 
-	// Filter requests within the window
-	var validRequests []time.Time
-	for _, t := range instance.requests[ip] {
-		if t.After(windowStart) {
-			validRequests = append(validRequests, t)
-		}
+	rateLimiter.memory = NewMemoryRateLimitBackend(rateLimiter.maxKeys)
+	if rateLimiter.backend == nil {
+		rateLimiter.backend = rateLimiter.memory
 	}
 
-	if len(validRequests) >= instance.limit {
-		instance.requests[ip] = validRequests
-		return false
+	return rateLimiter
+}
+
+// Stop releases the in-memory backend. The limiter keeps working afterwards.
+func (instance *RateLimiter) Stop() {
+	instance.memory.Stop()
+}
+
+// Allow records a request for key and reports whether it fits the quota
+func (instance *RateLimiter) Allow(ctx context.Context, key string, quota Quota) RateLimitResult {
+	result, err := instance.backend.Allow(ctx, key, quota)
+	if err == nil {
+		return result
 	}
 
-	// Add current request
-	validRequests = append(validRequests, now)
-	instance.requests[ip] = validRequests
-	return true
+	if l := common.LoggerFromContext(ctx); l != nil {
+		l.Warn("Rate limit backend failed, limiting per instance", logger.Error(err))
+	}
+	// The memory backend never fails
+	result, _ = instance.memory.Allow(ctx, key, quota)
+	return result
 }
 
-// Limit returns a middleware that rate limits requests
+// Limit returns a middleware that applies the policies matching each request, stopping at the first
+// one exceeded. Responses carry the RateLimit-Limit, RateLimit-Remaining and RateLimit-Reset headers
+// of the policy closest to its limit.
 func (instance *RateLimiter) Limit(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		ip := getClientIP(r)
+		principal := common.PrincipalFromContext(r.Context())
+
+		var binding *RateLimitResult
+		var applied []string
+		for _, policy := range instance.policies {
+			params, ok := policy.match(r)
+			if !ok {
+				continue
+			}
+			key := instance.key(r, policy, params, principal)
+			if key == "" {
+				continue
+			}
+
+			quota, counter := policy.quota(principal)
+			result := instance.Allow(r.Context(), counter+":"+key, quota)
+			applied = append(applied, formatPolicy(quota))
+			if binding == nil || result.Remaining < binding.Remaining || !result.Allowed {
+				binding = &result
+			}
+			if !result.Allowed {
+				break
+			}
+		}
+
+		if binding == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
 
-		if !instance.isAllowed(ip) {
-			w.Header().Set("Retry-After", "60")
-			common.RespondError(w, http.StatusTooManyRequests, "RATE_LIMIT_EXCEEDED", "Too many requests. Please try again later.", nil)
+		writeRateLimitHeaders(w, applied, *binding)
+		if !binding.Allowed {
+			common.RespondServiceError(w, r, types.ErrRateLimited{
+				Message:    "too many requests",
+				RetryAfter: binding.RetryAfter,
+			})
 			return
 		}
 
 		next.ServeHTTP(w, r)
 	})
 }
+
+// key returns what the request is counted by under policy, or "" when the policy does not apply to it
+func (instance *RateLimiter) key(r *http.Request, policy compiledPolicy, params map[string]string, principal *common.Principal) string {
+	switch policy.Key {
+	case RateLimitKeyIP:
+		ip := common.ClientIPFromContext(r.Context())
+		if ip == "" {
+			ip = getClientIP(r, nil)
+		}
+		return rateLimitKey(ip, instance.ipv6Prefix)
+	case RateLimitKeyAPIKey:
+		if principal != nil && principal.Method == common.AuthMethodAPIKey {
+			return principal.KeyID
+		}
+	case RateLimitKeyProfile:
+		return params["id"]
+	case RateLimitKeyEmail:
+		return emailFromBody(r)
+	}
+	return ""
+}
+
+// formatPolicy describes a quota for the RateLimit-Policy header, e.g. "5;w=60;burst=2"
+func formatPolicy(quota Quota) string {
+	policy := strconv.Itoa(quota.Limit) + ";w=" + strconv.Itoa(ceilSeconds(quota.Window))
+	if quota.Burst > 0 {
+		policy += ";burst=" + strconv.Itoa(quota.Burst)
+	}
+	return policy
+}
+
+func writeRateLimitHeaders(w http.ResponseWriter, policies []string, result RateLimitResult) {
+	header := w.Header()
+	header.Set("RateLimit-Policy", strings.Join(policies, ", "))
+	header.Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+	header.Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	header.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
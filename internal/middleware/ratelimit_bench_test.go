@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// slidingLogLimiter is the previous RateLimiter, kept to compare against: it stores the
+// timestamp of every request in the window and rescans them on each call
+type slidingLogLimiter struct {
+	requests  map[string][]time.Time
+	syncMutex sync.RWMutex
+	limit     int
+	window    time.Duration
+}
+
+func (instance *slidingLogLimiter) isAllowed(ip string) bool {
+	instance.syncMutex.Lock()
+	defer instance.syncMutex.Unlock()
+
+	now := time.Now()
+	windowStart := now.Add(-instance.window)
+
+	var validRequests []time.Time
+	for _, t := range instance.requests[ip] {
+		if t.After(windowStart) {
+			validRequests = append(validRequests, t)
+		}
+	}
+
+	if len(validRequests) >= instance.limit {
+		instance.requests[ip] = validRequests
+		return false
+	}
+
+	validRequests = append(validRequests, now)
+	instance.requests[ip] = validRequests
+	return true
+}
+
+var benchmarkKeys = func() []string {
+	keys := make([]string, 1024)
+	for i := range keys {
+		keys[i] = "10.0." + strconv.Itoa(i/256) + "." + strconv.Itoa(i%256)
+	}
+	return keys
+}()
+
+func BenchmarkRateLimiter_GCRA(b *testing.B) {
+	for _, limit := range []int{10, 100, 1000} {
+		b.Run("limit="+strconv.Itoa(limit), func(b *testing.B) {
+			limiter := NewRateLimiter(limit, time.Minute)
+			defer limiter.Stop()
+			quota := Quota{Limit: limit, Window: time.Minute}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				limiter.Allow(context.Background(), benchmarkKeys[i%len(benchmarkKeys)], quota)
+			}
+		})
+	}
+}
+
+func BenchmarkRateLimiter_SlidingLog(b *testing.B) {
+	for _, limit := range []int{10, 100, 1000} {
+		b.Run("limit="+strconv.Itoa(limit), func(b *testing.B) {
+			limiter := &slidingLogLimiter{requests: make(map[string][]time.Time), limit: limit, window: time.Minute}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				limiter.isAllowed(benchmarkKeys[i%len(benchmarkKeys)])
+			}
+		})
+	}
+}
+
+func BenchmarkRateLimiter_GCRA_Parallel(b *testing.B) {
+	limiter := NewRateLimiter(100, time.Minute)
+	defer limiter.Stop()
+	quota := Quota{Limit: 100, Window: time.Minute}
+
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			limiter.Allow(context.Background(), benchmarkKeys[i%len(benchmarkKeys)], quota)
+			i++
+		}
+	})
+}
+
+func BenchmarkRateLimiter_SlidingLog_Parallel(b *testing.B) {
+	limiter := &slidingLogLimiter{requests: make(map[string][]time.Time), limit: 100, window: time.Minute}
+
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			limiter.isAllowed(benchmarkKeys[i%len(benchmarkKeys)])
+			i++
+		}
+	})
+}
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// MailLimiter caps the emails anonymous clients make the API send, per recipient address and
+// per client IP, so public forms cannot be used to flood an inbox. Its counters live in the
+// backend of the rate limiter, shared by every instance when the store backend is used.
+type MailLimiter struct {
+	limiter    *RateLimiter
+	name       string
+	perAddress Quota
+	perIP      Quota
+}
+
+// NewMailLimiter creates a mail limiter counting under name, separately from the request policies
+func NewMailLimiter(limiter *RateLimiter, name string, perAddress, perIP Quota) *MailLimiter {
+	return &MailLimiter{
+		limiter:    limiter,
+		name:       name,
+		perAddress: perAddress,
+		perIP:      perIP,
+	}
+}
+
+// AllowMail records an email to address requested from clientIP and reports whether it fits both
+// limits. When it does not, it returns how long until another email may be requested.
+func (l *MailLimiter) AllowMail(ctx context.Context, address, clientIP string) (time.Duration, bool) {
+	// Both limits are counted even when the first one is exceeded, so retrying does not help
+	results := []RateLimitResult{
+		l.limiter.Allow(ctx, l.name+":address:"+strings.ToLower(strings.TrimSpace(address)), l.perAddress),
+	}
+	if clientIP != "" {
+		results = append(results, l.limiter.Allow(ctx, l.name+":ip:"+rateLimitKey(clientIP, l.limiter.ipv6Prefix), l.perIP))
+	}
+
+	var retryAfter time.Duration
+	allowed := true
+	for _, result := range results {
+		if !result.Allowed {
+			allowed = false
+			retryAfter = max(retryAfter, result.RetryAfter)
+		}
+	}
+	return retryAfter, allowed
+}
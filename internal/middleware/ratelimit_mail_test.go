@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMailLimiter_AllowMail(t *testing.T) {
+	limiter := NewPolicyRateLimiter(nil)
+	defer limiter.Stop()
+	mails := NewMailLimiter(limiter, "contact-mail", Quota{Limit: 2, Window: time.Hour}, Quota{Limit: 3, Window: time.Hour})
+	ctx := context.Background()
+
+	// Addresses are counted case-insensitively
+	for _, address := range []string{"ada@example.com", "ADA@example.com"} {
+		_, allowed := mails.AllowMail(ctx, address, "203.0.113.1")
+		assert.True(t, allowed)
+	}
+	retryAfter, allowed := mails.AllowMail(ctx, "ada@example.com", "203.0.113.2")
+	assert.False(t, allowed, "the address got its quota")
+	assert.Positive(t, retryAfter)
+
+	// 203.0.113.1 already requested two emails
+	_, allowed = mails.AllowMail(ctx, "grace@example.com", "203.0.113.1")
+	assert.True(t, allowed)
+	_, allowed = mails.AllowMail(ctx, "linus@example.com", "203.0.113.1")
+	assert.False(t, allowed, "the client IP got its quota")
+
+	_, allowed = mails.AllowMail(ctx, "linus@example.com", "")
+	assert.True(t, allowed, "requests without a client IP are only limited per address")
+}
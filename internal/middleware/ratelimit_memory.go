@@ -0,0 +1,143 @@
+package middleware
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// DefaultRateLimiterMaxKeys caps the number of clients a MemoryRateLimitBackend tracks at once
+const DefaultRateLimiterMaxKeys = 100_000
+
+// rateLimiterCleanupInterval is how often keys whose quota is fully restored are dropped
+const rateLimiterCleanupInterval = time.Minute
+
+// MemoryRateLimitBackend keeps the rate limit state of a single instance in memory using the
+// generic cell rate algorithm (GCRA). Each client costs a single timestamp, the theoretical
+// arrival time of its next request, and the least recently seen clients are evicted once
+// maxKeys is reached.
+type MemoryRateLimitBackend struct {
+	syncMutex sync.Mutex
+	keys      map[string]*list.Element
+	recent    *list.List // front is the most recently seen key
+	maxKeys   int
+	now       func() time.Time
+	stop      chan struct{}
+	stopOnce  sync.Once
+}
+
+var _ RateLimitBackend = (*MemoryRateLimitBackend)(nil)
+
+type rateLimitEntry struct {
+	key string
+	tat time.Time // theoretical arrival time: the quota is fully restored at this instant
+}
+
+// NewMemoryRateLimitBackend creates an in-memory backend tracking up to maxKeys clients.
+// Stop must be called to release the cleanup goroutine.
+func NewMemoryRateLimitBackend(maxKeys int) *MemoryRateLimitBackend {
+	if maxKeys <= 0 {
+		maxKeys = DefaultRateLimiterMaxKeys
+	}
+
+	backend := &MemoryRateLimitBackend{
+		keys:    make(map[string]*list.Element),
+		recent:  list.New(),
+		maxKeys: maxKeys,
+		now:     time.Now,
+		stop:    make(chan struct{}),
+	}
+
+	go backend.cleanup()
+
+	return backend
+}
+
+// Stop ends the cleanup goroutine. The backend keeps working afterwards, bounded by maxKeys.
+func (instance *MemoryRateLimitBackend) Stop() {
+	instance.stopOnce.Do(func() {
+		close(instance.stop)
+	})
+}
+
+func (instance *MemoryRateLimitBackend) cleanup() {
+	ticker := time.NewTicker(rateLimiterCleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-instance.stop:
+			return
+		case <-ticker.C:
+			instance.removeExpired()
+		}
+	}
+}
+
+// removeExpired drops keys whose quota is fully restored; forgetting them changes nothing
+func (instance *MemoryRateLimitBackend) removeExpired() {
+	instance.syncMutex.Lock()
+	defer instance.syncMutex.Unlock()
+
+	now := instance.now()
+	for element := instance.recent.Back(); element != nil; {
+		previous := element.Prev()
+		if entry := element.Value.(*rateLimitEntry); !entry.tat.After(now) {
+			instance.recent.Remove(element)
+			delete(instance.keys, entry.key)
+		}
+		element = previous
+	}
+}
+
+// Allow records a request for key and reports whether it fits the quota.
+// Requests are restored one every window / limit; the burst adds to how many may be spent at once.
+func (instance *MemoryRateLimitBackend) Allow(_ context.Context, key string, quota Quota) (RateLimitResult, error) {
+	instance.syncMutex.Lock()
+	defer instance.syncMutex.Unlock()
+
+	now := instance.now()
+	entry := instance.entry(key)
+	interval := quota.Window / time.Duration(quota.Limit)
+	tolerance := interval * time.Duration(quota.Capacity())
+
+	tat := entry.tat
+	if tat.Before(now) {
+		tat = now
+	}
+	newTat := tat.Add(interval)
+	// The request fits when the quota it consumes is restored within the tolerance
+	allowAt := newTat.Add(-tolerance)
+
+	result := RateLimitResult{Limit: quota.Capacity()}
+	if now.Before(allowAt) {
+		result.RetryAfter = allowAt.Sub(now)
+		result.Reset = tat.Sub(now)
+		return result, nil
+	}
+
+	entry.tat = newTat
+	result.Allowed = true
+	result.Remaining = int((tolerance - newTat.Sub(now)) / interval)
+	result.Reset = newTat.Sub(now)
+	return result, nil
+}
+
+// entry returns the entry of key, creating it and evicting the least recently seen key when full
+func (instance *MemoryRateLimitBackend) entry(key string) *rateLimitEntry {
+	if element, ok := instance.keys[key]; ok {
+		instance.recent.MoveToFront(element)
+		return element.Value.(*rateLimitEntry)
+	}
+
+	if instance.recent.Len() >= instance.maxKeys {
+		oldest := instance.recent.Back()
+		instance.recent.Remove(oldest)
+		delete(instance.keys, oldest.Value.(*rateLimitEntry).key)
+	}
+
+	entry := &rateLimitEntry{key: key}
+	instance.keys[key] = instance.recent.PushFront(entry)
+	return entry
+}
@@ -0,0 +1,160 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mrthoabby/portfolio-api/internal/common"
+)
+
+// RateLimitKey selects what a rate limit policy counts requests by
+type RateLimitKey string
+
+const (
+	// RateLimitKeyIP counts requests per client IP, IPv6 clients per network
+	RateLimitKeyIP RateLimitKey = "ip"
+	// RateLimitKeyAPIKey counts requests per API key; requests without one are not limited by the policy
+	RateLimitKeyAPIKey RateLimitKey = "apiKey"
+	// RateLimitKeyProfile counts requests per profile, taken from the {id} route parameter
+	RateLimitKeyProfile RateLimitKey = "profile"
+	// RateLimitKeyEmail counts requests per "email" field of the JSON body
+	RateLimitKeyEmail RateLimitKey = "email"
+)
+
+// Quota allows Limit requests per Window, restored gradually, plus Burst requests on top
+type Quota struct {
+	Limit  int
+	Window time.Duration
+	Burst  int
+}
+
+// Capacity is the number of requests that may be made at once
+func (q Quota) Capacity() int {
+	return q.Limit + q.Burst
+}
+
+// RateLimitPolicy limits the requests matching Route, counted per Key
+type RateLimitPolicy struct {
+	// Name identifies the policy; requests are counted separately per policy
+	Name string
+	// Route is "*" for every request, or an optional method followed by a path where {param}
+	// matches one segment and a trailing /* any remaining ones, e.g. "POST /api/v1/profiles/{id}/contacts"
+	Route string
+	Key   RateLimitKey
+	Quota Quota
+	// Authenticated, when set, replaces Quota for authenticated callers, who are then counted
+	// separately from anonymous requests sharing the same key
+	Authenticated *Quota
+}
+
+type compiledPolicy struct {
+	RateLimitPolicy
+	method   string
+	segments []string
+	anyPath  bool // matches every path
+	prefix   bool // the last segment was /*, matching any remaining ones
+}
+
+// compilePolicy prepares the route of a policy for matching. Policies are validated when the
+// configuration is loaded, see config.RateLimitPolicy.
+func compilePolicy(policy RateLimitPolicy) compiledPolicy {
+	compiled := compiledPolicy{RateLimitPolicy: policy}
+
+	route := strings.TrimSpace(policy.Route)
+	if method, path, found := strings.Cut(route, " "); found {
+		compiled.method = strings.ToUpper(method)
+		route = strings.TrimSpace(path)
+	}
+	if route == "*" {
+		compiled.anyPath = true
+		return compiled
+	}
+
+	compiled.segments = splitPath(route)
+	if n := len(compiled.segments); n > 0 && compiled.segments[n-1] == "*" {
+		compiled.segments = compiled.segments[:n-1]
+		compiled.prefix = true
+	}
+	return compiled
+}
+
+// match reports whether the request matches the policy route and returns the route parameters
+func (p compiledPolicy) match(r *http.Request) (map[string]string, bool) {
+	if p.method != "" && p.method != r.Method {
+		return nil, false
+	}
+	if p.anyPath {
+		return nil, true
+	}
+
+	path := splitPath(r.URL.Path)
+	if len(path) < len(p.segments) || (!p.prefix && len(path) != len(p.segments)) {
+		return nil, false
+	}
+
+	params := map[string]string{}
+	for i, segment := range p.segments {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			params[segment[1:len(segment)-1]] = path[i]
+			continue
+		}
+		if segment != path[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+// quota returns the quota that applies to the caller and the name its requests are counted under
+func (p compiledPolicy) quota(principal *common.Principal) (Quota, string) {
+	if principal != nil && p.Authenticated != nil {
+		return *p.Authenticated, p.Name + ":authenticated"
+	}
+	return p.Quota, p.Name
+}
+
+func splitPath(path string) []string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, "/")
+}
+
+// emailFromBody reads the "email" field of a JSON body and restores the body for the handler.
+// A read error, such as the body exceeding its size limit, is replayed to the handler as well.
+func emailFromBody(r *http.Request) string {
+	if r.Body == nil || r.Body == http.NoBody {
+		return ""
+	}
+
+	data, err := io.ReadAll(r.Body)
+	r.Body = io.NopCloser(io.MultiReader(bytes.NewReader(data), errorReader{err}))
+	if err != nil {
+		return ""
+	}
+
+	var body struct {
+		Email string `json:"email"`
+	}
+	if json.Unmarshal(data, &body) != nil {
+		return ""
+	}
+	return strings.ToLower(strings.TrimSpace(body.Email))
+}
+
+// errorReader returns err, or io.EOF when err is nil
+type errorReader struct {
+	err error
+}
+
+func (r errorReader) Read([]byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	return 0, io.EOF
+}
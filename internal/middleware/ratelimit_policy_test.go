@@ -0,0 +1,197 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mrthoabby/portfolio-api/internal/common"
+)
+
+func TestCompilePolicy_Match(t *testing.T) {
+	tests := []struct {
+		route    string
+		method   string
+		path     string
+		matches  bool
+		expected map[string]string
+	}{
+		{route: "*", method: http.MethodGet, path: "/anything", matches: true},
+		{route: "POST *", method: http.MethodGet, path: "/anything", matches: false},
+		{route: "POST /api/v1/profiles/{id}/contacts", method: http.MethodPost, path: "/api/v1/profiles/p1/contacts", matches: true, expected: map[string]string{"id": "p1"}},
+		{route: "POST /api/v1/profiles/{id}/contacts", method: http.MethodPost, path: "/api/v1/profiles/p1/contacts/", matches: true, expected: map[string]string{"id": "p1"}},
+		{route: "post /api/v1/profiles/{id}/contacts", method: http.MethodPost, path: "/api/v1/profiles/p1/contacts", matches: true, expected: map[string]string{"id": "p1"}},
+		{route: "POST /api/v1/profiles/{id}/contacts", method: http.MethodGet, path: "/api/v1/profiles/p1/contacts", matches: false},
+		{route: "POST /api/v1/profiles/{id}/contacts", method: http.MethodPost, path: "/api/v1/profiles/p1/questions", matches: false},
+		{route: "/api/v1/profiles/{id}", method: http.MethodPut, path: "/api/v1/profiles/p1/skills", matches: false},
+		{route: "/api/v1/profiles/{id}/*", method: http.MethodPut, path: "/api/v1/profiles/p1/skills/s1", matches: true, expected: map[string]string{"id": "p1"}},
+		{route: "/api/v1/*", method: http.MethodGet, path: "/health", matches: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.route+" "+tt.method+" "+tt.path, func(t *testing.T) {
+			policy := compilePolicy(RateLimitPolicy{Name: "test", Route: tt.route, Key: RateLimitKeyIP, Quota: Quota{Limit: 1, Window: time.Minute}})
+
+			params, ok := policy.match(httptest.NewRequest(tt.method, tt.path, nil))
+			assert.Equal(t, tt.matches, ok)
+			if tt.expected != nil {
+				assert.Equal(t, tt.expected, params)
+			}
+		})
+	}
+}
+
+func newTestPolicyRateLimiter(t *testing.T, policies ...RateLimitPolicy) http.Handler {
+	limiter := NewPolicyRateLimiter(policies)
+	t.Cleanup(limiter.Stop)
+
+	return limiter.Limit(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusOK)
+		w.Write(body)
+	}))
+}
+
+func serveRateLimited(handler http.Handler, method, path, body string, principal *common.Principal) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.RemoteAddr = "192.168.1.1:1234"
+	if principal != nil {
+		req = req.WithContext(common.WithPrincipal(req.Context(), principal))
+	}
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestRateLimiter_Policies_RouteSpecificAndGlobal(t *testing.T) {
+	handler := newTestPolicyRateLimiter(t,
+		RateLimitPolicy{Name: "global", Route: "*", Key: RateLimitKeyIP, Quota: Quota{Limit: 10, Window: time.Minute}},
+		RateLimitPolicy{Name: "contacts", Route: "POST /api/v1/profiles/{id}/contacts", Key: RateLimitKeyIP, Quota: Quota{Limit: 1, Window: time.Minute}},
+	)
+
+	rr := serveRateLimited(handler, http.MethodPost, "/api/v1/profiles/p1/contacts", "", nil)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "10;w=60, 1;w=60", rr.Header().Get("RateLimit-Policy"))
+	assert.Equal(t, "1", rr.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "0", rr.Header().Get("RateLimit-Remaining"))
+
+	assert.Equal(t, http.StatusTooManyRequests, serveRateLimited(handler, http.MethodPost, "/api/v1/profiles/p1/contacts", "", nil).Code)
+
+	// Other routes only count against the global policy
+	rr = serveRateLimited(handler, http.MethodGet, "/api/v1/profiles/p1", "", nil)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "10;w=60", rr.Header().Get("RateLimit-Policy"))
+	assert.Equal(t, "7", rr.Header().Get("RateLimit-Remaining"))
+}
+
+func TestRateLimiter_Policies_NoMatch(t *testing.T) {
+	handler := newTestPolicyRateLimiter(t,
+		RateLimitPolicy{Name: "contacts", Route: "POST /api/v1/profiles/{id}/contacts", Key: RateLimitKeyIP, Quota: Quota{Limit: 1, Window: time.Minute}},
+	)
+
+	rr := serveRateLimited(handler, http.MethodGet, "/health", "", nil)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Empty(t, rr.Header().Get("RateLimit-Policy"))
+}
+
+func TestRateLimiter_Policies_Burst(t *testing.T) {
+	handler := newTestPolicyRateLimiter(t,
+		RateLimitPolicy{Name: "global", Route: "*", Key: RateLimitKeyIP, Quota: Quota{Limit: 2, Window: time.Minute, Burst: 3}},
+	)
+
+	for i := 0; i < 5; i++ {
+		rr := serveRateLimited(handler, http.MethodGet, "/test", "", nil)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "2;w=60;burst=3", rr.Header().Get("RateLimit-Policy"))
+		assert.Equal(t, "5", rr.Header().Get("RateLimit-Limit"))
+	}
+	assert.Equal(t, http.StatusTooManyRequests, serveRateLimited(handler, http.MethodGet, "/test", "", nil).Code)
+}
+
+func TestRateLimiter_Policies_AuthenticatedQuota(t *testing.T) {
+	handler := newTestPolicyRateLimiter(t,
+		RateLimitPolicy{
+			Name:          "global",
+			Route:         "*",
+			Key:           RateLimitKeyIP,
+			Quota:         Quota{Limit: 1, Window: time.Minute},
+			Authenticated: &Quota{Limit: 3, Window: time.Minute},
+		},
+	)
+	principal := &common.Principal{Subject: "owner", Method: common.AuthMethodAPIKey, KeyID: "key-1"}
+
+	assert.Equal(t, http.StatusOK, serveRateLimited(handler, http.MethodGet, "/test", "", nil).Code)
+	assert.Equal(t, http.StatusTooManyRequests, serveRateLimited(handler, http.MethodGet, "/test", "", nil).Code)
+
+	rr := serveRateLimited(handler, http.MethodGet, "/test", "", principal)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "3;w=60", rr.Header().Get("RateLimit-Policy"))
+}
+
+func TestRateLimiter_Policies_APIKey(t *testing.T) {
+	handler := newTestPolicyRateLimiter(t,
+		RateLimitPolicy{Name: "keys", Route: "*", Key: RateLimitKeyAPIKey, Quota: Quota{Limit: 1, Window: time.Minute}},
+	)
+	first := &common.Principal{Subject: "owner", Method: common.AuthMethodAPIKey, KeyID: "key-1"}
+	second := &common.Principal{Subject: "owner", Method: common.AuthMethodAPIKey, KeyID: "key-2"}
+	jwt := &common.Principal{Subject: "owner", Method: common.AuthMethodJWT, KeyID: "token"}
+
+	assert.Equal(t, http.StatusOK, serveRateLimited(handler, http.MethodGet, "/test", "", first).Code)
+	assert.Equal(t, http.StatusTooManyRequests, serveRateLimited(handler, http.MethodGet, "/test", "", first).Code)
+	assert.Equal(t, http.StatusOK, serveRateLimited(handler, http.MethodGet, "/test", "", second).Code)
+
+	// Requests without an API key are not limited by the policy
+	for i := 0; i < 2; i++ {
+		assert.Equal(t, http.StatusOK, serveRateLimited(handler, http.MethodGet, "/test", "", nil).Code)
+		assert.Equal(t, http.StatusOK, serveRateLimited(handler, http.MethodGet, "/test", "", jwt).Code)
+	}
+}
+
+func TestRateLimiter_Policies_Profile(t *testing.T) {
+	handler := newTestPolicyRateLimiter(t,
+		RateLimitPolicy{Name: "contacts", Route: "POST /api/v1/profiles/{id}/contacts", Key: RateLimitKeyProfile, Quota: Quota{Limit: 1, Window: time.Minute}},
+	)
+
+	assert.Equal(t, http.StatusOK, serveRateLimited(handler, http.MethodPost, "/api/v1/profiles/p1/contacts", "", nil).Code)
+	assert.Equal(t, http.StatusTooManyRequests, serveRateLimited(handler, http.MethodPost, "/api/v1/profiles/p1/contacts", "", nil).Code)
+	assert.Equal(t, http.StatusOK, serveRateLimited(handler, http.MethodPost, "/api/v1/profiles/p2/contacts", "", nil).Code)
+}
+
+func TestRateLimiter_Policies_Email(t *testing.T) {
+	handler := newTestPolicyRateLimiter(t,
+		RateLimitPolicy{Name: "contacts", Route: "POST /api/v1/profiles/{id}/contacts", Key: RateLimitKeyEmail, Quota: Quota{Limit: 1, Window: time.Minute}},
+	)
+	path := "/api/v1/profiles/p1/contacts"
+
+	body := `{"name":"Ada","email":"ada@example.com"}`
+	rr := serveRateLimited(handler, http.MethodPost, path, body, nil)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, body, rr.Body.String(), "the body is restored for the handler")
+
+	// Emails are compared case-insensitively
+	assert.Equal(t, http.StatusTooManyRequests, serveRateLimited(handler, http.MethodPost, path, `{"email":" ADA@example.com"}`, nil).Code)
+	assert.Equal(t, http.StatusOK, serveRateLimited(handler, http.MethodPost, path, `{"email":"grace@example.com"}`, nil).Code)
+
+	// Bodies without an email are left to the handler to reject
+	rr = serveRateLimited(handler, http.MethodPost, path, `not json`, nil)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "not json", rr.Body.String())
+}
+
+func TestEmailFromBody_ReplaysReadError(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/test", strings.NewReader(`{"email":"ada@example.com"}`))
+	rr := httptest.NewRecorder()
+	req.Body = http.MaxBytesReader(rr, req.Body, 8)
+
+	assert.Empty(t, emailFromBody(req))
+
+	data, err := io.ReadAll(req.Body)
+	assert.Equal(t, `{"email"`, string(data))
+	var maxBytesErr *http.MaxBytesError
+	assert.ErrorAs(t, err, &maxBytesErr)
+}
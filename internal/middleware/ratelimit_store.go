@@ -0,0 +1,124 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/mrthoabby/portfolio-api/internal/common/contracts"
+	"github.com/mrthoabby/portfolio-api/internal/common/types"
+)
+
+// RateLimitsStore is the store holding the counters of every StoreRateLimitBackend
+const RateLimitsStore = "rate_limits"
+
+// StoreRateLimitBackend keeps rate limit counters in a shared store, so limits hold across
+// instances and restarts. It approximates a sliding window from two fixed-window counters:
+// the previous window's count weighs in proportionally to how much of it still overlaps.
+// Rejected requests are counted too, so clients that keep retrying stay limited.
+// Counters expire through a TTL index once they no longer overlap the current window.
+type StoreRateLimitBackend struct {
+	store contracts.Store
+	now   func() time.Time
+}
+
+var _ RateLimitBackend = (*StoreRateLimitBackend)(nil)
+
+type rateLimitCounter struct {
+	ID        string    `bson:"_id"`
+	Count     int       `bson:"count"`
+	ExpiresAt time.Time `bson:"expiresAt"`
+}
+
+// NewStoreRateLimitBackend creates a backend keeping its counters in the rate_limits store
+func NewStoreRateLimitBackend(dataSource contracts.DataSource) *StoreRateLimitBackend {
+	return &StoreRateLimitBackend{
+		store: dataSource.Store(RateLimitsStore),
+		now:   time.Now,
+	}
+}
+
+// EnsureIndexes creates the TTL index that removes expired counters
+func (b *StoreRateLimitBackend) EnsureIndexes(ctx context.Context) error {
+	expireAt := time.Duration(0)
+	return b.store.EnsureIndex(ctx, contracts.Index{Fields: []string{"expiresAt"}, ExpireAfter: &expireAt})
+}
+
+// Allow records a request for key and reports whether it fits the quota.
+// The burst raises the number of requests allowed per window.
+func (b *StoreRateLimitBackend) Allow(ctx context.Context, key string, quota Quota) (RateLimitResult, error) {
+	window := quota.Window
+	now := b.now()
+	index := now.UnixNano() / int64(window)
+	windowStart := time.Unix(0, index*int64(window)).UTC()
+	elapsed := now.Sub(windowStart)
+
+	previous, err := b.count(ctx, b.counterID(key, index-1))
+	if err != nil {
+		return RateLimitResult{}, err
+	}
+
+	var current rateLimitCounter
+	err = b.store.FindOneAndUpdate(ctx,
+		map[string]interface{}{"_id": b.counterID(key, index)},
+		contracts.Update{
+			Inc: map[string]interface{}{"count": 1},
+			// The counter is read as the previous window until the end of the next one
+			SetOnInsert: map[string]interface{}{"expiresAt": windowStart.Add(2 * window)},
+		},
+		contracts.FindOneAndUpdateOptions{Upsert: true},
+		&current,
+	)
+	if err != nil {
+		return RateLimitResult{}, err
+	}
+
+	return slidingWindowResult(previous, current.Count, quota.Capacity(), elapsed, window), nil
+}
+
+func (b *StoreRateLimitBackend) count(ctx context.Context, id string) (int, error) {
+	var counter rateLimitCounter
+	err := b.store.FindOne(ctx, map[string]interface{}{"_id": id}, &counter)
+	if errors.Is(err, types.ErrNotFound{}) {
+		return 0, nil
+	}
+	return counter.Count, err
+}
+
+func (b *StoreRateLimitBackend) counterID(key string, index int64) string {
+	return key + ":" + strconv.FormatInt(index, 10)
+}
+
+// slidingWindowResult evaluates a request already added to current, elapsed into the current window
+func slidingWindowResult(previous, current, limit int, elapsed, window time.Duration) RateLimitResult {
+	overlap := 1 - float64(elapsed)/float64(window)
+	estimate := float64(previous)*overlap + float64(current)
+	remaining := window - elapsed
+
+	result := RateLimitResult{
+		Allowed:   estimate <= float64(limit),
+		Limit:     limit,
+		Remaining: int(math.Max(0, math.Floor(float64(limit)-estimate))),
+		Reset:     remaining,
+	}
+	if current > 0 {
+		// The current window's requests weigh in until the end of the next one
+		result.Reset += window
+	}
+	if result.Allowed {
+		return result
+	}
+
+	// Find when one more request fits: previous*overlap + current + 1 <= limit
+	if current+1 <= limit {
+		needed := 1 - float64(limit-current-1)/float64(previous)
+		result.RetryAfter = time.Duration(needed*float64(window)) - elapsed
+		return result
+	}
+	// Not within this window: in the next one, current becomes the previous count
+	needed := 1 - float64(limit-1)/float64(current)
+	result.RetryAfter = remaining + time.Duration(needed*float64(window))
+	return result
+}
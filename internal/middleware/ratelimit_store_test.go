@@ -0,0 +1,224 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mrthoabby/portfolio-api/internal/common/contracts"
+	"github.com/mrthoabby/portfolio-api/internal/common/types"
+)
+
+// counterStore is an in-memory contracts.Store holding rate limit counters
+type counterStore struct {
+	contracts.Store
+	syncMutex sync.Mutex
+	counters  map[string]rateLimitCounter
+	indexes   []contracts.Index
+	err       error
+}
+
+func newCounterStore() *counterStore {
+	return &counterStore{counters: make(map[string]rateLimitCounter)}
+}
+
+func (s *counterStore) FindOne(_ context.Context, filter map[string]interface{}, result interface{}) error {
+	s.syncMutex.Lock()
+	defer s.syncMutex.Unlock()
+
+	if s.err != nil {
+		return s.err
+	}
+	counter, ok := s.counters[filter["_id"].(string)]
+	if !ok {
+		return types.ErrNotFound{Message: "record not found"}
+	}
+	*result.(*rateLimitCounter) = counter
+	return nil
+}
+
+func (s *counterStore) FindOneAndUpdate(_ context.Context, filter map[string]interface{}, update contracts.Update, _ contracts.FindOneAndUpdateOptions, result interface{}) error {
+	s.syncMutex.Lock()
+	defer s.syncMutex.Unlock()
+
+	if s.err != nil {
+		return s.err
+	}
+	id := filter["_id"].(string)
+	counter, ok := s.counters[id]
+	if !ok {
+		counter = rateLimitCounter{ID: id, ExpiresAt: update.SetOnInsert["expiresAt"].(time.Time)}
+	}
+	counter.Count += update.Inc["count"].(int)
+	s.counters[id] = counter
+
+	*result.(*rateLimitCounter) = counter
+	return nil
+}
+
+func (s *counterStore) EnsureIndex(_ context.Context, index contracts.Index) error {
+	s.indexes = append(s.indexes, index)
+	return nil
+}
+
+type counterDataSource struct {
+	contracts.DataSource
+	store *counterStore
+}
+
+func (d counterDataSource) Store(name string) contracts.Store {
+	return d.store
+}
+
+func newTestStoreBackend(store *counterStore, now time.Time) (*StoreRateLimitBackend, *fakeClock) {
+	clock := &fakeClock{now: now}
+	backend := NewStoreRateLimitBackend(counterDataSource{store: store})
+	backend.now = clock.Now
+	return backend, clock
+}
+
+func TestStoreRateLimitBackend_SharedAcrossInstances(t *testing.T) {
+	store := newCounterStore()
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	first, _ := newTestStoreBackend(store, start)
+	second, _ := newTestStoreBackend(store, start)
+	quota := Quota{Limit: 3, Window: time.Minute}
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		result, err := first.Allow(ctx, "contacts:203.0.113.7", quota)
+		assert.NoError(t, err)
+		assert.True(t, result.Allowed)
+	}
+
+	result, err := second.Allow(ctx, "contacts:203.0.113.7", quota)
+	assert.NoError(t, err)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+
+	result, err = second.Allow(ctx, "contacts:203.0.113.7", quota)
+	assert.NoError(t, err)
+	assert.False(t, result.Allowed)
+
+	// Other keys keep separate counters
+	result, err = first.Allow(ctx, "questions:203.0.113.7", quota)
+	assert.NoError(t, err)
+	assert.True(t, result.Allowed)
+
+	counter := store.counters["contacts:203.0.113.7:"+"28928160"]
+	assert.Equal(t, 4, counter.Count)
+	assert.Equal(t, start.Add(2*time.Minute), counter.ExpiresAt)
+}
+
+func TestStoreRateLimitBackend_PreviousWindowWeighsIn(t *testing.T) {
+	store := newCounterStore()
+	backend, clock := newTestStoreBackend(store, time.Date(2025, 1, 1, 0, 0, 30, 0, time.UTC))
+	quota := Quota{Limit: 4, Window: time.Minute}
+	ctx := context.Background()
+
+	for i := 0; i < 4; i++ {
+		backend.Allow(ctx, "client", quota)
+	}
+
+	// 15s into the next window, 3/4 of the previous window still overlaps: 4*0.75 + 1 = 4
+	clock.Advance(45 * time.Second)
+	result, err := backend.Allow(ctx, "client", quota)
+	assert.NoError(t, err)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+
+	// 4*0.75 + 2 = 5
+	result, err = backend.Allow(ctx, "client", quota)
+	assert.NoError(t, err)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, 30*time.Second, result.RetryAfter)
+}
+
+func TestStoreRateLimitBackend_BurstRaisesLimit(t *testing.T) {
+	store := newCounterStore()
+	backend, _ := newTestStoreBackend(store, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	quota := Quota{Limit: 2, Window: time.Minute, Burst: 1}
+	ctx := context.Background()
+
+	for i := 2; i >= 0; i-- {
+		result, err := backend.Allow(ctx, "client", quota)
+		assert.NoError(t, err)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, 3, result.Limit)
+		assert.Equal(t, i, result.Remaining)
+	}
+
+	result, err := backend.Allow(ctx, "client", quota)
+	assert.NoError(t, err)
+	assert.False(t, result.Allowed)
+}
+
+func TestStoreRateLimitBackend_EnsureIndexes(t *testing.T) {
+	store := newCounterStore()
+	backend, _ := newTestStoreBackend(store, time.Now())
+
+	assert.NoError(t, backend.EnsureIndexes(context.Background()))
+	assert.Len(t, store.indexes, 1)
+	assert.Equal(t, []string{"expiresAt"}, store.indexes[0].Fields)
+	assert.Equal(t, time.Duration(0), *store.indexes[0].ExpireAfter)
+}
+
+func Test_slidingWindowResult(t *testing.T) {
+	tests := []struct {
+		name              string
+		previous, current int
+		elapsed           time.Duration
+		expected          RateLimitResult
+	}{
+		{
+			name: "first request", previous: 0, current: 1, elapsed: 10 * time.Second,
+			expected: RateLimitResult{Allowed: true, Limit: 10, Remaining: 9, Reset: 110 * time.Second},
+		},
+		{
+			name: "previous window only", previous: 10, current: 1, elapsed: 30 * time.Second,
+			expected: RateLimitResult{Allowed: true, Limit: 10, Remaining: 4, Reset: 90 * time.Second},
+		},
+		{
+			name: "previous window fills the limit", previous: 10, current: 6, elapsed: 30 * time.Second,
+			expected: RateLimitResult{Allowed: false, Limit: 10, Remaining: 0, Reset: 90 * time.Second, RetryAfter: 12 * time.Second},
+		},
+		{
+			name: "current window over the limit", previous: 0, current: 12, elapsed: 30 * time.Second,
+			expected: RateLimitResult{Allowed: false, Limit: 10, Remaining: 0, Reset: 90 * time.Second, RetryAfter: 45 * time.Second},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := slidingWindowResult(tt.previous, tt.current, 10, tt.elapsed, time.Minute)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestRateLimiter_FallsBackToMemoryWhenBackendFails(t *testing.T) {
+	store := newCounterStore()
+	store.err = types.ErrUnavailable{Message: "database unavailable", Cause: errors.New("dial tcp")}
+	backend, _ := newTestStoreBackend(store, time.Now())
+
+	limiter := NewRateLimiter(1, time.Minute, WithBackend(backend))
+	defer limiter.Stop()
+
+	handler := limiter.Limit(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	for _, expected := range []int{http.StatusOK, http.StatusTooManyRequests} {
+		req := httptest.NewRequest(http.MethodGet, "/test", nil)
+		req.RemoteAddr = "192.168.1.1:1234"
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, expected, rr.Code)
+	}
+}
@@ -1,6 +1,8 @@
 package middleware
 
 import (
+	"context"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -78,3 +80,174 @@ func TestRateLimiter_DifferentIPsHaveSeparateLimits(t *testing.T) {
 	assert.Equal(t, http.StatusOK, rr.Code)
 }
 
+func TestRateLimiter_IgnoresSpoofedForwardedFor(t *testing.T) {
+	limiter := NewRateLimiter(1, time.Minute)
+
+	handler := ClientIP(nil)(limiter.Limit(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})))
+
+	for i, expected := range []int{http.StatusOK, http.StatusTooManyRequests} {
+		req := httptest.NewRequest(http.MethodGet, "/test", nil)
+		req.Header.Set("X-Forwarded-For", fmt.Sprintf("1.2.3.%d", i))
+		req.RemoteAddr = "192.168.1.1:1234"
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, expected, rr.Code)
+	}
+}
+
+func TestRateLimiter_GroupsIPv6ByPrefix(t *testing.T) {
+	limiter := NewRateLimiter(1, time.Minute)
+
+	handler := limiter.Limit(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		remoteAddr string
+		expected   int
+	}{
+		{"[2001:db8:1:2::1]:1234", http.StatusOK},
+		{"[2001:db8:1:2::2]:1234", http.StatusTooManyRequests}, // same /64
+		{"[2001:db8:1:3::1]:1234", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/test", nil)
+		req.RemoteAddr = tt.remoteAddr
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, tt.expected, rr.Code, tt.remoteAddr)
+	}
+
+	perAddress := NewRateLimiter(1, time.Minute, WithIPv6Prefix(128))
+	defer perAddress.Stop()
+	quota := Quota{Limit: 1, Window: time.Minute}
+	assert.True(t, perAddress.Allow(context.Background(), rateLimitKey("2001:db8:1:2::1", perAddress.ipv6Prefix), quota).Allowed)
+	assert.True(t, perAddress.Allow(context.Background(), rateLimitKey("2001:db8:1:2::2", perAddress.ipv6Prefix), quota).Allowed)
+}
+
+// fakeClock lets tests move a limiter through time
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func (c *fakeClock) Advance(d time.Duration) { c.now = c.now.Add(d) }
+
+func newTestRateLimiter(limit int, window time.Duration, opts ...RateLimiterOption) (*RateLimiter, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	limiter := NewRateLimiter(limit, window, opts...)
+	limiter.memory.now = clock.Now
+	return limiter, clock
+}
+
+func TestRateLimiter_Allow_RestoresQuotaGradually(t *testing.T) {
+	limiter, clock := newTestRateLimiter(5, time.Minute)
+	defer limiter.Stop()
+	quota := Quota{Limit: 5, Window: time.Minute}
+
+	for i := 4; i >= 0; i-- {
+		result := limiter.Allow(context.Background(), "client", quota)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, i, result.Remaining)
+	}
+
+	result := limiter.Allow(context.Background(), "client", quota)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+	assert.Equal(t, 12*time.Second, result.RetryAfter)
+	assert.Equal(t, time.Minute, result.Reset)
+
+	// One request is restored every window / limit
+	clock.Advance(12 * time.Second)
+	result = limiter.Allow(context.Background(), "client", quota)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+	assert.False(t, limiter.Allow(context.Background(), "client", quota).Allowed)
+
+	// The whole quota is back after a full window
+	clock.Advance(time.Minute)
+	result = limiter.Allow(context.Background(), "client", quota)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 4, result.Remaining)
+}
+
+func TestRateLimiter_Limit_Headers(t *testing.T) {
+	limiter, clock := newTestRateLimiter(2, 10*time.Second)
+	defer limiter.Stop()
+
+	handler := limiter.Limit(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	serve := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/test", nil)
+		req.RemoteAddr = "192.168.1.1:1234"
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+
+	rr := serve()
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "2;w=10", rr.Header().Get("RateLimit-Policy"))
+	assert.Equal(t, "2", rr.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "1", rr.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "5", rr.Header().Get("RateLimit-Reset"))
+
+	serve()
+	clock.Advance(1500 * time.Millisecond)
+	rr = serve()
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+	assert.Equal(t, "4", rr.Header().Get("Retry-After"))
+	assert.Equal(t, "0", rr.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "9", rr.Header().Get("RateLimit-Reset"))
+	assert.Contains(t, rr.Body.String(), "RATE_LIMIT_EXCEEDED")
+}
+
+func TestRateLimiter_EvictsLeastRecentlySeenKeys(t *testing.T) {
+	limiter, _ := newTestRateLimiter(1, time.Minute, WithMaxKeys(2))
+	defer limiter.Stop()
+	quota := Quota{Limit: 1, Window: time.Minute}
+
+	assert.True(t, limiter.Allow(context.Background(), "a", quota).Allowed)
+	assert.True(t, limiter.Allow(context.Background(), "b", quota).Allowed)
+	assert.False(t, limiter.Allow(context.Background(), "a", quota).Allowed) // a is now the most recently seen
+	assert.True(t, limiter.Allow(context.Background(), "c", quota).Allowed)  // evicts b
+
+	assert.Len(t, limiter.memory.keys, 2)
+	assert.NotContains(t, limiter.memory.keys, "b")
+	assert.False(t, limiter.Allow(context.Background(), "a", quota).Allowed)
+}
+
+func TestRateLimiter_RemoveExpired(t *testing.T) {
+	limiter, clock := newTestRateLimiter(2, time.Minute)
+	defer limiter.Stop()
+	quota := Quota{Limit: 2, Window: time.Minute}
+
+	limiter.Allow(context.Background(), "a", quota)
+	clock.Advance(20 * time.Second)
+	limiter.Allow(context.Background(), "b", quota)
+	limiter.Allow(context.Background(), "b", quota)
+
+	clock.Advance(15 * time.Second)
+	limiter.memory.removeExpired()
+
+	assert.NotContains(t, limiter.memory.keys, "a")
+	assert.Contains(t, limiter.memory.keys, "b")
+	assert.Equal(t, 1, limiter.memory.recent.Len())
+}
+
+func TestRateLimiter_Stop(t *testing.T) {
+	limiter := NewRateLimiter(1, time.Minute)
+	quota := Quota{Limit: 1, Window: time.Minute}
+
+	limiter.Stop()
+	limiter.Stop() // safe to call twice
+
+	assert.True(t, limiter.Allow(context.Background(), "a", quota).Allowed)
+	assert.False(t, limiter.Allow(context.Background(), "a", quota).Allowed)
+}
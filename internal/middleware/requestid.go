@@ -5,11 +5,9 @@ import (
 	"net/http"
 
 	"github.com/google/uuid"
-)
-
-type contextKey string
 
-const RequestIDKey contextKey = "requestID"
+	"github.com/mrthoabby/portfolio-api/internal/common"
+)
 
 // RequestID adds a unique request ID to each request
 func RequestID(next http.Handler) http.Handler {
@@ -24,15 +22,12 @@ func RequestID(next http.Handler) http.Handler {
 		w.Header().Set("X-Request-ID", requestID)
 
 		// Add to context for use in handlers/logging
-		ctx := context.WithValue(r.Context(), RequestIDKey, requestID)
+		ctx := common.WithRequestID(r.Context(), requestID)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
 
 // GetRequestID retrieves the request ID from context
 func GetRequestID(ctx context.Context) string {
-	if id, ok := ctx.Value(RequestIDKey).(string); ok {
-		return id
-	}
-	return ""
+	return common.RequestIDFromContext(ctx)
 }
@@ -0,0 +1,97 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/mrthoabby/portfolio-api/internal/events"
+)
+
+// AutoReplier emails the sender of a contact to confirm their message was received, or, when the
+// contact waits for verification, the link delivering it. Spam is never replied to. The emails
+// open with the auto-reply text of the profile settings when there is one. As the address was
+// typed in by whoever filled in the form, the emails never repeat the submitted name or message.
+type AutoReplier struct {
+	mailer    Mailer
+	profiles  ProfileReader
+	settings  SettingsReader
+	verifyURL string
+}
+
+var _ events.Handler = (*AutoReplier)(nil)
+
+// NewAutoReplier creates an auto-replier sending email through mailer. Verification links point
+// to verifyURL, the public address of GET /api/v1/contacts/verify.
+func NewAutoReplier(mailer Mailer, profiles ProfileReader, settings SettingsReader, verifyURL string) *AutoReplier {
+	return &AutoReplier{
+		mailer:    mailer,
+		profiles:  profiles,
+		settings:  settings,
+		verifyURL: verifyURL,
+	}
+}
+
+// Handle implements events.Handler
+func (a *AutoReplier) Handle(ctx context.Context, event events.Event) error {
+	data := templateData{}
+	var name, to string
+
+	switch event.Type {
+	case events.TypeContactCreated:
+		if err := event.Decode(&data.Contact); err != nil {
+			return err
+		}
+		// Verified senders already got the verification email
+		if data.Contact.Spam || data.Contact.Verified {
+			return nil
+		}
+		name = "contact_confirmation"
+		to = data.Contact.Email
+	case events.TypeContactVerificationRequested:
+		if err := event.Decode(&data.Verification); err != nil {
+			return err
+		}
+		link, err := a.verifyLink(data.Verification.Token)
+		if err != nil {
+			return err
+		}
+		data.VerifyLink = link
+		name = "contact_verification"
+		to = data.Verification.Email
+	default:
+		return nil
+	}
+
+	profileSettings, err := a.settings.ForProfile(ctx, event.ProfileID)
+	if err != nil {
+		return err
+	}
+	data.Greeting = profileSettings.AutoReplyMessage
+	data.ProfileName = profileName(ctx, a.profiles, event.ProfileID)
+
+	message, err := render(name, data)
+	if err != nil {
+		return err
+	}
+	message.To = []string{to}
+	if event.Type == events.TypeContactVerificationRequested {
+		message.Subject = "Confirm your message to " + data.ProfileName
+	} else {
+		message.Subject = "Your message to " + data.ProfileName + " was received"
+	}
+
+	return a.mailer.Send(ctx, message)
+}
+
+// verifyLink adds the token to the verification URL
+func (a *AutoReplier) verifyLink(token string) (string, error) {
+	link, err := url.Parse(a.verifyURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid verification URL: %w", err)
+	}
+	query := link.Query()
+	query.Set("token", token)
+	link.RawQuery = query.Encode()
+	return link.String(), nil
+}
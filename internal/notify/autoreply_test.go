@@ -0,0 +1,116 @@
+package notify
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrthoabby/portfolio-api/internal/application/settings"
+	"github.com/mrthoabby/portfolio-api/internal/events"
+)
+
+func TestAutoReplier_ContactCreated(t *testing.T) {
+	mailer := &fakeMailer{}
+	replier := NewAutoReplier(mailer, fakeProfiles{"profile-1": "Ada"}, fakeSettings{}, "")
+
+	err := replier.Handle(context.Background(), newEvent(t, events.TypeContactCreated, "profile-1", events.ContactCreated{
+		ContactID: "c1",
+		Name:      "O&#39;Brien",
+		Email:     "obrien@example.com",
+		Message:   "Loved the talk &amp; demo",
+	}))
+	require.NoError(t, err)
+
+	require.Len(t, mailer.sent, 1)
+	message := mailer.sent[0]
+	assert.Equal(t, []string{"obrien@example.com"}, message.To)
+	assert.Equal(t, "Your message to Ada was received", message.Subject)
+	assert.Contains(t, message.Text, "Hi,\n\nThanks for getting in touch. Your message to Ada was received.")
+
+	// The address is unverified, so nothing the submitter typed is sent to it
+	for _, body := range []string{message.Text, message.HTML} {
+		assert.NotContains(t, body, "Brien")
+		assert.NotContains(t, body, "Loved the talk")
+	}
+}
+
+func TestAutoReplier_Skips(t *testing.T) {
+	tests := []struct {
+		name  string
+		event events.Event
+	}{
+		{"spam", newEvent(t, events.TypeContactCreated, "profile-1", events.ContactCreated{Email: "bot@example.com", Spam: true})},
+		{"verified", newEvent(t, events.TypeContactCreated, "profile-1", events.ContactCreated{Email: "ada@example.com", Verified: true})},
+		{"question", newEvent(t, events.TypeQuestionCreated, "profile-1", events.QuestionCreated{Message: "Why?"})},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mailer := &fakeMailer{}
+			replier := NewAutoReplier(mailer, fakeProfiles{}, fakeSettings{}, "")
+
+			require.NoError(t, replier.Handle(context.Background(), tt.event))
+			assert.Empty(t, mailer.sent)
+		})
+	}
+}
+
+func TestAutoReplier_VerificationRequested(t *testing.T) {
+	mailer := &fakeMailer{}
+	replier := NewAutoReplier(mailer, fakeProfiles{"profile-1": "Ada"}, fakeSettings{}, "https://example.com/api/v1/contacts/verify?lang=en")
+
+	err := replier.Handle(context.Background(), newEvent(t, events.TypeContactVerificationRequested, "profile-1", events.ContactVerificationRequested{
+		ContactID: "c1",
+		Name:      "Grace",
+		Email:     "grace@example.com",
+		Message:   "Hello there, friend",
+		Token:     "c1.1767366245.sig+/=",
+		ExpiresAt: time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC),
+	}))
+	require.NoError(t, err)
+
+	require.Len(t, mailer.sent, 1)
+	message := mailer.sent[0]
+	assert.Equal(t, []string{"grace@example.com"}, message.To)
+	assert.Equal(t, "Confirm your message to Ada", message.Subject)
+
+	link := "https://example.com/api/v1/contacts/verify?lang=en&token=c1.1767366245.sig%2B%2F%3D"
+	assert.Contains(t, message.Text, link)
+	assert.Contains(t, message.Text, "Fri, 02 Jan 2026 15:04 UTC")
+	assert.Contains(t, message.HTML, `href="https://example.com/api/v1/contacts/verify?lang=en&amp;token=c1.1767366245.sig%2B%2F%3D"`)
+
+	for _, body := range []string{message.Text, message.HTML} {
+		assert.NotContains(t, body, "Grace")
+		assert.NotContains(t, body, "Hello there")
+	}
+}
+
+func TestAutoReplier_Greeting(t *testing.T) {
+	greeted := settings.Defaults("profile-1")
+	greeted.AutoReplyMessage = "Thanks! I answer within two days <3"
+	mailer := &fakeMailer{}
+	replier := NewAutoReplier(mailer, fakeProfiles{"profile-1": "Ada"}, fakeSettings{"profile-1": greeted}, "https://example.com/verify")
+
+	err := replier.Handle(context.Background(), newEvent(t, events.TypeContactCreated, "profile-1", events.ContactCreated{
+		Name:    "Grace",
+		Email:   "grace@example.com",
+		Message: "Hello there, friend",
+	}))
+	require.NoError(t, err)
+	err = replier.Handle(context.Background(), newEvent(t, events.TypeContactVerificationRequested, "profile-1", events.ContactVerificationRequested{
+		Name:    "Grace",
+		Email:   "grace@example.com",
+		Message: "Hello there, friend",
+		Token:   "token",
+	}))
+	require.NoError(t, err)
+
+	require.Len(t, mailer.sent, 2)
+	for _, message := range mailer.sent {
+		assert.Contains(t, message.Text, "Hi,\n\nThanks! I answer within two days <3\n\n")
+		assert.Contains(t, message.HTML, "Thanks! I answer within two days &lt;3")
+	}
+	assert.NotContains(t, mailer.sent[0].Text, "Thanks for getting in touch")
+}
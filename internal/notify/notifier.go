@@ -0,0 +1,165 @@
+// Package notify emails profile owners when something happens on their profile.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"fmt"
+	"html"
+	htmltemplate "html/template"
+	texttemplate "text/template"
+
+	"github.com/mrthoabby/portfolio-api/internal/application/profile"
+	"github.com/mrthoabby/portfolio-api/internal/application/settings"
+	"github.com/mrthoabby/portfolio-api/internal/events"
+)
+
+//go:embed templates
+var templateFS embed.FS
+
+var (
+	textTemplates = texttemplate.Must(texttemplate.ParseFS(templateFS, "templates/*.txt"))
+	htmlTemplates = htmltemplate.Must(htmltemplate.ParseFS(templateFS, "templates/*.html"))
+)
+
+// Recipients resolves who is notified about the events of a profile
+type Recipients interface {
+	Recipients(ctx context.Context, profileID string) ([]string, error)
+}
+
+// StaticRecipients notifies the same addresses about every profile
+type StaticRecipients []string
+
+// Recipients implements Recipients
+func (r StaticRecipients) Recipients(context.Context, string) ([]string, error) {
+	return r, nil
+}
+
+// SettingsReader looks up the settings of the profile an event belongs to
+type SettingsReader interface {
+	ForProfile(ctx context.Context, profileID string) (*settings.Settings, error)
+}
+
+// ProfileRecipients notifies the addresses chosen in the profile settings, or Default when none were
+type ProfileRecipients struct {
+	Settings SettingsReader
+	Default  []string
+}
+
+// Recipients implements Recipients
+func (r ProfileRecipients) Recipients(ctx context.Context, profileID string) ([]string, error) {
+	profileSettings, err := r.Settings.ForProfile(ctx, profileID)
+	if err != nil {
+		return nil, err
+	}
+	if len(profileSettings.Notifications.Emails) > 0 {
+		return profileSettings.Notifications.Emails, nil
+	}
+	return r.Default, nil
+}
+
+// ProfileReader looks up the profile an event belongs to
+type ProfileReader interface {
+	GetByID(ctx context.Context, id string) (*profile.Profile, error)
+}
+
+// Notifier emails the recipients of a profile about new contacts and questions.
+// Contacts flagged as spam are not notified.
+type Notifier struct {
+	mailer     Mailer
+	recipients Recipients
+	profiles   ProfileReader
+}
+
+var _ events.Handler = (*Notifier)(nil)
+
+// NewNotifier creates a notifier sending email through mailer
+func NewNotifier(mailer Mailer, recipients Recipients, profiles ProfileReader) *Notifier {
+	return &Notifier{
+		mailer:     mailer,
+		recipients: recipients,
+		profiles:   profiles,
+	}
+}
+
+type templateData struct {
+	ProfileName  string
+	Contact      events.ContactCreated
+	Question     events.QuestionCreated
+	Verification events.ContactVerificationRequested
+	VerifyLink   string
+	// Greeting is the auto-reply text chosen in the profile settings, if any
+	Greeting string
+}
+
+// Handle implements events.Handler
+func (n *Notifier) Handle(ctx context.Context, event events.Event) error {
+	data := templateData{}
+	var name, subject string
+
+	switch event.Type {
+	case events.TypeContactCreated:
+		if err := event.Decode(&data.Contact); err != nil {
+			return err
+		}
+		if data.Contact.Spam {
+			return nil
+		}
+		// Stored text is HTML-escaped by the input sanitizer; the templates escape it themselves
+		data.Contact.Name = html.UnescapeString(data.Contact.Name)
+		data.Contact.Message = html.UnescapeString(data.Contact.Message)
+		name = "contact_created"
+		subject = "New contact from " + data.Contact.Name
+	case events.TypeQuestionCreated:
+		if err := event.Decode(&data.Question); err != nil {
+			return err
+		}
+		data.Question.Message = html.UnescapeString(data.Question.Message)
+		name = "question_created"
+		subject = "New question"
+	default:
+		return nil
+	}
+
+	to, err := n.recipients.Recipients(ctx, event.ProfileID)
+	if err != nil {
+		return err
+	}
+	if len(to) == 0 {
+		return nil
+	}
+
+	data.ProfileName = profileName(ctx, n.profiles, event.ProfileID)
+	if event.Type == events.TypeQuestionCreated {
+		subject = "New question for " + data.ProfileName
+	}
+
+	message, err := render(name, data)
+	if err != nil {
+		return err
+	}
+	message.To = to
+	message.Subject = subject
+
+	return n.mailer.Send(ctx, message)
+}
+
+// profileName names the profile in the email, falling back to its ID when it cannot be read
+func profileName(ctx context.Context, profiles ProfileReader, profileID string) string {
+	if found, err := profiles.GetByID(ctx, profileID); err == nil && found.Name != "" {
+		return found.Name
+	}
+	return profileID
+}
+
+func render(name string, data templateData) (Message, error) {
+	var text, body bytes.Buffer
+	if err := textTemplates.ExecuteTemplate(&text, name+".txt", data); err != nil {
+		return Message{}, fmt.Errorf("failed to render %s text: %w", name, err)
+	}
+	if err := htmlTemplates.ExecuteTemplate(&body, name+".html", data); err != nil {
+		return Message{}, fmt.Errorf("failed to render %s html: %w", name, err)
+	}
+	return Message{Text: text.String(), HTML: body.String()}, nil
+}
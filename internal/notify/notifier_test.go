@@ -0,0 +1,156 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrthoabby/portfolio-api/internal/application/profile"
+	"github.com/mrthoabby/portfolio-api/internal/application/settings"
+	"github.com/mrthoabby/portfolio-api/internal/events"
+)
+
+type fakeMailer struct {
+	sent []Message
+}
+
+func (m *fakeMailer) Send(_ context.Context, message Message) error {
+	m.sent = append(m.sent, message)
+	return nil
+}
+
+type fakeProfiles map[string]string
+
+func (p fakeProfiles) GetByID(_ context.Context, id string) (*profile.Profile, error) {
+	name, ok := p[id]
+	if !ok {
+		return nil, errors.New("profile not found")
+	}
+	return &profile.Profile{ID: id, Name: name}, nil
+}
+
+// fakeSettings returns the stored settings of a profile, or the defaults
+type fakeSettings map[string]*settings.Settings
+
+func (f fakeSettings) ForProfile(_ context.Context, profileID string) (*settings.Settings, error) {
+	if profileSettings, ok := f[profileID]; ok {
+		return profileSettings, nil
+	}
+	return settings.Defaults(profileID), nil
+}
+
+func newEvent(t *testing.T, eventType, profileID string, payload interface{}) events.Event {
+	t.Helper()
+	event, err := events.New(eventType, profileID, payload)
+	require.NoError(t, err)
+	return event
+}
+
+func TestNotifier_ContactCreated(t *testing.T) {
+	mailer := &fakeMailer{}
+	notifier := NewNotifier(mailer, StaticRecipients{"owner@example.com"}, fakeProfiles{"profile-1": "Ada"})
+
+	// Stored contacts are HTML-escaped; the email must neither show nor double the escaping
+	err := notifier.Handle(context.Background(), newEvent(t, events.TypeContactCreated, "profile-1", events.ContactCreated{
+		ContactID: "c1",
+		Name:      "O&#39;Brien",
+		Email:     "obrien@example.com",
+		Message:   "Loved the &lt;script&gt; talk &amp; demo",
+		CreatedAt: time.Date(2026, 1, 2, 15, 4, 0, 0, time.UTC),
+	}))
+	require.NoError(t, err)
+
+	require.Len(t, mailer.sent, 1)
+	message := mailer.sent[0]
+	assert.Equal(t, []string{"owner@example.com"}, message.To)
+	assert.Equal(t, "New contact from O'Brien", message.Subject)
+	assert.Contains(t, message.Text, "O'Brien <obrien@example.com> sent a contact message to Ada")
+	assert.Contains(t, message.Text, "Loved the <script> talk & demo")
+	assert.Contains(t, message.Text, "contact c1")
+	assert.Contains(t, message.HTML, "Loved the &lt;script&gt; talk &amp; demo")
+	assert.NotContains(t, message.HTML, "<script>")
+}
+
+func TestNotifier_SkipsSpam(t *testing.T) {
+	mailer := &fakeMailer{}
+	notifier := NewNotifier(mailer, StaticRecipients{"owner@example.com"}, fakeProfiles{})
+
+	err := notifier.Handle(context.Background(), newEvent(t, events.TypeContactCreated, "profile-1", events.ContactCreated{Spam: true}))
+	require.NoError(t, err)
+	assert.Empty(t, mailer.sent)
+}
+
+func TestNotifier_QuestionCreated(t *testing.T) {
+	mailer := &fakeMailer{}
+	notifier := NewNotifier(mailer, StaticRecipients{"owner@example.com", "team@example.com"}, fakeProfiles{})
+
+	err := notifier.Handle(context.Background(), newEvent(t, events.TypeQuestionCreated, "profile-1", events.QuestionCreated{
+		QuestionID: "q1",
+		Message:    "Do you do &quot;remote&quot; work?",
+	}))
+	require.NoError(t, err)
+
+	require.Len(t, mailer.sent, 1)
+	message := mailer.sent[0]
+	assert.Equal(t, []string{"owner@example.com", "team@example.com"}, message.To)
+	assert.Equal(t, "New question for profile-1", message.Subject, "falls back to the profile ID")
+	assert.Contains(t, message.Text, `Do you do "remote" work?`)
+}
+
+func TestNotifier_IgnoresOtherEvents(t *testing.T) {
+	mailer := &fakeMailer{}
+	notifier := NewNotifier(mailer, StaticRecipients{"owner@example.com"}, fakeProfiles{})
+
+	err := notifier.Handle(context.Background(), events.Event{Type: "project.published", ProfileID: "profile-1"})
+	require.NoError(t, err)
+
+	err = notifier.Handle(context.Background(), newEvent(t, events.TypeQuestionCreated, "profile-1", events.QuestionCreated{}))
+	require.NoError(t, err)
+	assert.Len(t, mailer.sent, 1)
+
+	notifier = NewNotifier(mailer, StaticRecipients{}, fakeProfiles{})
+	err = notifier.Handle(context.Background(), newEvent(t, events.TypeQuestionCreated, "profile-1", events.QuestionCreated{}))
+	require.NoError(t, err)
+	assert.Len(t, mailer.sent, 1, "nothing is sent without recipients")
+}
+
+func TestNotifier_DeliversOverSMTP(t *testing.T) {
+	server, tlsConfig := newSMTPServer(t, true)
+	mailer := NewSMTPMailer(SMTPConfig{
+		Host:      "127.0.0.1",
+		Port:      server.port(),
+		From:      "noreply@example.com",
+		StartTLS:  true,
+		TLSConfig: tlsConfig,
+	})
+	notifier := NewNotifier(mailer, StaticRecipients{"owner@example.com"}, fakeProfiles{"profile-1": "Ada"})
+
+	err := notifier.Handle(context.Background(), newEvent(t, events.TypeQuestionCreated, "profile-1", events.QuestionCreated{QuestionID: "q1", Message: "Hello?"}))
+	require.NoError(t, err)
+
+	server.syncMutex.Lock()
+	defer server.syncMutex.Unlock()
+	assert.Equal(t, []string{"owner@example.com"}, server.to)
+	assert.Contains(t, string(server.data), "Someone asked Ada a question")
+}
+
+func TestProfileRecipients(t *testing.T) {
+	routed := settings.Defaults("profile-2")
+	routed.Notifications.Emails = []string{"sales@example.com"}
+	recipients := ProfileRecipients{
+		Settings: fakeSettings{"profile-2": routed},
+		Default:  []string{"owner@example.com"},
+	}
+
+	to, err := recipients.Recipients(context.Background(), "profile-1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"owner@example.com"}, to)
+
+	to, err = recipients.Recipients(context.Background(), "profile-2")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"sales@example.com"}, to)
+}
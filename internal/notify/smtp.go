@@ -0,0 +1,201 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dialTimeout bounds connecting to the SMTP server when the context has no deadline
+const dialTimeout = 10 * time.Second
+
+// ErrStartTLSUnsupported is returned when STARTTLS is required but the server does not offer it
+var ErrStartTLSUnsupported = errors.New("smtp server does not support STARTTLS")
+
+// Message is an email with a plain text and an HTML body
+type Message struct {
+	To      []string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// Mailer sends email
+type Mailer interface {
+	Send(ctx context.Context, message Message) error
+}
+
+// SMTPConfig configures an SMTPMailer
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+	// StartTLS upgrades the connection before authenticating and refuses servers that cannot
+	StartTLS bool
+	// TLSConfig overrides the TLS settings used by STARTTLS; the server name defaults to Host
+	TLSConfig *tls.Config
+}
+
+// SMTPMailer sends email through an SMTP server, one connection per message
+type SMTPMailer struct {
+	config SMTPConfig
+}
+
+var _ Mailer = (*SMTPMailer)(nil)
+
+// NewSMTPMailer creates a mailer for the given server
+func NewSMTPMailer(config SMTPConfig) *SMTPMailer {
+	return &SMTPMailer{config: config}
+}
+
+// Send implements Mailer
+func (m *SMTPMailer) Send(ctx context.Context, message Message) error {
+	data, err := m.compose(message)
+	if err != nil {
+		return err
+	}
+
+	addr := net.JoinHostPort(m.config.Host, strconv.Itoa(m.config.Port))
+	dialer := net.Dialer{Timeout: dialTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to connect to smtp server: %w", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, m.config.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to start smtp session: %w", err)
+	}
+	defer client.Close()
+
+	if m.config.StartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return ErrStartTLSUnsupported
+		}
+		tlsConfig := &tls.Config{ServerName: m.config.Host, MinVersion: tls.VersionTLS12}
+		if m.config.TLSConfig != nil {
+			tlsConfig = m.config.TLSConfig.Clone()
+			if tlsConfig.ServerName == "" {
+				tlsConfig.ServerName = m.config.Host
+			}
+		}
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return fmt.Errorf("smtp STARTTLS failed: %w", err)
+		}
+	}
+
+	if m.config.Username != "" {
+		auth := smtp.PlainAuth("", m.config.Username, m.config.Password, m.config.Host)
+		if err := client.Auth(auth); err != nil {
+			return fmt.Errorf("smtp authentication failed: %w", err)
+		}
+	}
+
+	if err := client.Mail(m.fromAddress()); err != nil {
+		return fmt.Errorf("smtp MAIL FROM rejected: %w", err)
+	}
+	for _, to := range message.To {
+		if err := client.Rcpt(to); err != nil {
+			return fmt.Errorf("smtp RCPT TO %s rejected: %w", to, err)
+		}
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("smtp DATA rejected: %w", err)
+	}
+	if _, err := writer.Write(data); err != nil {
+		return fmt.Errorf("failed to write smtp message: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("smtp message rejected: %w", err)
+	}
+
+	return client.Quit()
+}
+
+// fromAddress returns the bare address of From, which may include a display name
+func (m *SMTPMailer) fromAddress() string {
+	if address, err := mail.ParseAddress(m.config.From); err == nil {
+		return address.Address
+	}
+	return m.config.From
+}
+
+// compose renders message as a multipart/alternative MIME message
+func (m *SMTPMailer) compose(message Message) ([]byte, error) {
+	var buf bytes.Buffer
+	body := multipart.NewWriter(&buf)
+
+	var out bytes.Buffer
+	// Subject may carry user input: encoding it also keeps line breaks out of the headers
+	for _, header := range [][2]string{
+		{"From", m.config.From},
+		{"To", strings.Join(message.To, ", ")},
+		{"Subject", mime.QEncoding.Encode("utf-8", message.Subject)},
+		{"Date", time.Now().Format(time.RFC1123Z)},
+		{"Message-ID", m.messageID()},
+		{"MIME-Version", "1.0"},
+		{"Content-Type", "multipart/alternative; boundary=" + body.Boundary()},
+	} {
+		out.WriteString(header[0] + ": " + header[1] + "\r\n")
+	}
+	out.WriteString("\r\n")
+
+	for _, part := range []struct{ contentType, content string }{
+		{"text/plain; charset=utf-8", message.Text},
+		{"text/html; charset=utf-8", message.HTML},
+	} {
+		writer, err := body.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		encoder := quotedprintable.NewWriter(writer)
+		if _, err := encoder.Write([]byte(part.content)); err != nil {
+			return nil, err
+		}
+		if err := encoder.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := body.Close(); err != nil {
+		return nil, err
+	}
+
+	out.Write(buf.Bytes())
+	return out.Bytes(), nil
+}
+
+func (m *SMTPMailer) messageID() string {
+	random := make([]byte, 16)
+	rand.Read(random)
+
+	domain := m.config.Host
+	if at := strings.LastIndex(m.fromAddress(), "@"); at >= 0 {
+		domain = m.fromAddress()[at+1:]
+	}
+	return "<" + hex.EncodeToString(random) + "@" + domain + ">"
+}
@@ -0,0 +1,281 @@
+package notify
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"io"
+	"math/big"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// smtpServer is an in-process SMTP stand-in recording what clients send
+type smtpServer struct {
+	listener  net.Listener
+	tlsConfig *tls.Config
+	startTLS  bool
+
+	syncMutex sync.Mutex
+	usedTLS   bool
+	auth      string
+	from      string
+	to        []string
+	data      []byte
+}
+
+func newSMTPServer(t *testing.T, startTLS bool) (*smtpServer, *tls.Config) {
+	t.Helper()
+
+	certificate, roots := selfSignedCertificate(t)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	server := &smtpServer{
+		listener:  listener,
+		tlsConfig: &tls.Config{Certificates: []tls.Certificate{certificate}},
+		startTLS:  startTLS,
+	}
+	go server.serve()
+
+	return server, &tls.Config{RootCAs: roots}
+}
+
+func (s *smtpServer) port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (s *smtpServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.session(conn)
+	}
+}
+
+func (s *smtpServer) session(conn net.Conn) {
+	defer func() { conn.Close() }()
+
+	text := textproto.NewConn(conn)
+	text.PrintfLine("220 127.0.0.1 ESMTP test")
+	secure := false
+
+	for {
+		line, err := text.ReadLine()
+		if err != nil {
+			return
+		}
+		verb, arg, _ := strings.Cut(line, " ")
+
+		switch strings.ToUpper(verb) {
+		case "EHLO", "HELO":
+			text.PrintfLine("250-127.0.0.1")
+			if s.startTLS && !secure {
+				text.PrintfLine("250-STARTTLS")
+			}
+			text.PrintfLine("250 AUTH PLAIN")
+		case "STARTTLS":
+			text.PrintfLine("220 ready to start TLS")
+			tlsConn := tls.Server(conn, s.tlsConfig)
+			if err := tlsConn.Handshake(); err != nil {
+				return
+			}
+			conn = tlsConn
+			text = textproto.NewConn(conn)
+			secure = true
+		case "AUTH":
+			_, initial, _ := strings.Cut(arg, " ")
+			decoded, _ := base64.StdEncoding.DecodeString(initial)
+			s.record(func() { s.auth = string(decoded) })
+			text.PrintfLine("235 authenticated")
+		case "MAIL":
+			s.record(func() { s.from = address(arg); s.usedTLS = secure })
+			text.PrintfLine("250 ok")
+		case "RCPT":
+			s.record(func() { s.to = append(s.to, address(arg)) })
+			text.PrintfLine("250 ok")
+		case "DATA":
+			text.PrintfLine("354 go ahead")
+			data, err := text.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			s.record(func() { s.data = data })
+			text.PrintfLine("250 queued")
+		case "QUIT":
+			text.PrintfLine("221 bye")
+			return
+		default:
+			text.PrintfLine("502 not implemented")
+		}
+	}
+}
+
+func (s *smtpServer) record(update func()) {
+	s.syncMutex.Lock()
+	defer s.syncMutex.Unlock()
+	update()
+}
+
+// address extracts the address of a "FROM:<a@b>" or "TO:<a@b>" argument
+func address(arg string) string {
+	start, end := strings.Index(arg, "<"), strings.Index(arg, ">")
+	if start < 0 || end < start {
+		return arg
+	}
+	return arg[start+1 : end]
+}
+
+func selfSignedCertificate(t *testing.T) (tls.Certificate, *x509.CertPool) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "127.0.0.1"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	parsed, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(parsed)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, roots
+}
+
+// readParts returns the decoded bodies of a multipart/alternative message by content type
+func readParts(t *testing.T, message *mail.Message) map[string]string {
+	t.Helper()
+
+	mediaType, params, err := mime.ParseMediaType(message.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	require.Equal(t, "multipart/alternative", mediaType)
+
+	parts := map[string]string{}
+	reader := multipart.NewReader(message.Body, params["boundary"])
+	for {
+		part, err := reader.NextRawPart()
+		if err == io.EOF {
+			return parts
+		}
+		require.NoError(t, err)
+		body, err := io.ReadAll(quotedprintable.NewReader(part))
+		require.NoError(t, err)
+		contentType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		parts[contentType] = string(body)
+	}
+}
+
+func TestSMTPMailer_Send(t *testing.T) {
+	server, tlsConfig := newSMTPServer(t, true)
+	mailer := NewSMTPMailer(SMTPConfig{
+		Host:      "127.0.0.1",
+		Port:      server.port(),
+		Username:  "user",
+		Password:  "secret",
+		From:      "Portfolio <noreply@example.com>",
+		StartTLS:  true,
+		TLSConfig: tlsConfig,
+	})
+
+	err := mailer.Send(context.Background(), Message{
+		To:      []string{"owner@example.com", "team@example.com"},
+		Subject: "New contact from Zoë\r\nBcc: evil@example.com",
+		Text:    "Hello there",
+		HTML:    "<p>Hello there</p>",
+	})
+	require.NoError(t, err)
+
+	server.syncMutex.Lock()
+	defer server.syncMutex.Unlock()
+	assert.True(t, server.usedTLS, "the session must be upgraded before sending")
+	assert.Equal(t, "\x00user\x00secret", server.auth)
+	assert.Equal(t, "noreply@example.com", server.from)
+	assert.Equal(t, []string{"owner@example.com", "team@example.com"}, server.to)
+
+	message, err := mail.ReadMessage(strings.NewReader(string(server.data)))
+	require.NoError(t, err)
+	assert.Empty(t, message.Header.Get("Bcc"), "line breaks in the subject must not inject headers")
+	subject, err := new(mime.WordDecoder).DecodeHeader(message.Header.Get("Subject"))
+	require.NoError(t, err)
+	assert.Equal(t, "New contact from Zoë\r\nBcc: evil@example.com", subject)
+	assert.Equal(t, "Portfolio <noreply@example.com>", message.Header.Get("From"))
+	assert.Contains(t, message.Header.Get("Message-ID"), "@example.com>")
+
+	parts := readParts(t, message)
+	assert.Equal(t, "Hello there", parts["text/plain"])
+	assert.Equal(t, "<p>Hello there</p>", parts["text/html"])
+}
+
+func TestSMTPMailer_Send_RequiresStartTLS(t *testing.T) {
+	server, tlsConfig := newSMTPServer(t, false)
+	mailer := NewSMTPMailer(SMTPConfig{
+		Host:      "127.0.0.1",
+		Port:      server.port(),
+		From:      "noreply@example.com",
+		StartTLS:  true,
+		TLSConfig: tlsConfig,
+	})
+
+	err := mailer.Send(context.Background(), Message{To: []string{"owner@example.com"}, Subject: "Hi", Text: "Hi"})
+	assert.ErrorIs(t, err, ErrStartTLSUnsupported)
+
+	server.syncMutex.Lock()
+	defer server.syncMutex.Unlock()
+	assert.Empty(t, server.data)
+}
+
+func TestSMTPMailer_Send_Plain(t *testing.T) {
+	server, _ := newSMTPServer(t, false)
+	mailer := NewSMTPMailer(SMTPConfig{
+		Host: "127.0.0.1",
+		Port: server.port(),
+		From: "noreply@example.com",
+	})
+
+	err := mailer.Send(context.Background(), Message{To: []string{"owner@example.com"}, Subject: "Hi", Text: "Hi", HTML: "<p>Hi</p>"})
+	require.NoError(t, err)
+
+	server.syncMutex.Lock()
+	defer server.syncMutex.Unlock()
+	assert.False(t, server.usedTLS)
+	assert.Empty(t, server.auth, "no credentials are sent without a username")
+	assert.NotEmpty(t, server.data)
+}
+
+func TestSMTPMailer_Send_Unreachable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	mailer := NewSMTPMailer(SMTPConfig{Host: "127.0.0.1", Port: port, From: "noreply@example.com"})
+	err = mailer.Send(context.Background(), Message{To: []string{"owner@example.com"}})
+	assert.Error(t, err)
+}
@@ -0,0 +1,319 @@
+// Package outbox delivers events reliably. Events are written to the outbox in the same
+// transaction as the records they describe, then handed to their subscribers in the background
+// until each subscriber accepts them, so a crash can delay a side effect but never lose it.
+package outbox
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/mrthoabby/portfolio-api/internal/common/contracts"
+	"github.com/mrthoabby/portfolio-api/internal/common/logger"
+	"github.com/mrthoabby/portfolio-api/internal/common/types"
+	"github.com/mrthoabby/portfolio-api/internal/events"
+)
+
+// Entry statuses
+const (
+	// StatusPending entries are waiting for their next delivery attempt, or being delivered
+	StatusPending = "pending"
+	// StatusDelivered entries were accepted by their subscriber
+	StatusDelivered = "delivered"
+	// StatusDead entries failed every attempt and are kept for inspection
+	StatusDead = "dead"
+)
+
+// Dispatcher defaults
+const (
+	DefaultPollInterval = time.Second
+	DefaultLease        = time.Minute
+	DefaultMaxAttempts  = 10
+	DefaultBaseBackoff  = 30 * time.Second
+	DefaultMaxBackoff   = 6 * time.Hour
+	DefaultRetention    = 7 * 24 * time.Hour
+)
+
+// Entry is the delivery of one event to one subscriber
+type Entry struct {
+	ID         string       `bson:"_id"`
+	Subscriber string       `bson:"subscriber"`
+	Event      events.Event `bson:"event"`
+	Status     string       `bson:"status"`
+	Attempts   int          `bson:"attempts"`
+	// NextAttemptAt is when a pending entry is due. Claiming an entry pushes it back by the lease,
+	// so an entry whose dispatcher died is picked up again once the lease runs out.
+	NextAttemptAt time.Time  `bson:"nextAttemptAt"`
+	LeaseOwner    string     `bson:"leaseOwner,omitempty"`
+	LastError     string     `bson:"lastError,omitempty"`
+	DeliveredAt   *time.Time `bson:"deliveredAt,omitempty"`
+	CreatedAt     time.Time  `bson:"createdAt"`
+	UpdatedAt     time.Time  `bson:"updatedAt"`
+}
+
+// Subscriber receives every event published to the outbox. Its name identifies its entries,
+// so it must not change while entries are pending.
+type Subscriber struct {
+	Name    string
+	Handler events.Handler
+}
+
+// Config tunes the dispatcher. Zero values take the defaults.
+type Config struct {
+	// PollInterval is how often the outbox is checked for due entries
+	PollInterval time.Duration
+	// Lease is how long a claimed entry is reserved for its dispatcher, bounding each delivery
+	Lease time.Duration
+	// MaxAttempts is the number of attempts after which an entry is dead
+	MaxAttempts int
+	// BaseBackoff is the delay after the first failure, doubled after each further one up to MaxBackoff
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+	// Retention is how long delivered entries are kept
+	Retention time.Duration
+}
+
+// Outbox is an events.Publisher writing events to the "outbox" store and dispatching them
+type Outbox struct {
+	store       contracts.Store
+	subscribers map[string]events.Handler
+	names       []string
+	config      Config
+	logger      logger.Logger
+	now         func() time.Time
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	running  sync.WaitGroup
+}
+
+var _ events.Publisher = (*Outbox)(nil)
+
+// New creates an outbox delivering events to subscribers. Start must be called to dispatch them.
+func New(dataSource contracts.DataSource, appLogger logger.Logger, config Config, subscribers ...Subscriber) *Outbox {
+	if config.PollInterval <= 0 {
+		config.PollInterval = DefaultPollInterval
+	}
+	if config.Lease <= 0 {
+		config.Lease = DefaultLease
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = DefaultMaxAttempts
+	}
+	if config.BaseBackoff <= 0 {
+		config.BaseBackoff = DefaultBaseBackoff
+	}
+	if config.MaxBackoff <= 0 {
+		config.MaxBackoff = DefaultMaxBackoff
+	}
+	if config.Retention <= 0 {
+		config.Retention = DefaultRetention
+	}
+
+	outbox := &Outbox{
+		store:       dataSource.Store("outbox"),
+		subscribers: make(map[string]events.Handler, len(subscribers)),
+		config:      config,
+		logger:      appLogger,
+		now:         time.Now,
+		stop:        make(chan struct{}),
+	}
+	for _, subscriber := range subscribers {
+		outbox.subscribers[subscriber.Name] = subscriber.Handler
+		outbox.names = append(outbox.names, subscriber.Name)
+	}
+	return outbox
+}
+
+// EnsureIndexes creates the indexes used to claim due entries and expire delivered ones
+func (o *Outbox) EnsureIndexes(ctx context.Context) error {
+	if err := o.store.EnsureIndex(ctx, contracts.Index{Fields: []string{"status", "nextAttemptAt"}}); err != nil {
+		return err
+	}
+	retention := o.config.Retention
+	return o.store.EnsureIndex(ctx, contracts.Index{Fields: []string{"deliveredAt"}, ExpireAfter: &retention})
+}
+
+// Publish writes one entry per subscriber. Given the context of a transaction, the entries are
+// only written if the transaction commits.
+func (o *Outbox) Publish(ctx context.Context, event events.Event) error {
+	now := o.now()
+	for _, name := range o.names {
+		entry := &Entry{
+			ID:            event.ID + ":" + name,
+			Subscriber:    name,
+			Event:         event,
+			Status:        StatusPending,
+			NextAttemptAt: now,
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		}
+		if err := o.store.InsertOne(ctx, entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Start dispatches due entries in the background until Stop is called
+func (o *Outbox) Start() {
+	o.running.Add(1)
+	go o.run()
+}
+
+// Stop ends dispatching, interrupting the delivery in progress, which is retried later
+func (o *Outbox) Stop() {
+	o.stopOnce.Do(func() {
+		close(o.stop)
+	})
+	o.running.Wait()
+}
+
+func (o *Outbox) run() {
+	defer o.running.Done()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-o.stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	ticker := time.NewTicker(o.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		o.dispatch(ctx)
+
+		select {
+		case <-o.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// dispatch delivers due entries until none is left
+func (o *Outbox) dispatch(ctx context.Context) {
+	for ctx.Err() == nil {
+		entry, err := o.claim(ctx)
+		if err != nil {
+			var notFound types.ErrNotFound
+			if !errors.As(err, &notFound) && ctx.Err() == nil {
+				o.logger.Error("Failed to claim outbox entry", logger.Error(err))
+			}
+			return
+		}
+		o.deliver(ctx, entry)
+	}
+}
+
+// claim reserves the oldest due entry for the duration of the lease
+func (o *Outbox) claim(ctx context.Context) (*Entry, error) {
+	now := o.now()
+	var entry Entry
+	err := o.store.FindOneAndUpdate(ctx,
+		map[string]interface{}{
+			"status":        StatusPending,
+			"nextAttemptAt": map[string]interface{}{"$lte": now},
+		},
+		contracts.Update{
+			Set: map[string]interface{}{
+				"leaseOwner":    uuid.New().String(),
+				"nextAttemptAt": now.Add(o.config.Lease),
+				"updatedAt":     now,
+			},
+			Inc: map[string]interface{}{"attempts": 1},
+		},
+		contracts.FindOneAndUpdateOptions{Sort: []string{"nextAttemptAt"}},
+		&entry,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func (o *Outbox) deliver(ctx context.Context, entry *Entry) {
+	err := o.handle(ctx, entry)
+	now := o.now()
+
+	update := contracts.Update{
+		Set:   map[string]interface{}{"updatedAt": now},
+		Unset: []string{"leaseOwner"},
+	}
+	switch {
+	case err == nil:
+		update.Set["status"] = StatusDelivered
+		update.Set["deliveredAt"] = now
+		update.Unset = append(update.Unset, "lastError")
+	case entry.Attempts >= o.config.MaxAttempts:
+		update.Set["status"] = StatusDead
+		update.Set["lastError"] = err.Error()
+		o.logger.Error("Outbox entry failed its last attempt",
+			logger.String("entry_id", entry.ID),
+			logger.String("event_type", entry.Event.Type),
+			logger.Int("attempts", entry.Attempts),
+			logger.Error(err),
+		)
+	default:
+		update.Set["nextAttemptAt"] = now.Add(o.backoff(entry.Attempts))
+		update.Set["lastError"] = err.Error()
+		o.logger.Warn("Outbox delivery failed, retrying later",
+			logger.String("entry_id", entry.ID),
+			logger.String("event_type", entry.Event.Type),
+			logger.Int("attempts", entry.Attempts),
+			logger.Error(err),
+		)
+	}
+
+	// The lease owner guards against overwriting an entry that was claimed again after the lease ran out.
+	// Outlive a cancelled dispatch so the outcome of the attempt is not lost.
+	saveCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), o.config.Lease)
+	defer cancel()
+	err = o.store.UpdateOneWith(saveCtx, map[string]interface{}{"_id": entry.ID, "leaseOwner": entry.LeaseOwner}, update)
+	if err != nil {
+		o.logger.Error("Failed to record outbox delivery",
+			logger.String("entry_id", entry.ID),
+			logger.Error(err),
+		)
+	}
+}
+
+// handle runs the subscriber within the lease, turning panics into errors
+func (o *Outbox) handle(ctx context.Context, entry *Entry) (err error) {
+	handler, ok := o.subscribers[entry.Subscriber]
+	if !ok {
+		return errors.New("unknown subscriber " + entry.Subscriber)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, o.config.Lease)
+	defer cancel()
+
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = errors.New("subscriber panicked")
+			o.logger.Error("Outbox subscriber panicked",
+				logger.String("entry_id", entry.ID),
+				logger.NewField("panic", recovered),
+			)
+		}
+	}()
+
+	return handler.Handle(ctx, entry.Event)
+}
+
+// backoff is the delay before the attempt following the given number of failed ones
+func (o *Outbox) backoff(attempts int) time.Duration {
+	delay := o.config.BaseBackoff
+	for i := 1; i < attempts && delay < o.config.MaxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, o.config.MaxBackoff)
+}
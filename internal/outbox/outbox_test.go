@@ -0,0 +1,293 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrthoabby/portfolio-api/internal/common/contracts"
+	"github.com/mrthoabby/portfolio-api/internal/common/logger"
+	"github.com/mrthoabby/portfolio-api/internal/common/types"
+	"github.com/mrthoabby/portfolio-api/internal/events"
+)
+
+// entryStore keeps outbox entries in memory, understanding the filters and updates the outbox uses
+type entryStore struct {
+	contracts.Store
+	syncMutex sync.Mutex
+	entries   map[string]*Entry
+}
+
+func (s *entryStore) InsertOne(_ context.Context, record interface{}) error {
+	s.syncMutex.Lock()
+	defer s.syncMutex.Unlock()
+
+	entry := *record.(*Entry)
+	if _, exists := s.entries[entry.ID]; exists {
+		return types.ErrConflict{Message: "record already exists"}
+	}
+	s.entries[entry.ID] = &entry
+	return nil
+}
+
+func (s *entryStore) FindOneAndUpdate(_ context.Context, filter map[string]interface{}, update contracts.Update, _ contracts.FindOneAndUpdateOptions, result interface{}) error {
+	s.syncMutex.Lock()
+	defer s.syncMutex.Unlock()
+
+	due := filter["nextAttemptAt"].(map[string]interface{})["$lte"].(time.Time)
+	var candidates []*Entry
+	for _, entry := range s.entries {
+		if entry.Status == filter["status"] && !entry.NextAttemptAt.After(due) {
+			candidates = append(candidates, entry)
+		}
+	}
+	if len(candidates) == 0 {
+		return types.ErrNotFound{Message: "record not found"}
+	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].NextAttemptAt.Before(candidates[j].NextAttemptAt) })
+
+	entry := candidates[0]
+	apply(entry, update)
+	entry.Attempts += update.Inc["attempts"].(int)
+	*result.(*Entry) = *entry
+	return nil
+}
+
+func (s *entryStore) UpdateOneWith(_ context.Context, filter map[string]interface{}, update contracts.Update) error {
+	s.syncMutex.Lock()
+	defer s.syncMutex.Unlock()
+
+	entry, ok := s.entries[filter["_id"].(string)]
+	if !ok || entry.LeaseOwner != filter["leaseOwner"] {
+		return types.ErrNotFound{Message: "record not found"}
+	}
+	apply(entry, update)
+	return nil
+}
+
+func (s *entryStore) get(id string) Entry {
+	s.syncMutex.Lock()
+	defer s.syncMutex.Unlock()
+	return *s.entries[id]
+}
+
+func apply(entry *Entry, update contracts.Update) {
+	for field, value := range update.Set {
+		switch field {
+		case "status":
+			entry.Status = value.(string)
+		case "leaseOwner":
+			entry.LeaseOwner = value.(string)
+		case "nextAttemptAt":
+			entry.NextAttemptAt = value.(time.Time)
+		case "lastError":
+			entry.LastError = value.(string)
+		case "deliveredAt":
+			deliveredAt := value.(time.Time)
+			entry.DeliveredAt = &deliveredAt
+		case "updatedAt":
+			entry.UpdatedAt = value.(time.Time)
+		}
+	}
+	for _, field := range update.Unset {
+		switch field {
+		case "leaseOwner":
+			entry.LeaseOwner = ""
+		case "lastError":
+			entry.LastError = ""
+		}
+	}
+}
+
+type storeDataSource struct {
+	contracts.DataSource
+	store *entryStore
+}
+
+func (d storeDataSource) Store(string) contracts.Store {
+	return d.store
+}
+
+type nopLogger struct{}
+
+func (nopLogger) Debug(string, ...logger.Field)               {}
+func (nopLogger) Info(string, ...logger.Field)                {}
+func (nopLogger) Warn(string, ...logger.Field)                {}
+func (nopLogger) Error(string, ...logger.Field)               {}
+func (l nopLogger) With(...logger.Field) logger.Logger        { return l }
+func (l nopLogger) WithContext(context.Context) logger.Logger { return l }
+
+type handlerFunc func(ctx context.Context, event events.Event) error
+
+func (f handlerFunc) Handle(ctx context.Context, event events.Event) error {
+	return f(ctx, event)
+}
+
+// newTestOutbox creates an outbox whose clock only moves when advance is called
+func newTestOutbox(config Config, subscribers ...Subscriber) (*Outbox, *entryStore, func(time.Duration)) {
+	store := &entryStore{entries: map[string]*Entry{}}
+	outbox := New(storeDataSource{store: store}, nopLogger{}, config, subscribers...)
+
+	var syncMutex sync.Mutex
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	outbox.now = func() time.Time {
+		syncMutex.Lock()
+		defer syncMutex.Unlock()
+		return now
+	}
+	advance := func(d time.Duration) {
+		syncMutex.Lock()
+		defer syncMutex.Unlock()
+		now = now.Add(d)
+	}
+	return outbox, store, advance
+}
+
+func TestOutbox_PublishWritesOneEntryPerSubscriber(t *testing.T) {
+	ok := handlerFunc(func(context.Context, events.Event) error { return nil })
+	outbox, store, _ := newTestOutbox(Config{}, Subscriber{"email", ok}, Subscriber{"webhooks", ok})
+
+	event, err := events.New(events.TypeQuestionCreated, "profile-1", events.QuestionCreated{QuestionID: "q1"})
+	require.NoError(t, err)
+	require.NoError(t, outbox.Publish(context.Background(), event))
+
+	require.Len(t, store.entries, 2)
+	entry := store.get(event.ID + ":email")
+	assert.Equal(t, StatusPending, entry.Status)
+	assert.Equal(t, event, entry.Event)
+	assert.Equal(t, "webhooks", store.get(event.ID+":webhooks").Subscriber)
+
+	var conflict types.ErrConflict
+	assert.ErrorAs(t, outbox.Publish(context.Background(), event), &conflict, "the same event is only written once")
+}
+
+func TestOutbox_DeliversDueEntries(t *testing.T) {
+	var received []string
+	outbox, store, _ := newTestOutbox(Config{}, Subscriber{"email", handlerFunc(func(_ context.Context, event events.Event) error {
+		received = append(received, event.ID)
+		return nil
+	})})
+
+	for _, id := range []string{"e1", "e2"} {
+		require.NoError(t, outbox.Publish(context.Background(), events.Event{ID: id}))
+	}
+	outbox.dispatch(context.Background())
+
+	assert.ElementsMatch(t, []string{"e1", "e2"}, received)
+	entry := store.get("e1:email")
+	assert.Equal(t, StatusDelivered, entry.Status)
+	assert.Equal(t, 1, entry.Attempts)
+	assert.NotNil(t, entry.DeliveredAt)
+	assert.Empty(t, entry.LeaseOwner)
+
+	outbox.dispatch(context.Background())
+	assert.Len(t, received, 2, "delivered entries are not delivered again")
+}
+
+func TestOutbox_RetriesWithBackoffThenDeadLetters(t *testing.T) {
+	attempts := 0
+	outbox, store, advance := newTestOutbox(Config{MaxAttempts: 3, BaseBackoff: time.Minute},
+		Subscriber{"email", handlerFunc(func(context.Context, events.Event) error {
+			attempts++
+			return errors.New("smtp down")
+		})},
+	)
+	require.NoError(t, outbox.Publish(context.Background(), events.Event{ID: "e1"}))
+
+	outbox.dispatch(context.Background())
+	entry := store.get("e1:email")
+	assert.Equal(t, StatusPending, entry.Status)
+	assert.Equal(t, "smtp down", entry.LastError)
+	assert.Equal(t, outbox.now().Add(time.Minute), entry.NextAttemptAt)
+
+	outbox.dispatch(context.Background())
+	assert.Equal(t, 1, attempts, "not retried before the backoff")
+
+	advance(time.Minute)
+	outbox.dispatch(context.Background())
+	assert.Equal(t, 2, attempts)
+	assert.Equal(t, outbox.now().Add(2*time.Minute), store.get("e1:email").NextAttemptAt)
+
+	advance(2 * time.Minute)
+	outbox.dispatch(context.Background())
+	assert.Equal(t, 3, attempts)
+	entry = store.get("e1:email")
+	assert.Equal(t, StatusDead, entry.Status)
+	assert.Equal(t, 3, entry.Attempts)
+
+	advance(time.Hour)
+	outbox.dispatch(context.Background())
+	assert.Equal(t, 3, attempts, "dead entries are not retried")
+}
+
+func TestOutbox_ReclaimsExpiredLeases(t *testing.T) {
+	outbox, store, advance := newTestOutbox(Config{Lease: time.Minute},
+		Subscriber{"email", handlerFunc(func(context.Context, events.Event) error { return nil })},
+	)
+	require.NoError(t, outbox.Publish(context.Background(), events.Event{ID: "e1"}))
+
+	// A dispatcher claims the entry and dies before recording the outcome
+	stale, err := outbox.claim(context.Background())
+	require.NoError(t, err)
+	_, err = outbox.claim(context.Background())
+	assert.Error(t, err, "leased entries are not claimed twice")
+
+	advance(time.Minute)
+	outbox.dispatch(context.Background())
+	entry := store.get("e1:email")
+	assert.Equal(t, StatusDelivered, entry.Status)
+	assert.Equal(t, 2, entry.Attempts)
+
+	// The stale dispatcher cannot overwrite the outcome once its lease was taken over
+	outbox.deliver(context.Background(), stale)
+	assert.Equal(t, entry, store.get("e1:email"))
+}
+
+func TestOutbox_RecoversPanickingSubscribers(t *testing.T) {
+	outbox, store, _ := newTestOutbox(Config{},
+		Subscriber{"email", handlerFunc(func(context.Context, events.Event) error { panic("boom") })},
+	)
+	require.NoError(t, outbox.Publish(context.Background(), events.Event{ID: "e1"}))
+
+	outbox.dispatch(context.Background())
+	entry := store.get("e1:email")
+	assert.Equal(t, StatusPending, entry.Status)
+	assert.Equal(t, "subscriber panicked", entry.LastError)
+}
+
+func TestOutbox_StartAndStop(t *testing.T) {
+	delivered := make(chan string, 1)
+	outbox, _, _ := newTestOutbox(Config{PollInterval: time.Millisecond},
+		Subscriber{"email", handlerFunc(func(_ context.Context, event events.Event) error {
+			delivered <- event.ID
+			return nil
+		})},
+	)
+
+	outbox.Start()
+	require.NoError(t, outbox.Publish(context.Background(), events.Event{ID: "e1"}))
+	select {
+	case id := <-delivered:
+		assert.Equal(t, "e1", id)
+	case <-time.After(time.Second):
+		t.Fatal("entry was not dispatched")
+	}
+	outbox.Stop()
+	outbox.Stop()
+}
+
+func TestOutbox_Backoff(t *testing.T) {
+	outbox, _, _ := newTestOutbox(Config{BaseBackoff: time.Second, MaxBackoff: 10 * time.Second})
+
+	assert.Equal(t, time.Second, outbox.backoff(1))
+	assert.Equal(t, 2*time.Second, outbox.backoff(2))
+	assert.Equal(t, 8*time.Second, outbox.backoff(4))
+	assert.Equal(t, 10*time.Second, outbox.backoff(5))
+	assert.Equal(t, 10*time.Second, outbox.backoff(100))
+}
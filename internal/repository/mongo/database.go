@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"time"
 
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 
@@ -91,6 +92,12 @@ func NewDataSource(connectionString, databaseName string, logs logger.Logger) (*
 		return nil, fmt.Errorf("failed to ping MongoDB: %w", err)
 	}
 
+	if err := checkTransactionSupport(pingCtx, client); err != nil {
+		logs.Error("Deployment does not support transactions", logger.Error(err))
+		client.Disconnect(context.Background())
+		return nil, err
+	}
+
 	logs.Info("Successfully connected and verified to database",
 		logger.String("database", databaseName),
 	)
@@ -119,3 +126,42 @@ func (d *DataSource) Close() error {
 func (d *DataSource) Ping(ctx context.Context) error {
 	return d.client.Ping(ctx, nil)
 }
+
+// checkTransactionSupport fails unless the server is a replica set member or a mongos router,
+// as writes done in transactions would otherwise fail on every request.
+func checkTransactionSupport(ctx context.Context, client *mongo.Client) error {
+	var hello struct {
+		SetName string `bson:"setName"`
+		Msg     string `bson:"msg"`
+	}
+	admin := client.Database("admin")
+	err := admin.RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&hello)
+	if err != nil {
+		// Servers older than 4.4.2 only know the legacy command
+		err = admin.RunCommand(ctx, bson.D{{Key: "isMaster", Value: 1}}).Decode(&hello)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to check MongoDB topology: %w", err)
+	}
+
+	if hello.SetName == "" && hello.Msg != "isdbgrid" {
+		return fmt.Errorf("MongoDB is a standalone server, which does not support transactions: " +
+			"run it as a replica set (a single node is enough) or connect to a sharded cluster")
+	}
+	return nil
+}
+
+// WithTransaction runs fn in a MongoDB transaction, retrying it on transient errors.
+// Transactions require a replica set or a sharded cluster.
+func (d *DataSource) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	session, err := d.client.StartSession()
+	if err != nil {
+		return wrapError(err)
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sessionCtx mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sessionCtx)
+	})
+	return wrapError(err)
+}
@@ -2,12 +2,14 @@ package mongo
 
 import (
 	"context"
+	"errors"
 	"strings"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 
+	"github.com/mrthoabby/portfolio-api/internal/common/contracts"
 	"github.com/mrthoabby/portfolio-api/internal/common/types"
 )
 
@@ -29,56 +31,190 @@ func (s *Store) FindOne(ctx context.Context, filter map[string]interface{}, resu
 		if err == mongo.ErrNoDocuments {
 			return types.ErrNotFound{Message: "record not found"}
 		}
-		return err
+		return wrapError(err)
 	}
 	return nil
 }
 
 // FindMany finds all records matching the filter with optional sorting.
 func (s *Store) FindMany(ctx context.Context, filter map[string]interface{}, sortFields []string, results interface{}) error {
+	return s.FindManyWithOptions(ctx, filter, contracts.FindOptions{Sort: sortFields}, results)
+}
+
+// FindManyWithOptions finds the records matching the filter, applying the given sort and limit.
+func (s *Store) FindManyWithOptions(ctx context.Context, filter map[string]interface{}, findOptions contracts.FindOptions, results interface{}) error {
 	bsonFilter := toBsonM(filter)
 	opts := options.Find()
 
-	if len(sortFields) > 0 {
-		sortDoc := bson.D{}
-		for _, field := range sortFields {
-			order := 1
-			if strings.HasPrefix(field, "-") {
-				order = -1
-				field = strings.TrimPrefix(field, "-")
-			}
-			sortDoc = append(sortDoc, bson.E{Key: field, Value: order})
-		}
-		opts.SetSort(sortDoc)
+	if len(findOptions.Sort) > 0 {
+		opts.SetSort(toSortDoc(findOptions.Sort))
+	}
+	if findOptions.Limit > 0 {
+		opts.SetLimit(findOptions.Limit)
 	}
 
 	cursor, err := s.collection.Find(ctx, bsonFilter, opts)
 	if err != nil {
-		return err
+		return wrapError(err)
 	}
 	defer cursor.Close(ctx)
 
-	return cursor.All(ctx, results)
+	return wrapError(cursor.All(ctx, results))
 }
 
 // InsertOne inserts a single record into the store.
 func (s *Store) InsertOne(ctx context.Context, record interface{}) error {
 	_, err := s.collection.InsertOne(ctx, record)
-	return err
+	return wrapError(err)
 }
 
 // CountRecords counts the number of records matching the filter.
 func (s *Store) CountRecords(ctx context.Context, filter map[string]interface{}) (int64, error) {
 	bsonFilter := toBsonM(filter)
-	return s.collection.CountDocuments(ctx, bsonFilter)
+	count, err := s.collection.CountDocuments(ctx, bsonFilter)
+	return count, wrapError(err)
 }
 
 // UpdateOne updates a single record matching the filter.
 func (s *Store) UpdateOne(ctx context.Context, filter map[string]interface{}, update map[string]interface{}) error {
 	bsonFilter := toBsonM(filter)
 	bsonUpdate := bson.M{"$set": toBsonM(update)}
-	_, err := s.collection.UpdateOne(ctx, bsonFilter, bsonUpdate)
-	return err
+	result, err := s.collection.UpdateOne(ctx, bsonFilter, bsonUpdate)
+	if err != nil {
+		return wrapError(err)
+	}
+	if result.MatchedCount == 0 {
+		return types.ErrNotFound{Message: "record not found"}
+	}
+	return nil
+}
+
+// UpdateOneWith applies a structured update to a single record matching the filter.
+func (s *Store) UpdateOneWith(ctx context.Context, filter map[string]interface{}, update contracts.Update) error {
+	bsonFilter := toBsonM(filter)
+	result, err := s.collection.UpdateOne(ctx, bsonFilter, toUpdateDoc(update))
+	if err != nil {
+		return wrapError(err)
+	}
+	if result.MatchedCount == 0 {
+		return types.ErrNotFound{Message: "record not found"}
+	}
+	return nil
+}
+
+// FindOneAndUpdate atomically applies the update to a single record matching the filter
+// and decodes the updated record into result.
+func (s *Store) FindOneAndUpdate(ctx context.Context, filter map[string]interface{}, update contracts.Update, findOptions contracts.FindOneAndUpdateOptions, result interface{}) error {
+	bsonFilter := toBsonM(filter)
+	opts := options.FindOneAndUpdate().
+		SetUpsert(findOptions.Upsert).
+		SetReturnDocument(options.After)
+	if len(findOptions.Sort) > 0 {
+		opts.SetSort(toSortDoc(findOptions.Sort))
+	}
+
+	err := s.collection.FindOneAndUpdate(ctx, bsonFilter, toUpdateDoc(update), opts).Decode(result)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return types.ErrNotFound{Message: "record not found"}
+		}
+		return wrapError(err)
+	}
+	return nil
+}
+
+// EnsureIndex creates the index if it does not exist yet.
+func (s *Store) EnsureIndex(ctx context.Context, index contracts.Index) error {
+	opts := options.Index()
+	if index.Unique {
+		opts.SetUnique(true)
+	}
+	if index.ExpireAfter != nil {
+		opts.SetExpireAfterSeconds(int32(index.ExpireAfter.Seconds()))
+	}
+
+	_, err := s.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    toSortDoc(index.Fields),
+		Options: opts,
+	})
+	return wrapError(err)
+}
+
+// DeleteOne deletes a single record matching the filter.
+func (s *Store) DeleteOne(ctx context.Context, filter map[string]interface{}) error {
+	bsonFilter := toBsonM(filter)
+	result, err := s.collection.DeleteOne(ctx, bsonFilter)
+	if err != nil {
+		return wrapError(err)
+	}
+	if result.DeletedCount == 0 {
+		return types.ErrNotFound{Message: "record not found"}
+	}
+	return nil
+}
+
+// DeleteMany deletes every record matching the filter and returns how many were deleted.
+func (s *Store) DeleteMany(ctx context.Context, filter map[string]interface{}) (int64, error) {
+	bsonFilter := toBsonM(filter)
+	result, err := s.collection.DeleteMany(ctx, bsonFilter)
+	if err != nil {
+		return 0, wrapError(err)
+	}
+	return result.DeletedCount, nil
+}
+
+// wrapError classifies driver errors: duplicate keys become conflicts and connectivity
+// failures become types.ErrUnavailable, so they are not mistaken for missing records.
+func wrapError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case mongo.IsDuplicateKeyError(err):
+		return types.ErrConflict{Message: "record already exists"}
+	case mongo.IsNetworkError(err), mongo.IsTimeout(err), errors.Is(err, mongo.ErrClientDisconnected):
+		return types.ErrUnavailable{Message: "database unavailable", Cause: err}
+	default:
+		return err
+	}
+}
+
+// toUpdateDoc converts a structured update into MongoDB update operators.
+func toUpdateDoc(update contracts.Update) bson.M {
+	doc := bson.M{}
+	if len(update.Set) > 0 {
+		doc["$set"] = toBsonM(update.Set)
+	}
+	if len(update.Push) > 0 {
+		doc["$push"] = toBsonM(update.Push)
+	}
+	if len(update.Unset) > 0 {
+		unset := bson.M{}
+		for _, field := range update.Unset {
+			unset[field] = ""
+		}
+		doc["$unset"] = unset
+	}
+	if len(update.Inc) > 0 {
+		doc["$inc"] = toBsonM(update.Inc)
+	}
+	if len(update.SetOnInsert) > 0 {
+		doc["$setOnInsert"] = toBsonM(update.SetOnInsert)
+	}
+	return doc
+}
+
+// toSortDoc converts field names, prefixed with "-" for descending order, into a sort document.
+func toSortDoc(sortFields []string) bson.D {
+	sortDoc := bson.D{}
+	for _, field := range sortFields {
+		order := 1
+		if strings.HasPrefix(field, "-") {
+			order = -1
+			field = strings.TrimPrefix(field, "-")
+		}
+		sortDoc = append(sortDoc, bson.E{Key: field, Value: order})
+	}
+	return sortDoc
 }
 
 // toBsonM converts a map[string]interface{} to bson.M.
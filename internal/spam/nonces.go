@@ -0,0 +1,53 @@
+package spam
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/mrthoabby/portfolio-api/internal/common/contracts"
+	"github.com/mrthoabby/portfolio-api/internal/common/types"
+)
+
+// FormTokensStore is the store remembering the form tokens already submitted
+const FormTokensStore = "form_tokens"
+
+// NonceStore remembers the nonces of the form tokens already submitted, so every token is
+// accepted once only
+type NonceStore interface {
+	// Use records nonce until expiresAt and reports false when it was recorded before
+	Use(ctx context.Context, nonce string, expiresAt time.Time) (bool, error)
+}
+
+// StoreNonces keeps used nonces in a shared store, so a token is spent on every instance.
+// They expire through a TTL index once the token could no longer be submitted anyway.
+type StoreNonces struct {
+	store contracts.Store
+}
+
+var _ NonceStore = (*StoreNonces)(nil)
+
+type usedNonce struct {
+	Nonce     string    `bson:"_id"`
+	ExpiresAt time.Time `bson:"expiresAt"`
+}
+
+// NewStoreNonces creates a nonce store keeping its records in the form_tokens store
+func NewStoreNonces(dataSource contracts.DataSource) *StoreNonces {
+	return &StoreNonces{store: dataSource.Store(FormTokensStore)}
+}
+
+// EnsureIndexes creates the TTL index that removes the nonces of expired tokens
+func (s *StoreNonces) EnsureIndexes(ctx context.Context) error {
+	expireAt := time.Duration(0)
+	return s.store.EnsureIndex(ctx, contracts.Index{Fields: []string{"expiresAt"}, ExpireAfter: &expireAt})
+}
+
+// Use implements NonceStore; the unique _id makes concurrent submissions of a token race safely
+func (s *StoreNonces) Use(ctx context.Context, nonce string, expiresAt time.Time) (bool, error) {
+	err := s.store.InsertOne(ctx, &usedNonce{Nonce: nonce, ExpiresAt: expiresAt})
+	if errors.Is(err, types.ErrConflict{}) {
+		return false, nil
+	}
+	return err == nil, err
+}
@@ -0,0 +1,44 @@
+package spam
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mrthoabby/portfolio-api/internal/common/contracts"
+	"github.com/mrthoabby/portfolio-api/internal/common/types"
+)
+
+// nonceStore rejects duplicate IDs like a unique index does
+type nonceStore struct {
+	contracts.Store
+	ids map[string]bool
+}
+
+func (s *nonceStore) InsertOne(_ context.Context, record interface{}) error {
+	nonce := record.(*usedNonce)
+	if s.ids[nonce.Nonce] {
+		return types.ErrConflict{Message: "record already exists"}
+	}
+	s.ids[nonce.Nonce] = true
+	return nil
+}
+
+func TestStoreNonces_Use(t *testing.T) {
+	nonces := &StoreNonces{store: &nonceStore{ids: map[string]bool{}}}
+	expiresAt := time.Now().Add(time.Hour)
+
+	fresh, err := nonces.Use(context.Background(), "nonce-1", expiresAt)
+	assert.NoError(t, err)
+	assert.True(t, fresh)
+
+	fresh, err = nonces.Use(context.Background(), "nonce-1", expiresAt)
+	assert.NoError(t, err)
+	assert.False(t, fresh)
+
+	fresh, err = nonces.Use(context.Background(), "nonce-2", expiresAt)
+	assert.NoError(t, err)
+	assert.True(t, fresh)
+}
@@ -0,0 +1,84 @@
+package spam
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Scoring weights of the content heuristics
+const (
+	// linkScore is added for every link beyond the first one
+	linkScore = 2
+	// repeatScore is added for every run of repeatedRunLength or more identical characters
+	repeatScore       = 2
+	repeatedRunLength = 6
+	// phraseScore is added for every known spam phrase found
+	phraseScore = 3
+)
+
+// DefaultPhrases are the spam phrases matched when none are configured
+var DefaultPhrases = []string{
+	"buy now",
+	"casino",
+	"crypto investment",
+	"click here",
+	"earn money fast",
+	"free money",
+	"guaranteed seo",
+	"limited time offer",
+	"make money online",
+	"viagra",
+	"work from home",
+	"100% free",
+}
+
+var linkPattern = regexp.MustCompile(`(?i)https?://|www\.|\[url[=\]]|href\s*=`)
+
+// contentScore rates how much text looks like spam and returns the reasons that contributed
+func contentScore(text string, phrases []string) (int, []string) {
+	score := 0
+	var reasons []string
+
+	if links := len(linkPattern.FindAllStringIndex(text, -1)); links > 1 {
+		score += (links - 1) * linkScore
+		reasons = append(reasons, ReasonLinks)
+	}
+
+	if runs := repeatedRuns(text); runs > 0 {
+		score += runs * repeatScore
+		reasons = append(reasons, ReasonRepeatedCharacters)
+	}
+
+	lower := strings.ToLower(text)
+	matched := false
+	for _, phrase := range phrases {
+		if phrase != "" && strings.Contains(lower, strings.ToLower(phrase)) {
+			score += phraseScore
+			matched = true
+		}
+	}
+	if matched {
+		reasons = append(reasons, ReasonPhrases)
+	}
+
+	return score, reasons
+}
+
+// repeatedRuns counts the runs of repeatedRunLength or more identical non-space characters
+func repeatedRuns(text string) int {
+	runs := 0
+	var previous rune = utf8.RuneError
+	length := 0
+	for _, char := range text {
+		if char == previous && char != ' ' {
+			length++
+		} else {
+			previous, length = char, 1
+		}
+		if length == repeatedRunLength {
+			runs++
+		}
+	}
+	return runs
+}
@@ -0,0 +1,192 @@
+// Package spam screens public form submissions before they are stored.
+//
+// A Filter combines three defenses: a honeypot field that humans never see, a signed single-use
+// token recording when the form was rendered so submissions that arrive too fast can be rejected,
+// and heuristic scoring of the submitted text. A missing, invalid or reused token adds to the
+// score rather than making the submission spam on its own, so clients that never fetch a token
+// keep working unless their text looks suspicious too.
+package spam
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/mrthoabby/portfolio-api/internal/common/types"
+)
+
+// Reasons reported in a Verdict
+const (
+	ReasonHoneypot           = "honeypot"
+	ReasonFormToken          = "form_token"
+	ReasonLinks              = "links"
+	ReasonRepeatedCharacters = "repeated_characters"
+	ReasonPhrases            = "phrases"
+)
+
+// Defaults used when the Config leaves a value unset
+const (
+	DefaultMinFillTime  = 3 * time.Second
+	DefaultFormTokenTTL = 24 * time.Hour
+	DefaultThreshold    = 5
+)
+
+// ErrTooFast is returned for submissions made sooner after the form was rendered than a person could
+var ErrTooFast = types.ErrValidation{Message: "form submitted too quickly, please wait a moment and try again"}
+
+// Config tunes a Filter
+type Config struct {
+	// Secret signs the form tokens; instances sharing it accept each other's tokens
+	Secret []byte
+	// MinFillTime is how long a person takes at least to fill in the form
+	MinFillTime time.Duration
+	// FormTokenTTL is how long a rendered form may be submitted
+	FormTokenTTL time.Duration
+	// Threshold is the content score from which a submission is spam
+	Threshold int
+	// FormTokenScore is added to the score of submissions without a valid, unused token; 0 ignores the token
+	FormTokenScore int
+	// Nonces makes tokens single-use; without it, a token can be reused until it expires
+	Nonces NonceStore
+	// Phrases are matched case-insensitively; DefaultPhrases when empty
+	Phrases []string
+}
+
+// Submission is a form submission as received, before any sanitization
+type Submission struct {
+	// Scope is what the form belongs to, e.g. the profile ID; tokens are only valid for their scope
+	Scope string
+	// Honeypot is the value of the hidden field, empty for people
+	Honeypot string
+	// FormToken is the token issued when the form was rendered
+	FormToken string
+	// Text is the free text to score, e.g. the name and message
+	Text []string
+	// Threshold overrides the filter threshold when positive
+	Threshold int
+}
+
+// Verdict is the outcome of screening a submission
+type Verdict struct {
+	Spam    bool
+	Score   int
+	Reasons []string
+}
+
+// Filter screens form submissions
+type Filter struct {
+	token       formToken
+	tokenScore  int
+	nonces      NonceStore
+	minFillTime time.Duration
+	threshold   int
+	phrases     []string
+	now         func() time.Time
+}
+
+// NewFilter creates a filter, applying the defaults for unset values
+func NewFilter(config Config) *Filter {
+	filter := &Filter{
+		token:       formToken{secret: config.Secret, ttl: config.FormTokenTTL},
+		tokenScore:  config.FormTokenScore,
+		nonces:      config.Nonces,
+		minFillTime: config.MinFillTime,
+		threshold:   config.Threshold,
+		phrases:     config.Phrases,
+		now:         time.Now,
+	}
+	if filter.token.ttl <= 0 {
+		filter.token.ttl = DefaultFormTokenTTL
+	}
+	if filter.minFillTime <= 0 {
+		filter.minFillTime = DefaultMinFillTime
+	}
+	if filter.threshold <= 0 {
+		filter.threshold = DefaultThreshold
+	}
+	if len(filter.phrases) == 0 {
+		filter.phrases = DefaultPhrases
+	}
+	return filter
+}
+
+// IssueFormToken returns the token a form rendered now must be submitted with
+func (f *Filter) IssueFormToken(scope string) (string, time.Time) {
+	now := f.now()
+	return f.token.issue(scope, now), now.Add(f.token.ttl)
+}
+
+// Check screens a submission. Submissions made too fast are rejected with ErrTooFast;
+// a filled honeypot or a score reaching the threshold make it spam. A missing, invalid or
+// already used token adds the configured token score.
+func (f *Filter) Check(ctx context.Context, submission Submission) (Verdict, error) {
+	var verdict Verdict
+
+	if submission.Honeypot != "" {
+		verdict.Spam = true
+		verdict.Reasons = append(verdict.Reasons, ReasonHoneypot)
+	}
+
+	now := f.now()
+	token, err := f.token.verify(submission.Scope, submission.FormToken, now)
+	if err == nil {
+		if now.Sub(token.renderedAt) < f.minFillTime {
+			return Verdict{}, ErrTooFast
+		}
+		err = f.spend(ctx, token)
+	}
+	if err != nil {
+		if !isTokenError(err) {
+			return Verdict{}, err
+		}
+		if f.tokenScore > 0 {
+			verdict.Score += f.tokenScore
+			verdict.Reasons = append(verdict.Reasons, ReasonFormToken)
+		}
+	}
+
+	for _, text := range submission.Text {
+		score, reasons := contentScore(text, f.phrases)
+		verdict.Score += score
+		verdict.Reasons = appendMissing(verdict.Reasons, reasons...)
+	}
+	threshold := f.threshold
+	if submission.Threshold > 0 {
+		threshold = submission.Threshold
+	}
+	if verdict.Score >= threshold {
+		verdict.Spam = true
+	}
+
+	return verdict, nil
+}
+
+// spend marks the token used, failing with errReusedToken when it was used before
+func (f *Filter) spend(ctx context.Context, token parsedFormToken) error {
+	if f.nonces == nil {
+		return nil
+	}
+	fresh, err := f.nonces.Use(ctx, token.nonce, token.renderedAt.Add(f.token.ttl))
+	if err != nil {
+		return err
+	}
+	if !fresh {
+		return errReusedToken
+	}
+	return nil
+}
+
+func isTokenError(err error) bool {
+	return errors.Is(err, errMalformedToken) || errors.Is(err, errInvalidToken) ||
+		errors.Is(err, errExpiredToken) || errors.Is(err, errReusedToken)
+}
+
+func appendMissing(list []string, items ...string) []string {
+	for _, item := range items {
+		if !slices.Contains(list, item) {
+			list = append(list, item)
+		}
+	}
+	return list
+}
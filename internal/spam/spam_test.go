@@ -0,0 +1,228 @@
+package spam
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testTime = time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func newTestFilter(now *time.Time) *Filter {
+	filter := NewFilter(Config{Secret: []byte("test-secret")})
+	filter.now = func() time.Time { return *now }
+	return filter
+}
+
+func TestFilter_Check(t *testing.T) {
+	now := testTime
+	filter := newTestFilter(&now)
+	token, expiresAt := filter.IssueFormToken("profile-1")
+	assert.Equal(t, testTime.Add(DefaultFormTokenTTL), expiresAt)
+	now = now.Add(time.Minute)
+
+	tests := []struct {
+		name       string
+		submission Submission
+		expected   Verdict
+	}{
+		{
+			name:       "legitimate",
+			submission: Submission{Scope: "profile-1", FormToken: token, Text: []string{"Ada", "I'd love to talk about your project at https://example.com"}},
+			expected:   Verdict{},
+		},
+		{
+			name:       "honeypot",
+			submission: Submission{Scope: "profile-1", FormToken: token, Honeypot: "https://spam.example", Text: []string{"Ada", "Hello there"}},
+			expected:   Verdict{Spam: true, Reasons: []string{ReasonHoneypot}},
+		},
+		{
+			name:       "missing token",
+			submission: Submission{Scope: "profile-1", Text: []string{"Ada", "Hello there"}},
+			expected:   Verdict{},
+		},
+		{
+			name:       "content over the threshold",
+			submission: Submission{Scope: "profile-1", FormToken: token, Text: []string{"Ada", "CLICK HERE http://a.example http://b.example"}},
+			expected:   Verdict{Spam: true, Score: 5, Reasons: []string{ReasonLinks, ReasonPhrases}},
+		},
+		{
+			name:       "content under the threshold",
+			submission: Submission{Scope: "profile-1", FormToken: token, Text: []string{"Ada", "Sooooooo good, see www.a.example and www.b.example"}},
+			expected:   Verdict{Score: 4, Reasons: []string{ReasonLinks, ReasonRepeatedCharacters}},
+		},
+		{
+			name:       "content over a lowered threshold",
+			submission: Submission{Scope: "profile-1", FormToken: token, Threshold: 4, Text: []string{"Ada", "Sooooooo good, see www.a.example and www.b.example"}},
+			expected:   Verdict{Spam: true, Score: 4, Reasons: []string{ReasonLinks, ReasonRepeatedCharacters}},
+		},
+		{
+			name:       "content under a raised threshold",
+			submission: Submission{Scope: "profile-1", FormToken: token, Threshold: 6, Text: []string{"Ada", "CLICK HERE http://a.example http://b.example"}},
+			expected:   Verdict{Score: 5, Reasons: []string{ReasonLinks, ReasonPhrases}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verdict, err := filter.Check(context.Background(), tt.submission)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, verdict)
+		})
+	}
+}
+
+func TestFilter_Check_FormTokenScore(t *testing.T) {
+	now := testTime
+	filter := newTestFilter(&now)
+	filter.tokenScore = 3
+	token, _ := filter.IssueFormToken("profile-1")
+	now = now.Add(time.Minute)
+
+	tests := []struct {
+		name       string
+		submission Submission
+		expected   Verdict
+	}{
+		{
+			name:       "valid token",
+			submission: Submission{Scope: "profile-1", FormToken: token, Text: []string{"Ada", "Hello there"}},
+			expected:   Verdict{},
+		},
+		{
+			name:       "missing token",
+			submission: Submission{Scope: "profile-1", Text: []string{"Ada", "Hello there"}},
+			expected:   Verdict{Score: 3, Reasons: []string{ReasonFormToken}},
+		},
+		{
+			name:       "token of another profile",
+			submission: Submission{Scope: "profile-2", FormToken: token, Text: []string{"Ada", "Hello there"}},
+			expected:   Verdict{Score: 3, Reasons: []string{ReasonFormToken}},
+		},
+		{
+			name:       "tampered token",
+			submission: Submission{Scope: "profile-1", FormToken: "1" + token, Text: []string{"Ada", "Hello there"}},
+			expected:   Verdict{Score: 3, Reasons: []string{ReasonFormToken}},
+		},
+		{
+			name:       "missing token and suspicious content",
+			submission: Submission{Scope: "profile-1", Text: []string{"Ada", "See www.a.example and www.b.example"}},
+			expected:   Verdict{Spam: true, Score: 5, Reasons: []string{ReasonFormToken, ReasonLinks}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verdict, err := filter.Check(context.Background(), tt.submission)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, verdict)
+		})
+	}
+}
+
+// memoryNonces remembers nonces for the lifetime of a test
+type memoryNonces map[string]time.Time
+
+func (m memoryNonces) Use(_ context.Context, nonce string, expiresAt time.Time) (bool, error) {
+	if _, used := m[nonce]; used {
+		return false, nil
+	}
+	m[nonce] = expiresAt
+	return true, nil
+}
+
+func TestFilter_Check_SingleUseTokens(t *testing.T) {
+	now := testTime
+	filter := newTestFilter(&now)
+	filter.tokenScore = 3
+	nonces := memoryNonces{}
+	filter.nonces = nonces
+	token, _ := filter.IssueFormToken("profile-1")
+	other, _ := filter.IssueFormToken("profile-1")
+	assert.NotEqual(t, token, other, "every rendered form gets its own token")
+
+	// Submitted too fast, the token is not spent and can be submitted again once the person is done
+	_, err := filter.Check(context.Background(), Submission{Scope: "profile-1", FormToken: token})
+	assert.ErrorIs(t, err, ErrTooFast)
+
+	now = now.Add(time.Minute)
+	verdict, err := filter.Check(context.Background(), Submission{Scope: "profile-1", FormToken: token})
+	assert.NoError(t, err)
+	assert.Equal(t, Verdict{}, verdict)
+	assert.Len(t, nonces, 1)
+	for _, expiresAt := range nonces {
+		assert.True(t, testTime.Add(DefaultFormTokenTTL).Equal(expiresAt), "nonces are kept as long as the token is valid")
+	}
+
+	verdict, err = filter.Check(context.Background(), Submission{Scope: "profile-1", FormToken: token})
+	assert.NoError(t, err)
+	assert.Equal(t, Verdict{Score: 3, Reasons: []string{ReasonFormToken}}, verdict, "a replayed token counts as missing")
+}
+
+func TestFilter_Check_TooFast(t *testing.T) {
+	now := testTime
+	filter := newTestFilter(&now)
+	token, _ := filter.IssueFormToken("profile-1")
+
+	now = now.Add(DefaultMinFillTime - time.Millisecond)
+	_, err := filter.Check(context.Background(), Submission{Scope: "profile-1", FormToken: token})
+	assert.True(t, errors.Is(err, ErrTooFast))
+
+	now = now.Add(time.Millisecond)
+	verdict, err := filter.Check(context.Background(), Submission{Scope: "profile-1", FormToken: token})
+	assert.NoError(t, err)
+	assert.False(t, verdict.Spam)
+}
+
+func TestFilter_Check_ExpiredToken(t *testing.T) {
+	now := testTime
+	filter := newTestFilter(&now)
+	filter.tokenScore = DefaultThreshold
+	token, _ := filter.IssueFormToken("profile-1")
+
+	now = now.Add(DefaultFormTokenTTL + time.Second)
+	verdict, err := filter.Check(context.Background(), Submission{Scope: "profile-1", FormToken: token})
+	assert.NoError(t, err)
+	assert.Equal(t, Verdict{Spam: true, Score: DefaultThreshold, Reasons: []string{ReasonFormToken}}, verdict)
+}
+
+func TestFilter_Check_TokensOfAnotherSecret(t *testing.T) {
+	now := testTime
+	issuer := newTestFilter(&now)
+	token, _ := issuer.IssueFormToken("profile-1")
+
+	other := NewFilter(Config{Secret: []byte("other-secret"), FormTokenScore: DefaultThreshold})
+	other.now = func() time.Time { return testTime.Add(time.Minute) }
+	verdict, err := other.Check(context.Background(), Submission{Scope: "profile-1", FormToken: token})
+	assert.NoError(t, err)
+	assert.True(t, verdict.Spam)
+}
+
+func TestContentScore(t *testing.T) {
+	tests := []struct {
+		name            string
+		text            string
+		expectedScore   int
+		expectedReasons []string
+	}{
+		{name: "plain", text: "Would you be available for a call next week?", expectedScore: 0},
+		{name: "single link", text: "My portfolio: https://example.com", expectedScore: 0},
+		{name: "many links", text: "https://a.example www.b.example [url=c.example]c[/url] <a href=\"d\">", expectedScore: 6, expectedReasons: []string{ReasonLinks}},
+		{name: "repeated characters", text: "!!!!!!!!!!!! hiiiiiii", expectedScore: 4, expectedReasons: []string{ReasonRepeatedCharacters}},
+		{name: "spaces are not repeated characters", text: "a          b", expectedScore: 0},
+		{name: "phrases", text: "Make Money Online with our CASINO", expectedScore: 6, expectedReasons: []string{ReasonPhrases}},
+		{name: "unicode", text: strings.Repeat("é", 6), expectedScore: 2, expectedReasons: []string{ReasonRepeatedCharacters}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score, reasons := contentScore(tt.text, DefaultPhrases)
+			assert.Equal(t, tt.expectedScore, score)
+			assert.Equal(t, tt.expectedReasons, reasons)
+		})
+	}
+}